@@ -13,6 +13,7 @@ import (
 	"github.com/loomnetwork/loomchain/log"
 	pv "github.com/loomnetwork/loomchain/privval"
 	hsmpv "github.com/loomnetwork/loomchain/privval/hsm"
+	remotepv "github.com/loomnetwork/loomchain/privval/remote"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	abci_server "github.com/tendermint/tendermint/abci/server"
@@ -109,6 +110,9 @@ type Backend interface {
 	// Returns the TCP or UNIX socket address the backend RPC server listens on
 	RPCAddress() (string, error)
 	EventBus() *types.EventBus // TODO: doesn't seem to be used, remove it
+	// Returns the fnConsensus reactor, or nil if the reactor is disabled or the backend hasn't been
+	// started yet.
+	FnConsensusReactor() *fnConsensus.FnConsensusReactor
 }
 
 type TendermintBackend struct {
@@ -120,7 +124,8 @@ type TendermintBackend struct {
 	socketServer      tmcmn.Service
 	genesisValidators []*loom.Validator
 
-	FnRegistry fnConsensus.FnRegistry
+	FnRegistry         fnConsensus.FnRegistry
+	fnConsensusReactor *fnConsensus.FnConsensusReactor
 }
 
 // ParseConfig retrieves the default environment configuration,
@@ -164,6 +169,7 @@ type OverrideConfig struct {
 	CreateEmptyBlocks        bool
 	MempoolWalEnabled        bool
 	HsmConfig                *hsmpv.HsmConfig
+	RemoteSignerConfig       *remotepv.RemoteSignerConfig
 	FnConsensusReactorConfig *fnConsensus.ReactorConfigParsable
 }
 
@@ -185,7 +191,9 @@ func (b *TendermintBackend) Init() (*loom.Validator, error) {
 		return nil, errors.New("private validator file already exists")
 	}
 
-	privValidator, err := pv.GenPrivVal(privValFile, b.OverrideCfg.HsmConfig)
+	privValidator, err := pv.GenPrivVal(
+		privValFile, b.OverrideCfg.HsmConfig, b.OverrideCfg.RemoteSignerConfig,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -233,6 +241,34 @@ func (b *TendermintBackend) GenesisValidators() []*loom.Validator {
 	return b.genesisValidators
 }
 
+// LoadGenesisValidators loads the validators list from the genesis file without starting the node,
+// Start loads the list automatically so this only needs to be called by tools that execute blocks
+// without a running node.
+func (b *TendermintBackend) LoadGenesisValidators() error {
+	cfg, err := b.parseConfig()
+	if err != nil {
+		return err
+	}
+	return b.loadGenesisValidators(cfg)
+}
+
+func (b *TendermintBackend) loadGenesisValidators(conf *cfg.Config) error {
+	genDoc, err := types.GenesisDocFromFile(conf.GenesisFile())
+	if err != nil {
+		return err
+	}
+	validators := make([]*loom.Validator, 0)
+	for _, validator := range genDoc.Validators {
+		pubKey := [ed25519.PubKeyEd25519Size]byte(validator.PubKey.(ed25519.PubKeyEd25519))
+		validators = append(validators, &loom.Validator{
+			PubKey: pubKey[:],
+			Power:  validator.Power,
+		})
+	}
+	b.genesisValidators = validators
+	return nil
+}
+
 // IsValidator checks if the node is currently a validator.
 func (b *TendermintBackend) IsValidator() bool {
 	privVal := b.node.PrivValidator()
@@ -268,7 +304,9 @@ func (b *TendermintBackend) Reset(height uint64) error {
 	if err != nil {
 		return err
 	}
-	privVal, err := pv.LoadPrivVal(cfg.PrivValidatorFile(), b.OverrideCfg.HsmConfig)
+	privVal, err := pv.LoadPrivVal(
+		cfg.PrivValidatorFile(), b.OverrideCfg.HsmConfig, b.OverrideCfg.RemoteSignerConfig,
+	)
 	if err != nil {
 		return err
 	}
@@ -311,7 +349,9 @@ func (b *TendermintBackend) NodeSigner() (auth.Signer, error) {
 		return nil, err
 	}
 
-	privVal, err := pv.LoadPrivVal(cfg.PrivValidatorFile(), b.OverrideCfg.HsmConfig)
+	privVal, err := pv.LoadPrivVal(
+		cfg.PrivValidatorFile(), b.OverrideCfg.HsmConfig, b.OverrideCfg.RemoteSignerConfig,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -366,25 +406,16 @@ func (b *TendermintBackend) Start(app abci.Application) error {
 	}
 	logger := log.NewTMFilter(log.Root, levelOpt)
 	cfg.BaseConfig.LogLevel = b.OverrideCfg.LogLevel
-	privVal, err := pv.LoadPrivVal(cfg.PrivValidatorFile(), b.OverrideCfg.HsmConfig)
+	privVal, err := pv.LoadPrivVal(
+		cfg.PrivValidatorFile(), b.OverrideCfg.HsmConfig, b.OverrideCfg.RemoteSignerConfig,
+	)
 	if err != nil {
 		return err
 	}
 
-	//Load genesis validators
-	genDoc, err := types.GenesisDocFromFile(cfg.GenesisFile())
-	if err != nil {
+	if err := b.loadGenesisValidators(cfg); err != nil {
 		return err
 	}
-	validators := make([]*loom.Validator, 0)
-	for _, validator := range genDoc.Validators {
-		pubKey := [ed25519.PubKeyEd25519Size]byte(validator.PubKey.(ed25519.PubKeyEd25519))
-		validators = append(validators, &loom.Validator{
-			PubKey: pubKey[:],
-			Power:  validator.Power,
-		})
-	}
-	b.genesisValidators = validators
 
 	if !cmn.FileExists(cfg.NodeKeyFile()) {
 		return errors.New("failed to locate local node p2p key file")
@@ -425,6 +456,7 @@ func (b *TendermintBackend) Start(app abci.Application) error {
 			Name:    "FNCONSENSUS",
 			Reactor: fnConsensusReactor,
 		})
+		b.fnConsensusReactor = fnConsensusReactor
 	}
 
 	if b.SocketPath != "" {
@@ -464,6 +496,10 @@ func (b *TendermintBackend) EventBus() *types.EventBus {
 	return b.node.EventBus()
 }
 
+func (b *TendermintBackend) FnConsensusReactor() *fnConsensus.FnConsensusReactor {
+	return b.fnConsensusReactor
+}
+
 func (b *TendermintBackend) RunForever() {
 	cmn.TrapSignal(func() {
 		if (b.node != nil) && b.node.IsRunning() {
@@ -24,6 +24,7 @@ import (
 	"github.com/loomnetwork/loomchain/store"
 	blockindex "github.com/loomnetwork/loomchain/store/block_index"
 	evmaux "github.com/loomnetwork/loomchain/store/evm_aux"
+	"github.com/pkg/errors"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/common"
@@ -48,6 +49,8 @@ type State interface {
 	Context() context.Context
 	WithContext(ctx context.Context) State
 	WithPrefix(prefix []byte) State
+	// WithStore returns a copy of the state that reads from & writes to the given store.
+	WithStore(kvStore store.KVStore) State
 	SetFeature(string, bool)
 	SetMinBuildNumber(uint64)
 	ChangeConfigSetting(name, value string) error
@@ -250,6 +253,16 @@ func (s *StoreState) WithPrefix(prefix []byte) State {
 	}
 }
 
+func (s *StoreState) WithStore(kvStore store.KVStore) State {
+	return &StoreState{
+		store:           kvStore,
+		block:           s.block,
+		ctx:             s.ctx,
+		validators:      s.validators,
+		getValidatorSet: s.getValidatorSet,
+	}
+}
+
 func (s *StoreState) Release() {
 	// noop
 }
@@ -311,6 +324,8 @@ type TxHandlerResult struct {
 	Data             []byte
 	ValidatorUpdates []abci.Validator
 	Info             string
+	// Amount of gas used by the tx, currently only set for Go contract txs when gas metering is enabled.
+	GasUsed uint64
 	// Tags to associate with the tx that produced this result. Tags can be used to filter txs
 	// via the ABCI query interface (see https://godoc.org/github.com/tendermint/tendermint/libs/pubsub/query)
 	Tags []common.KVPair
@@ -320,6 +335,28 @@ func (f TxHandlerFunc) ProcessTx(state State, txBytes []byte, isCheckTx bool) (T
 	return f(state, txBytes, isCheckTx)
 }
 
+// Response codes returned by CheckTx & DeliverTx for failed txs.
+const (
+	// CodeTypeTxFailed is returned for txs that fail for any reason that doesn't have a more specific code.
+	CodeTypeTxFailed uint32 = 1
+	// CodeTypeTxExpired is returned for txs that are processed after the expiry height or time
+	// specified by the signer.
+	CodeTypeTxExpired uint32 = 2
+)
+
+// TxErrorCoder can be implemented by errors returned from tx handlers to override the response code
+// returned by CheckTx & DeliverTx when a tx fails.
+type TxErrorCoder interface {
+	TxErrorCode() uint32
+}
+
+func txErrorCode(err error) uint32 {
+	if coder, ok := errors.Cause(err).(TxErrorCoder); ok {
+		return coder.TxErrorCode()
+	}
+	return CodeTypeTxFailed
+}
+
 type QueryHandler interface {
 	Handle(state ReadOnlyState, path string, data []byte) ([]byte, error)
 }
@@ -372,6 +409,10 @@ type Application struct {
 	childTxRefs                 []evmaux.ChildTxRef // links Tendermint txs to EVM txs
 	ReceiptsVersion             int32
 	committedTxs                []CommittedTx
+	// Used to re-execute committed txs (e.g. when tracing them), may be nil.
+	CreateReplayTxHandler ReplayTxHandlerFactoryFunc
+	// Used to simulate txs without committing them, may be nil.
+	CreateSimulateTxHandler SimulateTxHandlerFactoryFunc
 }
 
 var _ abci.Application = &Application{}
@@ -668,7 +709,7 @@ func (a *Application) CheckTx(txBytes []byte) abci.ResponseCheckTx {
 	_, err = a.TxHandler.ProcessTx(state, txBytes, true)
 	if err != nil {
 		log.Error("CheckTx", "tx", hex.EncodeToString(ttypes.Tx(txBytes).Hash()), "err", err)
-		return abci.ResponseCheckTx{Code: 1, Log: err.Error()}
+		return abci.ResponseCheckTx{Code: txErrorCode(err), Log: err.Error()}
 	}
 
 	return abci.ResponseCheckTx{Code: abci.CodeTypeOK}
@@ -716,9 +757,11 @@ func (a *Application) deliverTx(storeTx store.KVStoreTx, txBytes []byte) abci.Re
 	r, err := a.processTx(storeTx, txBytes, false)
 	if err != nil {
 		log.Error("DeliverTx", "tx", hex.EncodeToString(ttypes.Tx(txBytes).Hash()), "err", err)
-		return abci.ResponseDeliverTx{Code: 1, Log: err.Error()}
+		return abci.ResponseDeliverTx{Code: txErrorCode(err), Log: err.Error(), GasUsed: int64(r.GasUsed)}
+	}
+	return abci.ResponseDeliverTx{
+		Code: abci.CodeTypeOK, Data: r.Data, Tags: r.Tags, Info: r.Info, GasUsed: int64(r.GasUsed),
 	}
-	return abci.ResponseDeliverTx{Code: abci.CodeTypeOK, Data: r.Data, Tags: r.Tags, Info: r.Info}
 }
 
 func (a *Application) processTx(storeTx store.KVStoreTx, txBytes []byte, isCheckTx bool) (TxHandlerResult, error) {
@@ -810,7 +853,9 @@ func (a *Application) deliverTx2(storeTx store.KVStoreTx, txBytes []byte) abci.R
 		// FIXME: Really shouldn't be using r.Data if txErr != nil, but need to refactor TxHandler.ProcessTx
 		//        so it only returns r with the correct status code & log fields.
 		// Pass the EVM tx hash (if any) back to Tendermint so it stores it in block results
-		return abci.ResponseDeliverTx{Code: 1, Data: r.Data, Log: txErr.Error()}
+		return abci.ResponseDeliverTx{
+			Code: txErrorCode(txErr), Data: r.Data, Log: txErr.Error(), GasUsed: int64(r.GasUsed),
+		}
 	}
 
 	a.EventHandler.Commit(uint64(a.curBlockHeader.GetHeight()))
@@ -821,7 +866,9 @@ func (a *Application) deliverTx2(storeTx store.KVStoreTx, txBytes []byte) abci.R
 		txHash: receiptTxHash,
 	})
 
-	return abci.ResponseDeliverTx{Code: abci.CodeTypeOK, Data: r.Data, Tags: r.Tags, Info: r.Info}
+	return abci.ResponseDeliverTx{
+		Code: abci.CodeTypeOK, Data: r.Data, Tags: r.Tags, Info: r.Info, GasUsed: int64(r.GasUsed),
+	}
 }
 
 // Commit commits the current block
@@ -846,6 +893,12 @@ func (a *Application) Commit() abci.ResponseCommit {
 	}
 	a.childTxRefs = nil
 
+	if a.EvmAuxStore != nil {
+		if err := a.EvmAuxStore.SetStateRoot(store.GetEvmStateRoot(a.Store), uint64(height)); err != nil {
+			log.Error("Failed to save EVM state root", "height", height, "err", err)
+		}
+	}
+
 	// Update the index before emitting events in case the subscribers attempt to lookup the
 	// block by number as soon as they receive an event.
 	if a.BlockIndexStore != nil {
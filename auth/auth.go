@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -12,8 +13,10 @@ import (
 	"golang.org/x/crypto/ed25519"
 
 	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/types"
 	"github.com/loomnetwork/go-loom/util"
 	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
 	"github.com/loomnetwork/loomchain/store"
 )
 
@@ -39,8 +42,14 @@ func (c contextKey) String() string {
 var (
 	ContextKeyOrigin  = contextKey("origin")
 	ContextKeyCheckTx = contextKey("CheckTx")
+	ContextKeySponsor = contextKey("sponsor")
+
+	contextKeyNonceLane = contextKey("nonce-lane")
 )
 
+// DefaultNonceLane is the nonce lane used by txs that don't specify a lane.
+const DefaultNonceLane uint32 = 0
+
 func Origin(ctx context.Context) loom.Address {
 	return ctx.Value(ContextKeyOrigin).(loom.Address)
 }
@@ -72,6 +81,25 @@ var SignatureTxMiddleware = loomchain.TxMiddlewareFunc(func(
 	return next(state.WithContext(ctx), tx.Inner, isCheckTx)
 })
 
+// NewUnsignedTxMiddleware returns a middleware that processes unsigned txs (serialized NonceTx) as if
+// they were signed by the given origin. It must only be used in place of the signature middleware
+// when simulating txs, never when processing txs that will be committed.
+func NewUnsignedTxMiddleware(origin loom.Address) loomchain.TxMiddlewareFunc {
+	return loomchain.TxMiddlewareFunc(func(
+		state loomchain.State,
+		txBytes []byte,
+		next loomchain.TxHandlerFunc,
+		isCheckTx bool,
+	) (loomchain.TxHandlerResult, error) {
+		ctmp := state.Context()
+		if ctmp == nil {
+			ctmp = context.Background()
+		}
+		ctx := context.WithValue(ctmp, ContextKeyOrigin, origin)
+		return next(state.WithContext(ctx), txBytes, isCheckTx)
+	})
+}
+
 func GetOrigin(tx SignedTx, chainId string) (loom.Address, error) {
 	if len(tx.PublicKey) != ed25519.PublicKeySize {
 		return loom.Address{}, errors.New("invalid public key length")
@@ -95,12 +123,44 @@ func nonceKey(addr loom.Address) []byte {
 	return util.PrefixKey([]byte("nonce"), addr.Bytes())
 }
 
+// nonceLaneKey returns the key of the sequence for the given nonce lane, the default lane is stored
+// under the same key as the nonce of an account that has never used any other lanes.
+func nonceLaneKey(addr loom.Address, lane uint32) []byte {
+	if lane == DefaultNonceLane {
+		return nonceKey(addr)
+	}
+	laneBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(laneBytes, lane)
+	return util.PrefixKey([]byte("lane_nonce"), addr.Bytes(), laneBytes)
+}
+
+// nonceCacheKey returns the key used to cache the next nonce expected for the given lane.
+func nonceCacheKey(addr loom.Address, lane uint32) string {
+	if lane == DefaultNonceLane {
+		return addr.String()
+	}
+	return fmt.Sprintf("%s/%d", addr.String(), lane)
+}
+
+// Nonce returns the current nonce of the given account in the default nonce lane.
 func Nonce(state loomchain.ReadOnlyState, addr loom.Address) uint64 {
-	return loomchain.NewSequence(nonceKey(addr)).Value(state)
+	return NonceOnLane(state, addr, DefaultNonceLane)
+}
+
+// NonceOnLane returns the current nonce of the given account in the given nonce lane.
+func NonceOnLane(state loomchain.ReadOnlyState, addr loom.Address, lane uint32) uint64 {
+	return loomchain.NewSequence(nonceLaneKey(addr, lane)).Value(state)
+}
+
+// NonceLane returns the nonce lane of the tx currently being processed.
+func NonceLane(ctx context.Context) uint32 {
+	lane, _ := ctx.Value(contextKeyNonceLane).(uint32)
+	return lane
 }
 
 type NonceHandler struct {
-	nonceCache map[string]uint64 // stores the next nonce expected to be seen for each account
+	// stores the next nonce expected to be seen for each account (and nonce lane)
+	nonceCache map[string]uint64
 	lastHeight int64
 }
 
@@ -125,24 +185,41 @@ func (n *NonceHandler) Nonce(
 		// Clear the cache for each block
 		n.nonceCache = make(map[string]uint64)
 	}
-	var seq uint64
 
+	// ExtendedNonceTx is wire compatible with NonceTx, the expiry fields are only checked if the
+	// tx expiry feature is enabled, and the lane is ignored unless the nonce lanes feature is enabled.
+	var tx ExtendedNonceTx
+	err := proto.Unmarshal(txBytes, &tx)
+	if err != nil {
+		return r, err
+	}
+
+	lane := DefaultNonceLane
+	if state.FeatureEnabled(features.NonceLanesFeature, false) && tx.Lane != DefaultNonceLane {
+		// Contract addresses are derived from the nonce in the default lane, so deploying contracts
+		// from any other lane could result in address collisions.
+		var innerTx types.Transaction
+		if err := proto.Unmarshal(tx.Inner, &innerTx); err != nil {
+			return r, err
+		}
+		if types.TxID(innerTx.Id) == types.TxID_DEPLOY {
+			return r, errors.New("contracts can only be deployed from the default nonce lane")
+		}
+		lane = tx.Lane
+	}
+
+	var seq uint64
 	incrementNonceOnFailedTx := state.Config().GetNonceHandler().GetIncNonceOnFailedTx()
 	if incrementNonceOnFailedTx && !isCheckTx {
 		// Unconditionally increment the nonce in DeliverTx, regardless of whether the tx succeeds
-		seq = loomchain.NewSequence(nonceKey(origin)).Next(kvStore)
+		seq = loomchain.NewSequence(nonceLaneKey(origin, lane)).Next(kvStore)
 	} else {
-		seq = loomchain.NewSequence(nonceKey(origin)).Next(state)
-	}
-
-	var tx NonceTx
-	err := proto.Unmarshal(txBytes, &tx)
-	if err != nil {
-		return r, err
+		seq = loomchain.NewSequence(nonceLaneKey(origin, lane)).Next(state)
 	}
 
 	//TODO nonce cache is temporary until we have a separate atomic state for the entire checktx flow
-	cacheSeq := n.nonceCache[origin.String()]
+	cacheKey := nonceCacheKey(origin, lane)
+	cacheSeq := n.nonceCache[cacheKey]
 	// The client may speculatively increment nonces without waiting for previous txs to be committed,
 	// so it's possible for a single account to submit multiple transactions in a single block.
 	if cacheSeq != 0 && isCheckTx {
@@ -151,15 +228,15 @@ func (n *NonceHandler) Nonce(
 	} else {
 		if incrementNonceOnFailedTx {
 			if isCheckTx {
-				n.nonceCache[origin.String()] = seq
+				n.nonceCache[cacheKey] = seq
 			} else {
 				// In DeliverTx we update the cache unconditionally, because even if the tx fails the
 				// nonce change will be persisted. We do this here because post commit middleware doesn't
 				// run for failed txs, so IncNonce can't be relied upon.
-				n.nonceCache[origin.String()] = seq + 1
+				n.nonceCache[cacheKey] = seq + 1
 			}
 		} else {
-			n.nonceCache[origin.String()] = seq
+			n.nonceCache[cacheKey] = seq
 		}
 	}
 
@@ -168,7 +245,15 @@ func (n *NonceHandler) Nonce(
 		return r, fmt.Errorf("sequence number does not match expected %d got %d", seq, tx.Sequence)
 	}
 
-	return next(state, tx.Inner, isCheckTx)
+	if state.FeatureEnabled(features.TxExpiryFeature, false) {
+		if err := checkTxExpiry(state, &tx, isCheckTx); err != nil {
+			return r, err
+		}
+	}
+
+	// IncNonce needs to know which lane's cached nonce to update
+	ctx := context.WithValue(state.Context(), contextKeyNonceLane, lane)
+	return next(state.WithContext(ctx), tx.Inner, isCheckTx)
 }
 
 func (n *NonceHandler) IncNonce(
@@ -185,12 +270,13 @@ func (n *NonceHandler) IncNonce(
 
 	// We only increment the nonce if the transaction is successful
 	// There are situations in checktx where we may not have committed the transaction to the statestore yet
+	cacheKey := nonceCacheKey(origin, NonceLane(state.Context()))
 	if state.Config().GetNonceHandler().GetIncNonceOnFailedTx() {
 		if isCheckTx {
-			n.nonceCache[origin.String()] = n.nonceCache[origin.String()] + 1
+			n.nonceCache[cacheKey] = n.nonceCache[cacheKey] + 1
 		}
 	} else {
-		n.nonceCache[origin.String()] = n.nonceCache[origin.String()] + 1
+		n.nonceCache[cacheKey] = n.nonceCache[cacheKey] + 1
 	}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	proto "github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/require"
@@ -13,7 +14,9 @@ import (
 	loom "github.com/loomnetwork/go-loom"
 	"github.com/loomnetwork/go-loom/auth"
 	"github.com/loomnetwork/go-loom/config"
+	"github.com/loomnetwork/go-loom/types"
 	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
 	"github.com/loomnetwork/loomchain/store"
 )
 
@@ -221,3 +224,124 @@ func TestRevertedTxNonceMiddleware(t *testing.T) {
 	currentNonce = Nonce(state, origin)
 	require.Equal(t, uint64(2), currentNonce)
 }
+
+func TestNonceTxExpiry(t *testing.T) {
+	nonceTxHandler := NewNonceHandler()
+	pubkey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	origin := loom.Address{
+		ChainID: "default",
+		Local:   loom.LocalAddressFromPublicKey(pubkey),
+	}
+	ctx := context.WithValue(context.Background(), ContextKeyOrigin, origin)
+	blockTime := time.Unix(1000, 0)
+	noopHandler := func(state loomchain.State, txBytes []byte, isCheckTx bool) (loomchain.TxHandlerResult, error) {
+		return loomchain.TxHandlerResult{}, nil
+	}
+
+	nonceTxBytes, err := proto.Marshal(&ExtendedNonceTx{
+		Inner:            []byte{},
+		Sequence:         1,
+		ValidUntilHeight: 10,
+	})
+	require.NoError(t, err)
+
+	// Expiry is ignored until the feature is enabled
+	state := loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 11, Time: blockTime}, nil, nil)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), nonceTxBytes, noopHandler, false)
+	require.NoError(t, err)
+
+	state = loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 10, Time: blockTime}, nil, nil)
+	state.SetFeature(features.TxExpiryFeature, true)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), nonceTxBytes, noopHandler, false)
+	require.NoError(t, err)
+
+	// In CheckTx the tx must still be valid in the next block
+	state = loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 10, Time: blockTime}, nil, nil)
+	state.SetFeature(features.TxExpiryFeature, true)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), nonceTxBytes, noopHandler, true)
+	require.Equal(t, TxExpiredError{ValidUntilHeight: 10}, err)
+
+	state = loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 11, Time: blockTime}, nil, nil)
+	state.SetFeature(features.TxExpiryFeature, true)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), nonceTxBytes, noopHandler, false)
+	require.Equal(t, TxExpiredError{ValidUntilHeight: 10}, err)
+	require.Equal(t, loomchain.CodeTypeTxExpired, err.(loomchain.TxErrorCoder).TxErrorCode())
+
+	nonceTxBytes, err = proto.Marshal(&ExtendedNonceTx{
+		Inner:          []byte{},
+		Sequence:       1,
+		ValidUntilTime: blockTime.Unix() - 1,
+	})
+	require.NoError(t, err)
+	state = loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 12, Time: blockTime}, nil, nil)
+	state.SetFeature(features.TxExpiryFeature, true)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), nonceTxBytes, noopHandler, false)
+	require.Equal(t, TxExpiredError{ValidUntilTime: blockTime.Unix() - 1}, err)
+}
+
+func TestNonceLanes(t *testing.T) {
+	nonceTxHandler := NewNonceHandler()
+	pubkey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	origin := loom.Address{
+		ChainID: "default",
+		Local:   loom.LocalAddressFromPublicKey(pubkey),
+	}
+	ctx := context.WithValue(context.Background(), ContextKeyOrigin, origin)
+	cfg := config.DefaultConfig()
+	cfg.NonceHandler.IncNonceOnFailedTx = false
+	// Increment the cached nonce the same way the post commit middleware would
+	incNonceHandler := func(state loomchain.State, txBytes []byte, isCheckTx bool) (loomchain.TxHandlerResult, error) {
+		r := loomchain.TxHandlerResult{}
+		return r, nonceTxHandler.IncNonce(state, txBytes, r, nil, isCheckTx)
+	}
+	laneTx := func(lane uint32, seq uint64) []byte {
+		txBytes, err := proto.Marshal(&ExtendedNonceTx{Inner: []byte{}, Sequence: seq, Lane: lane})
+		require.NoError(t, err)
+		return txBytes
+	}
+
+	// Lanes are ignored until the feature is enabled
+	state := loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 1}, nil, nil).WithOnChainConfig(cfg)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(3, 1), incNonceHandler, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), Nonce(state, origin))
+	require.Equal(t, uint64(0), NonceOnLane(state, origin, 3))
+
+	state = loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 2}, nil, nil).WithOnChainConfig(cfg)
+	state.SetFeature(features.NonceLanesFeature, true)
+
+	// Each lane is ordered independently, in CheckTx the cached nonce of one lane shouldn't affect
+	// any other lane.
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(1, 1), incNonceHandler, true)
+	require.NoError(t, err)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(1, 2), incNonceHandler, true)
+	require.NoError(t, err)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(2, 1), incNonceHandler, true)
+	require.NoError(t, err)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(DefaultNonceLane, 1), incNonceHandler, true)
+	require.NoError(t, err)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(2, 3), incNonceHandler, true)
+	require.Error(t, err)
+
+	state = loomchain.NewStoreState(ctx, store.NewMemStore(), abci.Header{Height: 3}, nil, nil).WithOnChainConfig(cfg)
+	state.SetFeature(features.NonceLanesFeature, true)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(1, 1), incNonceHandler, false)
+	require.NoError(t, err)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(1, 2), incNonceHandler, false)
+	require.NoError(t, err)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), laneTx(2, 1), incNonceHandler, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), Nonce(state, origin))
+	require.Equal(t, uint64(2), NonceOnLane(state, origin, 1))
+	require.Equal(t, uint64(1), NonceOnLane(state, origin, 2))
+
+	// Contracts can only be deployed from the default lane
+	deployTxBytes, err := proto.Marshal(&types.Transaction{Id: uint32(types.TxID_DEPLOY)})
+	require.NoError(t, err)
+	nonceTxBytes, err := proto.Marshal(&ExtendedNonceTx{Inner: deployTxBytes, Sequence: 2, Lane: 2})
+	require.NoError(t, err)
+	_, err = nonceTxHandler.Nonce(state, store.NewMemStore(), nonceTxBytes, incNonceHandler, false)
+	require.Error(t, err)
+}
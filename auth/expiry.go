@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/loomnetwork/loomchain"
+)
+
+// TxExpiredError is returned by the nonce middleware for txs that are processed after the expiry
+// height or time specified by the signer.
+type TxExpiredError struct {
+	ValidUntilHeight int64
+	ValidUntilTime   int64
+}
+
+func (e TxExpiredError) Error() string {
+	return fmt.Sprintf("tx expired (valid until height %d, time %d)", e.ValidUntilHeight, e.ValidUntilTime)
+}
+
+// TxErrorCode implements loomchain.TxErrorCoder so clients can distinguish expired txs.
+func (e TxExpiredError) TxErrorCode() uint32 {
+	return loomchain.CodeTypeTxExpired
+}
+
+// checkTxExpiry returns TxExpiredError if the tx can no longer be included in a block.
+// In CheckTx the tx is checked against the height of the next block, since that's the earliest block
+// the tx can be included in. Tendermint rechecks the txs in the mempool after each block is committed,
+// so this ensures txs are evicted from the mempool as soon as they can no longer be included.
+func checkTxExpiry(state loomchain.State, tx *ExtendedNonceTx, isCheckTx bool) error {
+	height := state.Block().Height
+	if isCheckTx {
+		height++
+	}
+	if (tx.ValidUntilHeight > 0 && height > tx.ValidUntilHeight) ||
+		(tx.ValidUntilTime > 0 && state.Block().Time > tx.ValidUntilTime) {
+		return TxExpiredError{
+			ValidUntilHeight: tx.ValidUntilHeight,
+			ValidUntilTime:   tx.ValidUntilTime,
+		}
+	}
+	return nil
+}
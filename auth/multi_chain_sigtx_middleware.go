@@ -88,7 +88,7 @@ func NewMultiChainSignatureTxMiddleware(
 			return r, fmt.Errorf("unknown chain ID %s", msgSender.ChainID)
 		}
 
-		recoverOrigin := getOriginRecoveryFunc(state, types.TxID(tx.Id), chain.TxType)
+		recoverOrigin := getOriginRecoveryFunc(state, signedTx, types.TxID(tx.Id), chain.TxType)
 		if recoverOrigin == nil {
 			return r, fmt.Errorf("recovery function for Tx type %v not found", chain.TxType)
 		}
@@ -146,9 +146,15 @@ func NewMultiChainSignatureTxMiddleware(
 	})
 }
 
-func getOriginRecoveryFunc(state loomchain.State, txID types.TxID, txType SignedTxType) originRecoveryFunc {
+func getOriginRecoveryFunc(
+	state loomchain.State, signedTx SignedTx, txID types.TxID, txType SignedTxType,
+) originRecoveryFunc {
 	switch txType {
 	case LoomSignedTxType:
+		// ed25519 signed txs always include the public key, txs signed by multisig accounts don't
+		if len(signedTx.PublicKey) == 0 && state.FeatureEnabled(features.MultiSigAccountFeature, false) {
+			return verifyMultiSig
+		}
 		return verifyEd25519
 	case EthereumSignedTxType:
 		if (txID == types.TxID_ETHEREUM) && state.FeatureEnabled(features.EthTxFeature, false) {
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/common/evmcompat"
+	"github.com/pkg/errors"
+)
+
+// MaxMultiSigKeys is the max number of keys a multisig account can have.
+const MaxMultiSigKeys = 16
+
+const localAddressLength = 20
+
+var multiSigAddressPrefix = []byte("multisig:")
+
+// Signature types accepted from each type of key in a multisig account.
+var multiSigAllowedSigTypes = map[SignedTxType][]evmcompat.SignatureType{
+	EthereumSignedTxType: {
+		evmcompat.SignatureType_EIP712,
+		evmcompat.SignatureType_GETH,
+		evmcompat.SignatureType_TREZOR,
+	},
+	TronSignedTxType:    {evmcompat.SignatureType_TRON},
+	BinanceSignedTxType: {evmcompat.SignatureType_BINANCE},
+}
+
+// NewMultiSigAccount creates a multisig account from the given keys & threshold, the keys are sorted
+// by address to match the order expected by ValidateMultiSigAccount.
+func NewMultiSigAccount(keys []*MultiSigKey, threshold uint32) (*MultiSigAccount, error) {
+	sortedKeys := make([]*MultiSigKey, len(keys))
+	copy(sortedKeys, keys)
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		return bytes.Compare(sortedKeys[i].Local, sortedKeys[j].Local) < 0
+	})
+	account := &MultiSigAccount{
+		Keys:      sortedKeys,
+		Threshold: threshold,
+	}
+	if err := ValidateMultiSigAccount(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// ValidateMultiSigAccount checks the given multisig account is well formed, the keys in the account
+// must be sorted by address so that each set of keys & threshold maps to exactly one address.
+func ValidateMultiSigAccount(account *MultiSigAccount) error {
+	if account == nil {
+		return errors.New("multisig account not specified")
+	}
+	if len(account.Keys) == 0 || len(account.Keys) > MaxMultiSigKeys {
+		return errors.Errorf("multisig account must have between 1 and %d keys", MaxMultiSigKeys)
+	}
+	if account.Threshold == 0 || int(account.Threshold) > len(account.Keys) {
+		return errors.Errorf("invalid multisig threshold %d", account.Threshold)
+	}
+	for i, key := range account.Keys {
+		switch SignedTxType(key.SigType) {
+		case LoomSignedTxType, EthereumSignedTxType, TronSignedTxType, BinanceSignedTxType:
+		default:
+			return errors.Errorf("invalid signature type %s for key %d", key.SigType, i)
+		}
+		if len(key.Local) != localAddressLength {
+			return errors.Errorf("invalid address for key %d", i)
+		}
+		if i > 0 && bytes.Compare(account.Keys[i-1].Local, key.Local) >= 0 {
+			return errors.New("multisig keys must be unique & sorted by address")
+		}
+	}
+	return nil
+}
+
+// MultiSigAddress returns the address of the given multisig account.
+func MultiSigAddress(account *MultiSigAccount) (loom.LocalAddress, error) {
+	if err := ValidateMultiSigAccount(account); err != nil {
+		return nil, err
+	}
+	accountBytes, err := proto.Marshal(account)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal MultiSigAccount")
+	}
+	return loom.LocalAddressFromPublicKey(append(multiSigAddressPrefix, accountBytes...)), nil
+}
+
+// verifyMultiSig verifies a tx signed by a multisig account, the SignedTx signature must contain a
+// serialized MultiSignature with at least a threshold number of valid signatures from the account
+// keys. Each signature must be over the same inner tx.
+func verifyMultiSig(chainID string, tx SignedTx, _ []evmcompat.SignatureType) ([]byte, error) {
+	var multiSig MultiSignature
+	if err := proto.Unmarshal(tx.Signature, &multiSig); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal MultiSignature")
+	}
+
+	addr, err := MultiSigAddress(multiSig.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := multiSig.Account.Keys
+	signed := make(map[uint32]bool, len(multiSig.Signatures))
+	for _, sig := range multiSig.Signatures {
+		if sig == nil || sig.KeyIndex >= uint32(len(keys)) {
+			return nil, errors.New("invalid multisig key index")
+		}
+		if signed[sig.KeyIndex] {
+			return nil, errors.Errorf("duplicate signature for multisig key %d", sig.KeyIndex)
+		}
+		key := keys[sig.KeyIndex]
+		signer, err := recoverMultiSigSigner(chainID, SignedTxType(key.SigType), tx.Inner, sig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid signature for multisig key %d", sig.KeyIndex)
+		}
+		if !bytes.Equal(signer, key.Local) {
+			return nil, errors.Errorf("signature for multisig key %d was signed by another key", sig.KeyIndex)
+		}
+		signed[sig.KeyIndex] = true
+	}
+
+	if len(signed) < int(multiSig.Account.Threshold) {
+		return nil, errors.Errorf(
+			"not enough multisig signatures, got %d, need %d", len(signed), multiSig.Account.Threshold,
+		)
+	}
+	return addr, nil
+}
+
+// recoverMultiSigSigner recovers the address of the key that produced a partial signature.
+func recoverMultiSigSigner(
+	chainID string, sigType SignedTxType, inner []byte, sig *PartialSignature,
+) ([]byte, error) {
+	partialTx := SignedTx{
+		Inner:     inner,
+		Signature: sig.Signature,
+		PublicKey: sig.PublicKey,
+	}
+	allowedSigTypes := multiSigAllowedSigTypes[sigType]
+	switch sigType {
+	case LoomSignedTxType:
+		return verifyEd25519(chainID, partialTx, allowedSigTypes)
+	case EthereumSignedTxType:
+		return verifySolidity66Byte(chainID, partialTx, allowedSigTypes)
+	case TronSignedTxType:
+		return verifyTron(chainID, partialTx, allowedSigTypes)
+	case BinanceSignedTxType:
+		return verifyBinance(chainID, partialTx, allowedSigTypes)
+	}
+	return nil, errors.Errorf("unsupported signature type %s", sigType)
+}
@@ -0,0 +1,107 @@
+// +build evm
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gogo/protobuf/proto"
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/auth"
+	"github.com/loomnetwork/go-loom/plugin/contractpb"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
+	"github.com/loomnetwork/loomchain/store"
+)
+
+func TestMultiSigAccountVerification(t *testing.T) {
+	state := loomchain.NewStoreState(nil, store.NewMemStore(), abci.Header{ChainID: defaultLoomChainId}, nil, nil)
+	ctx := context.WithValue(state.Context(), ContextKeyOrigin, origin)
+	tmx := NewMultiChainSignatureTxMiddleware(
+		map[string]ChainConfig{
+			"default": {
+				TxType:      LoomSignedTxType,
+				AccountType: NativeAccountType,
+			},
+		},
+		func(state loomchain.State) (contractpb.StaticContext, error) { return nil, nil },
+	)
+
+	ed25519PrivKey, err := base64.StdEncoding.DecodeString(priKey1)
+	require.NoError(t, err)
+	ed25519Signer := auth.NewSigner(auth.SignerTypeEd25519, ed25519PrivKey)
+	ethKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ethSigner := &auth.EthSigner66Byte{PrivateKey: ethKey}
+	tronKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tronSigner := &auth.TronSigner{PrivateKey: tronKey}
+
+	keys := []*MultiSigKey{
+		{SigType: string(LoomSignedTxType), Local: loom.LocalAddressFromPublicKey(ed25519Signer.PublicKey())},
+		{SigType: string(EthereumSignedTxType), Local: crypto.PubkeyToAddress(ethKey.PublicKey).Bytes()},
+		{SigType: string(TronSignedTxType), Local: crypto.PubkeyToAddress(tronKey.PublicKey).Bytes()},
+	}
+	signers := map[string]auth.Signer{
+		string(keys[0].Local): ed25519Signer,
+		string(keys[1].Local): ethSigner,
+		string(keys[2].Local): tronSigner,
+	}
+
+	_, err = NewMultiSigAccount(keys, 4)
+	require.Error(t, err)
+	account, err := NewMultiSigAccount(keys, 2)
+	require.NoError(t, err)
+	localAddr, err := MultiSigAddress(account)
+	require.NoError(t, err)
+	nonceTx := mockNonceTx(t, loom.Address{ChainID: defaultLoomChainId, Local: localAddr}, sequence)
+
+	signTx := func(keyIndexes ...uint32) []byte {
+		multiSig := &MultiSignature{Account: account}
+		for _, i := range keyIndexes {
+			signer := signers[string(account.Keys[i].Local)]
+			sig := &PartialSignature{
+				KeyIndex:  i,
+				Signature: signer.Sign(nonceTx),
+			}
+			if account.Keys[i].SigType == string(LoomSignedTxType) {
+				sig.PublicKey = signer.PublicKey()
+			}
+			multiSig.Signatures = append(multiSig.Signatures, sig)
+		}
+		multiSigBytes, err := proto.Marshal(multiSig)
+		require.NoError(t, err)
+		signedTxBytes, err := proto.Marshal(&auth.SignedTx{Inner: nonceTx, Signature: multiSigBytes})
+		require.NoError(t, err)
+		return signedTxBytes
+	}
+
+	// Multisig accounts can't be used until the feature is enabled
+	_, err = throttleMiddlewareHandler(tmx, state, signTx(0, 1), ctx)
+	require.Error(t, err)
+
+	state.SetFeature(features.MultiSigAccountFeature, true)
+	_, err = throttleMiddlewareHandler(tmx, state, signTx(0, 1), ctx)
+	require.NoError(t, err)
+	_, err = throttleMiddlewareHandler(tmx, state, signTx(1, 2), ctx)
+	require.NoError(t, err)
+	_, err = throttleMiddlewareHandler(tmx, state, signTx(0, 1, 2), ctx)
+	require.NoError(t, err)
+
+	// Not enough signatures
+	_, err = throttleMiddlewareHandler(tmx, state, signTx(2), ctx)
+	require.Error(t, err)
+	// The same key can't sign more than once
+	_, err = throttleMiddlewareHandler(tmx, state, signTx(2, 2), ctx)
+	require.Error(t, err)
+
+	// Regular ed25519 signed txs should still work
+	_, err = throttleMiddlewareHandler(tmx, state, mockEd25519SignedTx(t, priKey1), ctx)
+	require.NoError(t, err)
+}
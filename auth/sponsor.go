@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/plugin/contractpb"
+	"github.com/loomnetwork/go-loom/types"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/loomnetwork/go-loom/vm"
+	"github.com/pkg/errors"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
+)
+
+// SponsorPolicyTxID is the ID of the txs handled by the SponsorPolicyTx handler.
+const SponsorPolicyTxID = 6
+
+// Payer returns the account that pays for the tx currently being processed, this is the sponsor of
+// the tx if the tx is sponsored, otherwise it's the origin of the tx.
+func Payer(ctx context.Context) loom.Address {
+	if sponsor, ok := ctx.Value(ContextKeySponsor).(loom.Address); ok {
+		return sponsor
+	}
+	return Origin(ctx)
+}
+
+// parseSponsoredTx returns nil if the given tx isn't a SponsoredTx.
+func parseSponsoredTx(txBytes []byte) *SponsoredTx {
+	var tx SponsoredTx
+	if err := proto.Unmarshal(txBytes, &tx); err != nil || len(tx.UserTx) == 0 {
+		return nil
+	}
+	return &tx
+}
+
+// UnwrapSponsoredTx returns the user's SignedTx if the given tx is a SponsoredTx, otherwise the
+// given tx is returned as is.
+func UnwrapSponsoredTx(txBytes []byte) []byte {
+	if tx := parseSponsoredTx(txBytes); tx != nil {
+		return tx.UserTx
+	}
+	return txBytes
+}
+
+// NewSponsoredTxMiddleware returns middleware that unwraps SponsoredTx(s). The sponsor's signature
+// and policy are checked, and then the user's SignedTx is passed through to the signature middleware
+// with the sponsor stored in the context. Txs that aren't sponsored are passed through as is.
+func NewSponsoredTxMiddleware(
+	authConfig *Config,
+	createAddressMapperCtx func(state loomchain.State) (contractpb.StaticContext, error),
+) loomchain.TxMiddlewareFunc {
+	return loomchain.TxMiddlewareFunc(func(
+		state loomchain.State,
+		txBytes []byte,
+		next loomchain.TxHandlerFunc,
+		isCheckTx bool,
+	) (loomchain.TxHandlerResult, error) {
+		var r loomchain.TxHandlerResult
+
+		if !state.FeatureEnabled(features.SponsoredTxFeature, false) {
+			return next(state, txBytes, isCheckTx)
+		}
+
+		tx := parseSponsoredTx(txBytes)
+		if tx == nil {
+			return next(state, txBytes, isCheckTx)
+		}
+
+		sponsor, err := recoverSponsor(state, authConfig, tx, createAddressMapperCtx)
+		if err != nil {
+			return r, errors.Wrap(err, "failed to verify sponsor")
+		}
+
+		if err := checkSponsorPolicy(state, sponsor, tx.UserTx); err != nil {
+			return r, err
+		}
+
+		ctx := state.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx = context.WithValue(ctx, ContextKeySponsor, sponsor)
+		return next(state.WithContext(ctx), tx.UserTx, isCheckTx)
+	})
+}
+
+// recoverSponsor verifies the sponsor signature in the given tx, and returns the address used to
+// identify the sponsor on this chain.
+func recoverSponsor(
+	state loomchain.State,
+	authConfig *Config,
+	tx *SponsoredTx,
+	createAddressMapperCtx func(state loomchain.State) (contractpb.StaticContext, error),
+) (loom.Address, error) {
+	if tx.Sponsor == nil {
+		return loom.Address{}, errors.New("sponsor not specified")
+	}
+	sponsor := loom.UnmarshalAddressPB(tx.Sponsor)
+	signedTx := SignedTx{
+		Inner:     tx.UserTx,
+		Signature: tx.Signature,
+		PublicKey: tx.PublicKey,
+	}
+
+	chains := getEnabledChains(authConfig.Chains, state)
+	if len(chains) == 0 {
+		signer, err := GetOrigin(signedTx, state.Block().ChainID)
+		if err != nil {
+			return loom.Address{}, err
+		}
+		if signer.Compare(sponsor) != 0 {
+			return loom.Address{}, fmt.Errorf("sponsor %s doesn't match signer %s", sponsor, signer)
+		}
+		return sponsor, nil
+	}
+
+	chain, found := chains[sponsor.ChainID]
+	if !found {
+		return loom.Address{}, fmt.Errorf("unknown chain ID %s", sponsor.ChainID)
+	}
+
+	recoverSigner := getOriginRecoveryFunc(state, signedTx, types.TxID_CALL, chain.TxType)
+	if recoverSigner == nil {
+		return loom.Address{}, fmt.Errorf("recovery function for Tx type %v not found", chain.TxType)
+	}
+
+	signer, err := recoverSigner(state.Block().ChainID, signedTx, getAllowedSignatureTypes(state, sponsor.ChainID))
+	if err != nil {
+		return loom.Address{}, err
+	}
+	if !bytes.Equal(signer, sponsor.Local) {
+		return loom.Address{}, fmt.Errorf("sponsor %s doesn't match signer %s", sponsor.Local, loom.LocalAddress(signer))
+	}
+
+	switch chain.AccountType {
+	case NativeAccountType:
+		return sponsor, nil
+	case MappedAccountType:
+		return getMappedAccountAddress(state, sponsor, createAddressMapperCtx)
+	default:
+		return loom.Address{}, fmt.Errorf("Invalid account type %v for chain ID %s", chain.AccountType, sponsor.ChainID)
+	}
+}
+
+// checkSponsorPolicy returns an error if the sponsor's policy doesn't allow it to pay for the user's tx.
+func checkSponsorPolicy(state loomchain.State, sponsor loom.Address, userTxBytes []byte) error {
+	policy, err := GetSponsorPolicy(state, sponsor)
+	if err != nil {
+		return err
+	}
+	if len(policy.Contracts) == 0 {
+		return nil
+	}
+
+	var signedTx SignedTx
+	if err := proto.Unmarshal(userTxBytes, &signedTx); err != nil {
+		return errors.Wrap(err, "failed to unmarshal SignedTx")
+	}
+	var nonceTx NonceTx
+	if err := proto.Unmarshal(signedTx.Inner, &nonceTx); err != nil {
+		return errors.Wrap(err, "failed to unmarshal NonceTx")
+	}
+	var tx types.Transaction
+	if err := proto.Unmarshal(nonceTx.Inner, &tx); err != nil {
+		return errors.Wrap(err, "failed to unmarshal Transaction")
+	}
+	var msg vm.MessageTx
+	if err := proto.Unmarshal(tx.Data, &msg); err != nil {
+		return errors.Wrap(err, "failed to unmarshal MessageTx")
+	}
+
+	if types.TxID(tx.Id) == types.TxID_DEPLOY || msg.To == nil {
+		return fmt.Errorf("sponsor %s doesn't pay for contract deployment", sponsor)
+	}
+	contract := loom.UnmarshalAddressPB(msg.To)
+	for _, addr := range policy.Contracts {
+		if loom.UnmarshalAddressPB(addr).Compare(contract) == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("sponsor %s doesn't pay for txs sent to %s", sponsor, contract)
+}
+
+func sponsorPolicyKey(sponsor loom.Address) []byte {
+	return util.PrefixKey([]byte("sponsor"), sponsor.Bytes())
+}
+
+// GetSponsorPolicy returns the policy of the given sponsor, the policy will be empty if the sponsor
+// hasn't set one.
+func GetSponsorPolicy(state loomchain.ReadOnlyState, sponsor loom.Address) (*SponsorPolicy, error) {
+	var policy SponsorPolicy
+	if policyBytes := state.Get(sponsorPolicyKey(sponsor)); len(policyBytes) > 0 {
+		if err := proto.Unmarshal(policyBytes, &policy); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal SponsorPolicy")
+		}
+	}
+	return &policy, nil
+}
+
+// SetSponsorPolicy replaces the policy of the given sponsor, an empty policy removes all restrictions.
+func SetSponsorPolicy(state loomchain.State, sponsor loom.Address, policy *SponsorPolicy) error {
+	if len(policy.Contracts) == 0 {
+		state.Delete(sponsorPolicyKey(sponsor))
+		return nil
+	}
+	for _, addr := range policy.Contracts {
+		if addr == nil || addr.Local == nil {
+			return errors.New("invalid contract address in SponsorPolicy")
+		}
+	}
+	policyBytes, err := proto.Marshal(policy)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SponsorPolicy")
+	}
+	state.Set(sponsorPolicyKey(sponsor), policyBytes)
+	return nil
+}
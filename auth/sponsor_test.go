@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	proto "github.com/gogo/protobuf/proto"
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/auth"
+	"github.com/loomnetwork/go-loom/types"
+	"github.com/loomnetwork/go-loom/vm"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
+	"github.com/loomnetwork/loomchain/store"
+)
+
+func TestSponsoredTxMiddleware(t *testing.T) {
+	_, userPrivKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, sponsorPrivKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	userSigner := auth.NewEd25519Signer([]byte(userPrivKey))
+	sponsorSigner := auth.NewEd25519Signer([]byte(sponsorPrivKey))
+	user := loom.Address{ChainID: "default", Local: loom.LocalAddressFromPublicKey(userSigner.PublicKey())}
+	sponsor := loom.Address{ChainID: "default", Local: loom.LocalAddressFromPublicKey(sponsorSigner.PublicKey())}
+	contract1 := loom.MustParseAddress("default:0x9a1aC42a17AAD6Dbc6d21c162989d0f701074044")
+	contract2 := loom.MustParseAddress("default:0x2a6b071aD396cEFdd16c731454af0d8c95ECD4B2")
+
+	userTx := func(to loom.Address) []byte {
+		msgTxBytes, err := proto.Marshal(&vm.MessageTx{From: user.MarshalPB(), To: to.MarshalPB()})
+		require.NoError(t, err)
+		txBytes, err := proto.Marshal(&types.Transaction{Id: uint32(types.TxID_CALL), Data: msgTxBytes})
+		require.NoError(t, err)
+		nonceTxBytes, err := proto.Marshal(&NonceTx{Inner: txBytes, Sequence: 1})
+		require.NoError(t, err)
+		signedTxBytes, err := proto.Marshal(auth.SignTx(userSigner, nonceTxBytes))
+		require.NoError(t, err)
+		return signedTxBytes
+	}
+	sponsorTx := func(userTxBytes []byte, signer auth.Signer) []byte {
+		txBytes, err := proto.Marshal(&SponsoredTx{
+			UserTx:    userTxBytes,
+			Sponsor:   sponsor.MarshalPB(),
+			Signature: signer.Sign(userTxBytes),
+			PublicKey: signer.PublicKey(),
+		})
+		require.NoError(t, err)
+		return txBytes
+	}
+
+	var origin, payer loom.Address
+	handler := func(state loomchain.State, txBytes []byte, isCheckTx bool) (loomchain.TxHandlerResult, error) {
+		origin = Origin(state.Context())
+		payer = Payer(state.Context())
+		return loomchain.TxHandlerResult{}, nil
+	}
+	verifySignature := func(state loomchain.State, txBytes []byte, isCheckTx bool) (loomchain.TxHandlerResult, error) {
+		return SignatureTxMiddleware(state, txBytes, handler, isCheckTx)
+	}
+	mw := NewSponsoredTxMiddleware(&Config{}, nil)
+	processTx := func(state loomchain.State, txBytes []byte) error {
+		_, err := mw(state, txBytes, verifySignature, false)
+		return err
+	}
+
+	state := loomchain.NewStoreState(context.Background(), store.NewMemStore(), abci.Header{ChainID: "default"}, nil, nil)
+	// Sponsored txs are rejected until the feature is enabled
+	require.Error(t, processTx(state, sponsorTx(userTx(contract1), sponsorSigner)))
+
+	state.SetFeature(features.SponsoredTxFeature, true)
+	require.NoError(t, processTx(state, userTx(contract1)))
+	require.Equal(t, user, origin)
+	require.Equal(t, user, payer)
+
+	require.NoError(t, processTx(state, sponsorTx(userTx(contract1), sponsorSigner)))
+	require.Equal(t, user, origin)
+	require.Equal(t, sponsor, payer)
+
+	// The sponsor signature must be produced by the sponsor
+	require.Error(t, processTx(state, sponsorTx(userTx(contract1), userSigner)))
+
+	// Sponsors can restrict the contracts they pay for
+	require.NoError(t, SetSponsorPolicy(state, sponsor, &SponsorPolicy{
+		Contracts: []*types.Address{contract1.MarshalPB()},
+	}))
+	require.NoError(t, processTx(state, sponsorTx(userTx(contract1), sponsorSigner)))
+	require.Error(t, processTx(state, sponsorTx(userTx(contract2), sponsorSigner)))
+
+	require.NoError(t, SetSponsorPolicy(state, sponsor, &SponsorPolicy{}))
+	require.NoError(t, processTx(state, sponsorTx(userTx(contract2), sponsorSigner)))
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"path"
@@ -15,6 +16,7 @@ import (
 	"github.com/loomnetwork/go-loom/types"
 	"github.com/loomnetwork/go-loom/util"
 	"github.com/loomnetwork/loomchain/cmd/loom/common"
+	cdb "github.com/loomnetwork/loomchain/db"
 	"github.com/loomnetwork/loomchain/store"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -110,6 +112,9 @@ var (
 	}
 	curStandardPrefixes = [][]byte{
 		[]byte("nonce"),
+		[]byte("lane_nonce"),
+		[]byte("sponsor"),
+		[]byte("txlimit"),
 		[]byte("feature"),
 		[]byte("registry"),
 		[]byte("reg_caddr"),
@@ -594,6 +599,141 @@ func newExtractCurrentStateCommand() *cobra.Command {
 	return cmd
 }
 
+func newExportSnapshotCommand() *cobra.Command {
+	var appHeight int64
+	var chunkSize int
+	var evmDBName string
+	cmd := &cobra.Command{
+		Use:   "export-snapshot <path/to/snapshot_dir>",
+		Short: "Export a chunked snapshot of app.db & evm.db at a specific height",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := common.ParseConfig()
+			if err != nil {
+				return err
+			}
+
+			snapshotDir, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("Failed to resolve snapshot path '%s'", args[0])
+			}
+
+			appDB, err := cdb.LoadReadOnlyDB(cfg.DBBackend, cfg.DBName, cfg.RootPath())
+			if err != nil {
+				return err
+			}
+			defer appDB.Close()
+
+			evmDB, err := cdb.LoadReadOnlyDB(cfg.EvmStore.DBBackend, evmDBName, cfg.RootPath())
+			if err != nil {
+				return err
+			}
+			defer evmDB.Close()
+
+			startTime := time.Now()
+			manifest, err := store.ExportStateSnapshot(appDB, evmDB, appHeight, snapshotDir, chunkSize)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf(
+				"Exported snapshot at height %d in %v mins: %d app keys, %d evm keys, %d chunks\n",
+				manifest.Version, time.Since(startTime).Minutes(),
+				manifest.NumAppKeys, manifest.NumEvmKeys, len(manifest.Chunks),
+			)
+			fmt.Printf("App hash: %s\nEVM root: %s\n", manifest.AppHash, manifest.EvmRoot)
+			return nil
+		},
+	}
+	cmdFlags := cmd.Flags()
+	cmdFlags.Int64Var(&appHeight, "app-height", 0, "Export the state as it was at the specified app height, defaults to the latest height")
+	cmdFlags.IntVar(&chunkSize, "chunk-size", store.DefaultStateSnapshotChunkSize, "Max number of bytes to write to each chunk")
+	cmdFlags.StringVar(&evmDBName, "evmdb-name", "evm", "Name of EVM state database")
+	return cmd
+}
+
+func newImportSnapshotCommand() *cobra.Command {
+	var appHash string
+	var evmDBName string
+	cmd := &cobra.Command{
+		Use:   "import-snapshot <path/to/snapshot_dir>",
+		Short: "Import a snapshot created by export-snapshot into an empty app.db & evm.db",
+		Long: `Import a snapshot created by export-snapshot into an empty app.db & evm.db.
+
+Every chunk is verified against the snapshot manifest before anything is written to the DBs, and
+once all the chunks have been imported the reconstructed IAVL root & EVM root are verified again. To verify the
+snapshot itself use --app-hash to specify the app hash from the header of the block that follows
+the snapshot height.
+
+The Tendermint block store & state in chaindata must be brought to the same height separately.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := common.ParseConfig()
+			if err != nil {
+				return err
+			}
+
+			snapshotDir, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("Failed to resolve snapshot path '%s'", args[0])
+			}
+
+			manifest, err := store.ReadStateSnapshotManifest(snapshotDir)
+			if err != nil {
+				return err
+			}
+			if appHash != "" {
+				expectedAppHash, err := hex.DecodeString(strings.TrimPrefix(appHash, "0x"))
+				if err != nil {
+					return errors.Wrap(err, "invalid app hash")
+				}
+				if !strings.EqualFold(manifest.AppHash, hex.EncodeToString(expectedAppHash)) {
+					return fmt.Errorf(
+						"snapshot app hash %s doesn't match expected app hash %X",
+						manifest.AppHash, expectedAppHash,
+					)
+				}
+			}
+
+			appDB, err := cdb.LoadDB(
+				cfg.DBBackend, cfg.DBName, cfg.RootPath(), cfg.DBBackendConfig.CacheSizeMegs,
+				cfg.DBBackendConfig.WriteBufferMegs, false,
+			)
+			if err != nil {
+				return err
+			}
+			defer appDB.Close()
+
+			evmDB, err := cdb.LoadDB(
+				cfg.EvmStore.DBBackend, evmDBName, cfg.RootPath(), cfg.EvmStore.CacheSizeMegs,
+				cfg.EvmStore.WriteBufferMegs, false,
+			)
+			if err != nil {
+				return err
+			}
+			defer evmDB.Close()
+
+			startTime := time.Now()
+			manifest, err = store.ImportStateSnapshot(snapshotDir, appDB, evmDB)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf(
+				"Imported snapshot at height %d in %v mins: %d app keys, %d evm keys, %d chunks\n",
+				manifest.Version, time.Since(startTime).Minutes(),
+				manifest.NumAppKeys, manifest.NumEvmKeys, len(manifest.Chunks),
+			)
+			fmt.Printf("App hash: %s\nEVM root: %s\n", manifest.AppHash, manifest.EvmRoot)
+			return nil
+		},
+	}
+	cmdFlags := cmd.Flags()
+	cmdFlags.StringVar(&appHash, "app-hash", "", "Expected app hash (hex) of the snapshot")
+	cmdFlags.StringVar(&evmDBName, "evmdb-name", "evm", "Name of EVM state database")
+	return cmd
+}
+
 func newCompareCurrentStateCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "compare-current-state <path/to/src_app.db> <path/to/dest_app.db>",
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/loomnetwork/loomchain/cmd/loom/common"
+	cdb "github.com/loomnetwork/loomchain/db"
+	evmaux "github.com/loomnetwork/loomchain/store/evm_aux"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func newMigrateBackendCommand() *cobra.Command {
+	var srcBackend, destBackend, destDir string
+	var batchSize int
+	var verify bool
+	cmd := &cobra.Command{
+		Use:   "migrate-backend [db-name...]",
+		Short: "Copy the node databases to a different DB backend",
+		Long: `Copy the node databases to a different DB backend.
+
+By default app.db, evm.db, events.db & receipts_db are copied from the node's root dir to the
+destination dir, the names of the DBs to copy can be specified explicitly instead. The node must be
+stopped while the DBs are being copied. Once the copy is complete the original DBs should be
+replaced by the new ones, and the DBBackend settings in loom.yaml should be updated to match.
+
+NOTE: The receipts DB (receipts_db) only supports goleveldb, so it's copied to the destination dir
+without changing the backend.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := common.ParseConfig()
+			if err != nil {
+				return err
+			}
+
+			if srcBackend == destBackend {
+				return errors.New("source & destination backends must be different")
+			}
+			if destBackend == cdb.MemDBackend {
+				return errors.New("can't migrate to an in-memory DB")
+			}
+
+			dbNames := args
+			if len(dbNames) == 0 {
+				dbNames = append(dbNames, cfg.DBName)
+				if cfg.EvmStore != nil {
+					dbNames = append(dbNames, cfg.EvmStore.DBName)
+				}
+				if cfg.EventStore != nil {
+					dbNames = append(dbNames, cfg.EventStore.DBName)
+				}
+				dbNames = append(dbNames, evmaux.EvmAuxDBName)
+			}
+
+			destDir, err = filepath.Abs(destDir)
+			if err != nil {
+				return fmt.Errorf("Failed to resolve destination path '%s'", destDir)
+			}
+			if err := os.MkdirAll(destDir, 0755); err != nil {
+				return err
+			}
+
+			for _, dbName := range dbNames {
+				if dbName == evmaux.EvmAuxDBName {
+					if err := migrateReceiptsDB(destDir, batchSize, verify); err != nil {
+						return errors.Wrapf(err, "failed to migrate %s", dbName)
+					}
+					continue
+				}
+				if _, err := os.Stat(filepath.Join(cfg.RootPath(), dbName+".db")); os.IsNotExist(err) {
+					fmt.Printf("Skipping %s.db, it doesn't exist\n", dbName)
+					continue
+				}
+				if err := migrateDBBackend(
+					dbName, cfg.RootPath(), srcBackend, destDir, destBackend, batchSize, verify,
+				); err != nil {
+					return errors.Wrapf(err, "failed to migrate %s.db", dbName)
+				}
+			}
+			return nil
+		},
+	}
+	cmdFlags := cmd.Flags()
+	cmdFlags.StringVar(&srcBackend, "from", cdb.GoLevelDBBackend, "Backend of the existing DBs")
+	cmdFlags.StringVar(&destBackend, "to", cdb.BadgerDBBackend, "Backend of the new DBs")
+	cmdFlags.StringVar(&destDir, "dest", "", "Directory the new DBs should be written to")
+	cmdFlags.IntVar(&batchSize, "batch-size", 10000, "Number of keys to write to disk in each batch")
+	cmdFlags.BoolVar(&verify, "verify", true, "Compare the new DBs to the original ones once they're copied")
+	cmd.MarkFlagRequired("dest")
+	return cmd
+}
+
+func migrateDBBackend(
+	dbName, srcDir, srcBackend, destDir, destBackend string, batchSize int, verify bool,
+) error {
+	if _, err := os.Stat(filepath.Join(destDir, dbName+".db")); err == nil {
+		return fmt.Errorf("%s already exists", filepath.Join(destDir, dbName+".db"))
+	}
+
+	srcDB, err := cdb.LoadDB(srcBackend, dbName, srcDir, 256, 4, false)
+	if err != nil {
+		return err
+	}
+	defer srcDB.Close()
+
+	destDB, err := cdb.LoadDB(destBackend, dbName, destDir, 256, 64, false)
+	if err != nil {
+		return err
+	}
+	defer destDB.Close()
+
+	startTime := time.Now()
+	numKeys, err := cdb.CopyDB(srcDB, destDB, batchSize)
+	if err != nil {
+		return err
+	}
+	fmt.Printf(
+		"Copied %d keys from %s.db (%s) to %s.db (%s) in %v mins\n",
+		numKeys, dbName, srcBackend, dbName, destBackend, time.Since(startTime).Minutes(),
+	)
+
+	if verify {
+		startTime = time.Now()
+		if err := cdb.CompareDBs(srcDB, destDB); err != nil {
+			return errors.Wrap(err, "verification failed")
+		}
+		fmt.Printf("Verified %s.db in %v mins\n", dbName, time.Since(startTime).Minutes())
+	}
+
+	if err := destDB.Compact(); err != nil {
+		return errors.Wrap(err, "failed to compact new DB")
+	}
+	return nil
+}
+
+// migrateReceiptsDB copies the receipts DB to the destination dir. The receipts DB is always opened
+// from the working dir of the node, and only supports goleveldb, so the copy uses goleveldb too.
+func migrateReceiptsDB(destDir string, batchSize int, verify bool) error {
+	if _, err := os.Stat(evmaux.EvmAuxDBName); os.IsNotExist(err) {
+		fmt.Printf("Skipping %s, it doesn't exist\n", evmaux.EvmAuxDBName)
+		return nil
+	}
+	destPath := filepath.Join(destDir, evmaux.EvmAuxDBName)
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("%s already exists", destPath)
+	}
+
+	srcDB, err := leveldb.OpenFile(evmaux.EvmAuxDBName, &opt.Options{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer srcDB.Close()
+
+	destDB, err := leveldb.OpenFile(destPath, nil)
+	if err != nil {
+		return err
+	}
+	defer destDB.Close()
+
+	startTime := time.Now()
+	var numKeys uint64
+	it := srcDB.NewIterator(nil, nil)
+	defer it.Release()
+	batch := new(leveldb.Batch)
+	for it.Next() {
+		batch.Put(it.Key(), it.Value())
+		numKeys++
+		if batch.Len() == batchSize {
+			if err := destDB.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if batch.Len() > 0 {
+		if err := destDB.Write(batch, nil); err != nil {
+			return err
+		}
+	}
+	fmt.Printf(
+		"Copied %d keys from %s to %s in %v mins\n",
+		numKeys, evmaux.EvmAuxDBName, destPath, time.Since(startTime).Minutes(),
+	)
+
+	if verify {
+		startTime = time.Now()
+		itA := srcDB.NewIterator(nil, nil)
+		defer itA.Release()
+		itB := destDB.NewIterator(nil, nil)
+		defer itB.Release()
+		for itA.Next() {
+			if !itB.Next() {
+				return errors.Errorf("verification failed, key %X is missing", itA.Key())
+			}
+			if !bytes.Equal(itA.Key(), itB.Key()) || !bytes.Equal(itA.Value(), itB.Value()) {
+				return errors.Errorf("verification failed, mismatch at key %X", itA.Key())
+			}
+		}
+		if itB.Next() {
+			return errors.Errorf("verification failed, unexpected key %X", itB.Key())
+		}
+		fmt.Printf("Verified %s in %v mins\n", evmaux.EvmAuxDBName, time.Since(startTime).Minutes())
+	}
+	return destDB.CompactRange(util.Range{})
+}
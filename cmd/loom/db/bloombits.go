@@ -0,0 +1,58 @@
+// +build evm
+
+package db
+
+import (
+	"fmt"
+
+	"github.com/loomnetwork/loomchain/receipts/leveldb"
+	evmaux "github.com/loomnetwork/loomchain/store/evm_aux"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newIndexBloomBitsCommand() *cobra.Command {
+	var from, to uint64
+	cmd := &cobra.Command{
+		Use:   "index-bloom-bits",
+		Short: "Backfill the bloom-bits index used by eth_getLogs from the stored tx receipts",
+		Long: "Regenerates the blooms of the blocks in the given range from the tx receipts stored in " +
+			"the receipts DB, and indexes all the sections of the bloom-bits index that become complete. " +
+			"Must be run in the node directory while the node is stopped.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			evmAuxStore, err := evmaux.LoadStore()
+			if err != nil {
+				return err
+			}
+			defer evmAuxStore.Close()
+
+			if to == 0 {
+				bloomsFrom, ok, err := evmAuxStore.BlockBloomsFrom()
+				if err != nil {
+					return err
+				}
+				if !ok || bloomsFrom <= 1 {
+					return errors.New("--to must be specified")
+				}
+				to = bloomsFrom - 1
+			}
+
+			receipts := leveldb.NewLevelDbReceipts(evmAuxStore, 0)
+			err = receipts.BackfillBlockBlooms(from, to, func(height uint64) {
+				fmt.Printf("Stored block blooms up to height %d\n", height)
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Backfilled block blooms from height %d to %d\n", from, to)
+			return nil
+		},
+	}
+	cmdFlags := cmd.Flags()
+	cmdFlags.Uint64Var(&from, "from", 1, "Height of the first block to backfill")
+	cmdFlags.Uint64Var(
+		&to, "to", 0,
+		"Height of the last block to backfill, defaults to the height before the first block with a stored bloom",
+	)
+	return cmd
+}
@@ -21,7 +21,11 @@ func NewDBCommand() *cobra.Command {
 		newGetAppHeightCommand(),
 		newAnalyzeCommand(),
 		newExtractCurrentStateCommand(),
+		newExportSnapshotCommand(),
+		newImportSnapshotCommand(),
+		newMigrateBackendCommand(),
 		newCompareCurrentStateCommand(),
+		newIndexBloomBitsCommand(),
 	)
 	return cmd
 }
@@ -14,6 +14,7 @@ func NewDBCommand() *cobra.Command {
 	cmd.AddCommand(
 		newPruneDBCommand(),
 		newCompactDBCommand(),
+		newMigrateBackendCommand(),
 	)
 	return cmd
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/loomnetwork/go-loom/cli"
+	"github.com/loomnetwork/go-loom/client"
+	"github.com/loomnetwork/loomchain/fnConsensus"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
+)
+
+func newFnConsensusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fnconsensus <command>",
+		Short: "Inspect the state of the fnConsensus reactor",
+	}
+	cmd.AddCommand(newFnConsensusStatusCommand())
+	return cmd
+}
+
+const fnConsensusStatusCmdExample = `
+loom fnconsensus status -u http://localhost:46658
+`
+
+func newFnConsensusStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Show the current nonce & vote progress of each Fn known to the fnConsensus reactor",
+		Example: fnConsensusStatusCmdExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var rawJSON json.RawMessage
+			rpcclient := client.NewJSONRPCClient(cli.TxFlags.URI + "/query")
+			if err := rpcclient.Call("fnconsensus_status", map[string]interface{}{}, "1", &rawJSON); err != nil {
+				return errors.Wrap(err, "failed to fetch fnConsensus status")
+			}
+			var status fnConsensus.ReactorStatus
+			if err := amino.NewCodec().UnmarshalJSON(rawJSON, &status); err != nil {
+				return errors.Wrap(err, "failed to decode fnConsensus status")
+			}
+
+			if !status.IsValidator {
+				fmt.Println("Node is not a validator, the fnConsensus reactor doesn't track any Fns.")
+				return nil
+			}
+			if len(status.Fns) == 0 {
+				fmt.Println("No Fns registered.")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(w, "Fn\tRegistered\tNonce\tVote In Progress\tSigned")
+			for _, fn := range status.Fns {
+				signed := "-"
+				if fn.VoteInProgress {
+					signed = fmt.Sprintf("%d/%d", fn.NumSigned, fn.NumValidators)
+				}
+				fmt.Fprintf(w, "%s\t%t\t%d\t%t\t%s\n", fn.FnID, fn.Registered, fn.Nonce, fn.VoteInProgress, signed)
+			}
+			return w.Flush()
+		},
+	}
+	setChainFlags(cmd.Flags())
+	return cmd
+}
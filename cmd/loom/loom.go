@@ -28,6 +28,7 @@ import (
 	"github.com/loomnetwork/go-loom/client"
 	"github.com/loomnetwork/go-loom/crypto"
 	"github.com/loomnetwork/go-loom/plugin/contractpb"
+	"github.com/loomnetwork/go-loom/types"
 	"github.com/loomnetwork/go-loom/util"
 	"github.com/loomnetwork/loomchain"
 	"github.com/loomnetwork/loomchain/abci/backend"
@@ -70,9 +71,11 @@ import (
 	"github.com/loomnetwork/loomchain/throttle"
 	"github.com/loomnetwork/loomchain/tx_handler"
 	"github.com/loomnetwork/loomchain/vm"
+	"github.com/loomnetwork/loomchain/wasm"
 	"github.com/pkg/errors"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
 	"golang.org/x/crypto/ed25519"
 )
 
@@ -356,20 +359,8 @@ func newRunCommand() *cobra.Command {
 			if cfg.FnConsensus.Enabled {
 				fnRegistry = fnConsensus.NewInMemoryFnRegistry()
 			}
-			var loaders []plugin.Loader
-			for _, loader := range cfg.ContractLoaders {
-				if strings.EqualFold("static", loader) {
-					loaders = append(loaders, common.NewDefaultContractsLoader(cfg))
-				}
-				if strings.EqualFold("dynamic", loader) {
-					loaders = append(loaders, plugin.NewManager(cfg.PluginsPath()))
-				}
-				if strings.EqualFold("external", loader) {
-					loaders = append(loaders, plugin.NewExternalLoader(cfg.PluginsPath()))
-				}
-			}
 			backend := initBackend(cfg, abciServerAddr, fnRegistry)
-			loader := plugin.NewMultiLoader(loaders...)
+			loader := newContractLoader(cfg)
 			termChan := make(chan os.Signal)
 			go func(c <-chan os.Signal, l plugin.Loader) {
 				<-c
@@ -415,7 +406,9 @@ func newRunCommand() *cobra.Command {
 				return err
 			}
 
-			if err := initQueryService(app, chainID, cfg, loader, app.ReceiptHandlerProvider); err != nil {
+			if err := initQueryService(
+				app, chainID, cfg, loader, app.ReceiptHandlerProvider, backend.FnConsensusReactor(),
+			); err != nil {
 				return err
 			}
 
@@ -446,6 +439,27 @@ func newRunCommand() *cobra.Command {
 	return cmd
 }
 
+// newContractLoader creates a loader that loads contracts using the loaders listed in the config.
+func newContractLoader(cfg *config.Config) plugin.Loader {
+	var loaders []plugin.Loader
+	for _, loader := range cfg.ContractLoaders {
+		if strings.EqualFold("static", loader) {
+			loaders = append(loaders, common.NewDefaultContractsLoader(cfg))
+		}
+		if strings.EqualFold("dynamic", loader) {
+			loaders = append(loaders, plugin.NewManager(cfg.PluginsPath()))
+		}
+		if strings.EqualFold("external", loader) {
+			externalLoader := plugin.NewExternalLoader(cfg.PluginsPath())
+			if cfg.ExternalPluginsWatchInterval > 0 {
+				externalLoader.Watch(time.Duration(cfg.ExternalPluginsWatchInterval)*time.Second, log.Default)
+			}
+			loaders = append(loaders, externalLoader)
+		}
+	}
+	return plugin.NewMultiLoader(loaders...)
+}
+
 const contractInfoCommandExample = `
 loom contract default:0x81ee596ba88eF371a51d4B535E07cB243A8C692d
 `
@@ -491,6 +505,168 @@ func contractInfoCommand() *cobra.Command {
 	return cmd
 }
 
+const contractVersionsCommandExample = `
+loom contract-versions SimpleStore
+`
+
+type contractVersion struct {
+	Address   string
+	GoVersion string `json:",omitempty"`
+	Height    int64
+}
+
+type contractVersions struct {
+	Name     string
+	Owner    string
+	Versions []contractVersion
+}
+
+func contractVersionsCommand() *cobra.Command {
+	var flags cli.ContractCallFlags
+	cmd := &cobra.Command{
+		Use:     "contract-versions <name>",
+		Short:   "Get the owner & version history of a named contract",
+		Args:    cobra.ExactArgs(1),
+		Example: contractVersionsCommandExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var rawJSON json.RawMessage
+			rpcclient := client.NewJSONRPCClient(flags.URI + "/query")
+			params := map[string]interface{}{"name": args[0]}
+			if err := rpcclient.Call("contractversions", params, "1", &rawJSON); err != nil {
+				return errors.Wrap(err, "failed to get contract versions")
+			}
+			var rec regcommon.NameRecord
+			if err := amino.NewCodec().UnmarshalJSON(rawJSON, &rec); err != nil {
+				return errors.Wrap(err, "failed to decode contract versions")
+			}
+			resp := &contractVersions{
+				Name:  rec.Name,
+				Owner: loom.UnmarshalAddressPB(rec.Owner).String(),
+			}
+			for _, v := range rec.Versions {
+				resp.Versions = append(resp.Versions, contractVersion{
+					Address:   loom.UnmarshalAddressPB(v.Address).String(),
+					GoVersion: v.GoVersion,
+					Height:    v.Height,
+				})
+			}
+
+			out, err := json.MarshalIndent(resp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		},
+	}
+	cli.AddContractStaticCallFlags(cmd.Flags(), &flags)
+	return cmd
+}
+
+const contractUpgradeCommandExample = `
+loom contract-upgrade SimpleStore --address 0x81ee596ba88eF371a51d4B535E07cB243A8C692d --height 12000 -k priv_key
+loom contract-upgrade coin --go-version 2.0.0 --height 12000 -k priv_key
+`
+
+func contractUpgradeCommand() *cobra.Command {
+	var addrStr, goVersion string
+	var height int64
+	cmd := &cobra.Command{
+		Use:     "contract-upgrade <name>",
+		Short:   "Schedule an upgrade of a named contract to a new address or Go contract version",
+		Args:    cobra.ExactArgs(1),
+		Example: contractUpgradeCommandExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if addrStr == "" && goVersion == "" {
+				return errors.New("either --address or --go-version must be specified")
+			}
+			upgrade := &regcommon.ContractVersion{
+				GoVersion: goVersion,
+				Height:    height,
+			}
+			if addrStr != "" {
+				addr, err := cli.ParseAddress(addrStr, cli.TxFlags.ChainID)
+				if err != nil {
+					return errors.Wrap(err, "invalid contract address")
+				}
+				upgrade.Address = addr.MarshalPB()
+			}
+			if err := commitRegistryTx(&regcommon.RegistryTx{Name: args[0], Upgrade: upgrade}); err != nil {
+				return err
+			}
+			fmt.Printf("Upgrade of %s scheduled at height %d\n", args[0], height)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&addrStr, "address", "", "Address of the new contract implementation")
+	cmd.Flags().StringVar(&goVersion, "go-version", "", "New Go contract version")
+	cmd.Flags().Int64Var(&height, "height", 0, "Block height at which the upgrade takes effect")
+	cmd.Flags().StringVarP(&cli.TxFlags.PrivFile, "key", "k", "", "private key file")
+	setChainFlags(cmd.Flags())
+	return cmd
+}
+
+const contractTransferCommandExample = `
+loom contract-transfer SimpleStore 0x2a6b071aD396cEFdd16c731454af0d8c95ECD4B2 -k priv_key
+`
+
+func contractTransferCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "contract-transfer <name> <new owner>",
+		Short:   "Transfer ownership of a named contract",
+		Args:    cobra.ExactArgs(2),
+		Example: contractTransferCommandExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newOwner, err := cli.ParseAddress(args[1], cli.TxFlags.ChainID)
+			if err != nil {
+				return errors.Wrap(err, "invalid owner address")
+			}
+			tx := &regcommon.RegistryTx{Name: args[0], NewOwner: newOwner.MarshalPB()}
+			if err := commitRegistryTx(tx); err != nil {
+				return err
+			}
+			fmt.Printf("Ownership of %s transferred to %s\n", args[0], newOwner.String())
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&cli.TxFlags.PrivFile, "key", "k", "", "private key file")
+	setChainFlags(cmd.Flags())
+	return cmd
+}
+
+func commitRegistryTx(registryTx *regcommon.RegistryTx) error {
+	callerChainID := cli.TxFlags.CallerChainID
+	if callerChainID == "" {
+		callerChainID = cli.TxFlags.ChainID
+	}
+	clientAddr, signer, err := caller(cli.TxFlags.PrivFile, "", cli.TxFlags.Algo, callerChainID)
+	if err != nil {
+		return errors.Wrapf(err, "initialization failed")
+	}
+	if signer == nil {
+		return fmt.Errorf("invalid private key")
+	}
+
+	registryTxBytes, err := proto.Marshal(registryTx)
+	if err != nil {
+		return err
+	}
+	msgTxBytes, err := proto.Marshal(&vm.MessageTx{
+		From: clientAddr.MarshalPB(),
+		To:   clientAddr.MarshalPB(),
+		Data: registryTxBytes,
+	})
+	if err != nil {
+		return err
+	}
+	rpcclient := client.NewDAppChainRPCClient(cli.TxFlags.ChainID, cli.TxFlags.URI+"/rpc", cli.TxFlags.URI+"/query")
+	_, err = rpcclient.CommitTx(clientAddr, signer, &types.Transaction{
+		Id:   regcommon.RegistryTxID,
+		Data: msgTxBytes,
+	})
+	return err
+}
+
 //nolint:deadcode
 func recovery() {
 	if r := recover(); r != nil {
@@ -814,71 +990,60 @@ func loadApp(
 		newABMFactory = plugin.NewAccountBalanceManagerFactory
 	}
 
-	vmManager := vm.NewManager()
-	vmManager.Register(vm.VMType_PLUGIN, func(state loomchain.State) (vm.VM, error) {
-		return plugin.NewPluginVM(
-			loader,
-			state,
-			createRegistry(state),
-			eventHandler,
-			log.Default,
-			newABMFactory,
-			receiptHandlerProvider.Writer(),
-			receiptHandlerProvider.Reader(),
-		), nil
-	})
+	createVMManager := func(
+		eventHandler loomchain.EventHandler, receiptHandlerProvider *receipts.ReceiptHandlerProvider,
+	) *vm.Manager {
+		vmManager := vm.NewManager()
+		vmManager.Register(vm.VMType_PLUGIN, func(state loomchain.State) (vm.VM, error) {
+			return plugin.NewPluginVM(
+				loader,
+				state,
+				createRegistry(state),
+				eventHandler,
+				log.Default,
+				newABMFactory,
+				receiptHandlerProvider.Writer(),
+				receiptHandlerProvider.Reader(),
+			), nil
+		})
 
-	if evm.EVMEnabled {
-		vmManager.Register(vm.VMType_EVM, func(state loomchain.State) (vm.VM, error) {
-			var createABM evm.AccountBalanceManagerFactoryFunc
-			var err error
-			if newABMFactory != nil {
-				pvm := plugin.NewPluginVM(
-					loader,
-					state,
-					createRegistry(state),
-					eventHandler,
-					log.Default,
-					newABMFactory,
-					receiptHandlerProvider.Writer(),
-					receiptHandlerProvider.Reader(),
-				)
-				createABM, err = newABMFactory(pvm)
-				if err != nil {
-					return nil, err
+		if evm.EVMEnabled {
+			vmManager.Register(vm.VMType_EVM, func(state loomchain.State) (vm.VM, error) {
+				var createABM evm.AccountBalanceManagerFactoryFunc
+				var err error
+				if newABMFactory != nil {
+					pvm := plugin.NewPluginVM(
+						loader,
+						state,
+						createRegistry(state),
+						eventHandler,
+						log.Default,
+						newABMFactory,
+						receiptHandlerProvider.Writer(),
+						receiptHandlerProvider.Reader(),
+					)
+					createABM, err = newABMFactory(pvm)
+					if err != nil {
+						return nil, err
+					}
 				}
+				return evm.NewLoomVm(
+					state, eventHandler, receiptHandlerProvider.Writer(), createABM, cfg.EVMDebugEnabled,
+				), nil
+			})
+		}
+
+		vmManager.Register(vm.VMType_WASM, func(state loomchain.State) (vm.VM, error) {
+			if !state.FeatureEnabled(features.WasmVMFeature, false) {
+				return nil, errors.New("WASM contracts are not enabled")
 			}
-			return evm.NewLoomVm(state, eventHandler, receiptHandlerProvider.Writer(), createABM, cfg.EVMDebugEnabled), nil
+			return wasm.NewWasmVM(state, createRegistry(state), eventHandler, vmManager.InitVM), nil
 		})
+		return vmManager
 	}
+	vmManager := createVMManager(eventHandler, receiptHandlerProvider)
 	evm.LogEthDbBatch = cfg.LogEthDbBatch
-
-	deployTxHandler := &vm.DeployTxHandler{
-		Manager:                vmManager,
-		CreateRegistry:         createRegistry,
-		AllowNamedEVMContracts: cfg.AllowNamedEvmContracts,
-	}
-
-	callTxHandler := &vm.CallTxHandler{
-		Manager: vmManager,
-	}
-
-	ethTxHandler := &tx_handler.EthTxHandler{
-		Manager:        vmManager,
-		CreateRegistry: createRegistry,
-	}
-
-	migrationTxHandler := &tx_handler.MigrationTxHandler{
-		Manager:        vmManager,
-		CreateRegistry: createRegistry,
-		Migrations: map[int32]tx_handler.MigrationFunc{
-			1: migrations.DPOSv3Migration,
-			2: migrations.GatewayMigration,
-			3: migrations.GatewayMigration,
-			4: migrations.GatewayMigration,
-			5: migrations.GatewayMigration,
-		},
-	}
+	evm.EnableGoContractBridge(plugin.NewGoContractCallerFactory(loader, createRegistry, newABMFactory, log.Default))
 
 	gen, err := config.ReadGenesis(cfg.GenesisPath())
 	if err != nil {
@@ -913,8 +1078,6 @@ func loadApp(
 		return nil
 	}
 
-	router := loomchain.NewTxRouter()
-
 	isEvmTx := func(txID uint32, state loomchain.State, txBytes []byte, isCheckTx bool) bool {
 		var msg vm.MessageTx
 		err := proto.Unmarshal(txBytes, &msg)
@@ -948,72 +1111,221 @@ func loadApp(
 		}
 	}
 
-	router.HandleDeliverTx(1, loomchain.GeneratePassthroughRouteHandler(deployTxHandler))
-	router.HandleDeliverTx(2, loomchain.GeneratePassthroughRouteHandler(callTxHandler))
-	router.HandleDeliverTx(3, loomchain.GeneratePassthroughRouteHandler(migrationTxHandler))
-	router.HandleDeliverTx(4, loomchain.GeneratePassthroughRouteHandler(ethTxHandler))
+	createRouter := func(vmManager *vm.Manager) *loomchain.TxRouter {
+		deployTxHandler := &vm.DeployTxHandler{
+			Manager:                vmManager,
+			CreateRegistry:         createRegistry,
+			AllowNamedEVMContracts: cfg.AllowNamedEvmContracts,
+		}
 
-	// TODO: Write this in more elegant way
-	router.HandleCheckTx(1, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, deployTxHandler))
-	router.HandleCheckTx(2, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, callTxHandler))
-	router.HandleCheckTx(3, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, migrationTxHandler))
-	router.HandleCheckTx(4, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, ethTxHandler))
+		callTxHandler := &vm.CallTxHandler{
+			Manager: vmManager,
+		}
 
-	txMiddleWare := []loomchain.TxMiddleware{
-		loomchain.LogTxMiddleware,
-		loomchain.RecoveryTxMiddleware,
-	}
+		ethTxHandler := &tx_handler.EthTxHandler{
+			Manager:        vmManager,
+			CreateRegistry: createRegistry,
+		}
 
-	postCommitMiddlewares := []loomchain.PostCommitMiddleware{
-		loomchain.LogPostCommitMiddleware,
-	}
+		migrationTxHandler := &tx_handler.MigrationTxHandler{
+			Manager:        vmManager,
+			CreateRegistry: createRegistry,
+			Migrations: map[int32]tx_handler.MigrationFunc{
+				1: migrations.DPOSv3Migration,
+				2: migrations.GatewayMigration,
+				3: migrations.GatewayMigration,
+				4: migrations.GatewayMigration,
+				5: migrations.GatewayMigration,
+			},
+		}
 
-	txMiddleWare = append(txMiddleWare, auth.NewChainConfigMiddleware(
-		cfg.Auth,
-		getContractStaticCtx("addressmapper", vmManager),
-	))
+		registryTxHandler := &tx_handler.RegistryTxHandler{
+			CreateRegistry: createRegistry,
+		}
 
-	createKarmaContractCtx := getContractCtx("karma", vmManager)
+		sponsorPolicyTxHandler := &tx_handler.SponsorPolicyTxHandler{}
 
-	if cfg.Karma.Enabled {
-		txMiddleWare = append(txMiddleWare, throttle.GetKarmaMiddleWare(
-			cfg.Karma.Enabled,
-			cfg.Karma.MaxCallCount,
-			cfg.Karma.SessionDuration,
-			createKarmaContractCtx,
-		))
-	}
+		router := loomchain.NewTxRouter()
 
-	if cfg.TxLimiter.Enabled {
-		txMiddleWare = append(txMiddleWare, throttle.NewTxLimiterMiddleware(cfg.TxLimiter))
-	}
+		router.HandleDeliverTx(1, loomchain.GeneratePassthroughRouteHandler(deployTxHandler))
+		router.HandleDeliverTx(2, loomchain.GeneratePassthroughRouteHandler(callTxHandler))
+		router.HandleDeliverTx(3, loomchain.GeneratePassthroughRouteHandler(migrationTxHandler))
+		router.HandleDeliverTx(4, loomchain.GeneratePassthroughRouteHandler(ethTxHandler))
+		router.HandleDeliverTx(
+			regcommon.RegistryTxID, loomchain.GeneratePassthroughRouteHandler(registryTxHandler),
+		)
+		router.HandleDeliverTx(
+			auth.SponsorPolicyTxID, loomchain.GeneratePassthroughRouteHandler(sponsorPolicyTxHandler),
+		)
 
-	if cfg.ContractTxLimiter.Enabled {
-		contextFactory := getContractCtx("user-deployer-whitelist", vmManager)
-		txMiddleWare = append(
-			txMiddleWare, throttle.NewContractTxLimiterMiddleware(cfg.ContractTxLimiter, contextFactory),
+		// TODO: Write this in more elegant way
+		router.HandleCheckTx(1, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, deployTxHandler))
+		router.HandleCheckTx(2, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, callTxHandler))
+		router.HandleCheckTx(3, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, migrationTxHandler))
+		router.HandleCheckTx(4, loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, ethTxHandler))
+		router.HandleCheckTx(
+			regcommon.RegistryTxID,
+			loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, registryTxHandler),
 		)
-	}
+		router.HandleCheckTx(
+			auth.SponsorPolicyTxID,
+			loomchain.GenerateConditionalRouteHandler(isEvmTx, loomchain.NoopTxHandler, sponsorPolicyTxHandler),
+		)
+		return router
+	}
+
+	// The nonce middleware writes nonces to nonceStore directly (rather than via the tx state) when
+	// IncNonceOnFailedTx is enabled. Tx metrics are only collected if instrumented is true.
+	// If origin isn't empty tx signatures aren't verified, and the txs are processed as if they were
+	// signed by origin, this must only be used when simulating txs.
+	createTxHandler := func(
+		vmManager *vm.Manager, nonceStore store.KVStore, instrumented bool, origin loom.Address,
+	) (loomchain.TxHandler, error) {
+		txMiddleWare := []loomchain.TxMiddleware{
+			loomchain.LogTxMiddleware,
+			loomchain.RecoveryTxMiddleware,
+		}
 
-	if cfg.DeployerWhitelist.ContractEnabled {
-		contextFactory := getContractCtx("deployerwhitelist", vmManager)
-		dwMiddleware, err := throttle.NewDeployerWhitelistMiddleware(contextFactory)
-		if err != nil {
-			return nil, err
+		postCommitMiddlewares := []loomchain.PostCommitMiddleware{
+			loomchain.LogPostCommitMiddleware,
+		}
+
+		if origin.IsEmpty() {
+			// Sponsored txs must be unwrapped before the user's signature is verified
+			txMiddleWare = append(txMiddleWare, auth.NewSponsoredTxMiddleware(
+				cfg.Auth,
+				getContractStaticCtx("addressmapper", vmManager),
+			))
+			txMiddleWare = append(txMiddleWare, auth.NewChainConfigMiddleware(
+				cfg.Auth,
+				getContractStaticCtx("addressmapper", vmManager),
+			))
+		} else {
+			txMiddleWare = append(txMiddleWare, auth.NewUnsignedTxMiddleware(origin))
+		}
+
+		if cfg.Karma.Enabled {
+			txMiddleWare = append(txMiddleWare, throttle.GetKarmaMiddleWare(
+				cfg.Karma.Enabled,
+				cfg.Karma.MaxCallCount,
+				cfg.Karma.SessionDuration,
+				getContractCtx("karma", vmManager),
+			))
 		}
-		txMiddleWare = append(txMiddleWare, dwMiddleware)
 
+		if cfg.TxLimiter.Enabled {
+			txMiddleWare = append(txMiddleWare, throttle.NewTxLimiterMiddleware(cfg.TxLimiter))
+		}
+
+		// The state tx limiter is part of consensus, so it must run on every node, it's enabled and
+		// configured on-chain.
+		txMiddleWare = append(txMiddleWare, throttle.NewStateTxLimiterMiddleware())
+
+		if cfg.ContractTxLimiter.Enabled {
+			contextFactory := getContractCtx("user-deployer-whitelist", vmManager)
+			txMiddleWare = append(
+				txMiddleWare, throttle.NewContractTxLimiterMiddleware(cfg.ContractTxLimiter, contextFactory),
+			)
+		}
+
+		if cfg.DeployerWhitelist.ContractEnabled {
+			contextFactory := getContractCtx("deployerwhitelist", vmManager)
+			dwMiddleware, err := throttle.NewDeployerWhitelistMiddleware(contextFactory)
+			if err != nil {
+				return nil, err
+			}
+			txMiddleWare = append(txMiddleWare, dwMiddleware)
+
+		}
+
+		if cfg.UserDeployerWhitelist.ContractEnabled {
+			contextFactory := getContractCtx("user-deployer-whitelist", vmManager)
+			evmDeployRecorderMiddleware, err := throttle.NewEVMDeployRecorderPostCommitMiddleware(contextFactory)
+			if err != nil {
+				return nil, err
+			}
+			postCommitMiddlewares = append(postCommitMiddlewares, evmDeployRecorderMiddleware)
+		}
+
+		nonceTxHandler := auth.NewNonceHandler()
+		txMiddleWare = append(txMiddleWare, nonceTxHandler.TxMiddleware(nonceStore))
+
+		if cfg.GoContractDeployerWhitelist.Enabled {
+			goDeployers, err := cfg.GoContractDeployerWhitelist.DeployerAddresses(chainID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "getting list of users allowed go deploys")
+			}
+			txMiddleWare = append(txMiddleWare, throttle.GetGoDeployTxMiddleWare(goDeployers))
+		}
+
+		if instrumented {
+			txMiddleWare = append(txMiddleWare, loomchain.NewInstrumentingTxMiddleware())
+		}
+
+		// We need to make sure nonce post commit middleware is last
+		// as it doesn't pass control to other middlewares after it.
+		postCommitMiddlewares = append(postCommitMiddlewares, nonceTxHandler.PostCommitMiddleware())
+
+		return loomchain.MiddlewareTxHandler(
+			txMiddleWare,
+			createRouter(vmManager),
+			postCommitMiddlewares,
+		), nil
 	}
 
-	if cfg.UserDeployerWhitelist.ContractEnabled {
-		contextFactory := getContractCtx("user-deployer-whitelist", vmManager)
-		evmDeployRecorderMiddleware, err := throttle.NewEVMDeployRecorderPostCommitMiddleware(contextFactory)
+	txHandler, err := createTxHandler(vmManager, appStore, true, loom.Address{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Creates a tx handler that re-executes txs without touching the receipts, events, or nonce
+	// cache of the live tx handler.
+	createReplayTxHandler := func(kvStore store.KVStore) (loomchain.TxHandler, error) {
+		eventHandler := loomchain.NewDefaultEventHandler(events.NewLogEventDispatcher())
+		receiptHandlerProvider := receipts.NewReceiptHandlerProvider(
+			eventHandler, cfg.EVMPersistentTxReceiptsMax, evmAuxStore,
+		)
+		txHandler, err := createTxHandler(
+			createVMManager(eventHandler, receiptHandlerProvider), kvStore, false, loom.Address{},
+		)
 		if err != nil {
 			return nil, err
 		}
-		postCommitMiddlewares = append(postCommitMiddlewares, evmDeployRecorderMiddleware)
+		return loomchain.TxHandlerFunc(func(
+			state loomchain.State, txBytes []byte, isCheckTx bool,
+		) (loomchain.TxHandlerResult, error) {
+			defer func() {
+				receiptHandlerProvider.Store().DiscardCurrentReceipt()
+				eventHandler.Rollback()
+			}()
+			return txHandler.ProcessTx(state, txBytes, isCheckTx)
+		}), nil
+	}
+
+	// Creates a tx handler that simulates txs without touching the receipts, events, or nonce cache
+	// of the live tx handler, the events emitted by the txs are posted to the given event handler.
+	createSimulateTxHandler := func(
+		kvStore store.KVStore, eventHandler loomchain.EventHandler, origin loom.Address,
+	) (loomchain.TxHandler, error) {
+		receiptHandlerProvider := receipts.NewReceiptHandlerProvider(
+			eventHandler, cfg.EVMPersistentTxReceiptsMax, evmAuxStore,
+		)
+		txHandler, err := createTxHandler(
+			createVMManager(eventHandler, receiptHandlerProvider), kvStore, false, origin,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return loomchain.TxHandlerFunc(func(
+			state loomchain.State, txBytes []byte, isCheckTx bool,
+		) (loomchain.TxHandlerResult, error) {
+			defer receiptHandlerProvider.Store().DiscardCurrentReceipt()
+			return txHandler.ProcessTx(state, txBytes, isCheckTx)
+		}), nil
 	}
 
+	createKarmaContractCtx := getContractCtx("karma", vmManager)
+
 	createContractUpkeepHandler := func(state loomchain.State) (loomchain.KarmaHandler, error) {
 		// TODO: This setting should be part of the config stored within the Karma contract itself,
 		//       that will allow us to switch the upkeep on & off via a tx.
@@ -1061,19 +1373,6 @@ func loadApp(
 		return loom.NewValidatorSet(b.GenesisValidators()...), nil
 	}
 
-	nonceTxHandler := auth.NewNonceHandler()
-	txMiddleWare = append(txMiddleWare, nonceTxHandler.TxMiddleware(appStore))
-
-	if cfg.GoContractDeployerWhitelist.Enabled {
-		goDeployers, err := cfg.GoContractDeployerWhitelist.DeployerAddresses(chainID)
-		if err != nil {
-			return nil, errors.Wrapf(err, "getting list of users allowed go deploys")
-		}
-		txMiddleWare = append(txMiddleWare, throttle.GetGoDeployTxMiddleWare(goDeployers))
-	}
-
-	txMiddleWare = append(txMiddleWare, loomchain.NewInstrumentingTxMiddleware())
-
 	createValidatorsManager := func(state loomchain.State) (loomchain.ValidatorsManager, error) {
 		pvm, err := vmManager.InitVM(vm.VMType_PLUGIN, state)
 		if err != nil {
@@ -1128,18 +1427,12 @@ func loadApp(
 		}
 	}
 
-	// We need to make sure nonce post commit middleware is last
-	// as it doesn't pass control to other middlewares after it.
-	postCommitMiddlewares = append(postCommitMiddlewares, nonceTxHandler.PostCommitMiddleware())
-
 	return &loomchain.Application{
-		Store: appStore,
-		Init:  init,
-		TxHandler: loomchain.MiddlewareTxHandler(
-			txMiddleWare,
-			router,
-			postCommitMiddlewares,
-		),
+		Store:                       appStore,
+		Init:                        init,
+		TxHandler:                   txHandler,
+		CreateReplayTxHandler:       createReplayTxHandler,
+		CreateSimulateTxHandler:     createSimulateTxHandler,
 		BlockIndexStore:             blockIndexStore,
 		EventHandler:                eventHandler,
 		ReceiptHandlerProvider:      receiptHandlerProvider,
@@ -1231,6 +1524,7 @@ func initBackend(cfg *config.Config, abciServerAddr string, fnRegistry fnConsens
 		RPCProxyPort:             cfg.RPCProxyPort,
 		CreateEmptyBlocks:        cfg.CreateEmptyBlocks,
 		HsmConfig:                cfg.HsmConfig,
+		RemoteSignerConfig:       cfg.RemoteSigner,
 		FnConsensusReactorConfig: cfg.FnConsensus.Reactor,
 		MempoolWalEnabled:        cfg.MempoolWalEnabled,
 	}
@@ -1245,6 +1539,7 @@ func initBackend(cfg *config.Config, abciServerAddr string, fnRegistry fnConsens
 func initQueryService(
 	app *loomchain.Application, chainID string, cfg *config.Config, loader plugin.Loader,
 	receiptHandlerProvider loomchain.ReceiptHandlerProvider,
+	fnConsensusReactor *fnConsensus.FnConsensusReactor,
 ) error {
 	// metrics
 	fieldKeys := []string{"method", "error"}
@@ -1300,6 +1595,9 @@ func initQueryService(
 		EvmAuxStore:            app.EvmAuxStore,
 		Web3Cfg:                cfg.Web3,
 		DPOSCfg:                cfg.DPOS,
+		TxReplayer:             app,
+		TxSimulator:            app,
+		FnConsensusReactor:     fnConsensusReactor,
 	}
 	bus := &rpc.QueryEventBus{
 		Subs:    *app.EventHandler.SubscriptionSet(),
@@ -1307,7 +1605,11 @@ func initQueryService(
 	}
 	var qsvc rpc.QueryService = rpc.NewInstrumentingMiddleWare(requestCount, requestLatency, qs)
 	logger := log.Root.With("module", "query-server")
-	err = rpc.RPCServer(qsvc, chainID, logger, bus, cfg.RPCBindAddress, cfg.UnsafeRPCEnabled, cfg.UnsafeRPCBindAddress)
+	pluginLoader, _ := loader.(plugin.ReloadableLoader)
+	err = rpc.RPCServer(
+		qsvc, chainID, logger, bus, cfg.RPCBindAddress, cfg.UnsafeRPCEnabled, cfg.UnsafeRPCBindAddress, cfg.Web3,
+		app.EventStore, pluginLoader,
+	)
 	if err != nil {
 		return err
 	}
@@ -1365,6 +1667,14 @@ func main() {
 		userdeployer.NewUserDeployCommand(),
 		dbg.NewDebugCommand(),
 		contractInfoCommand(),
+		contractVersionsCommand(),
+		contractUpgradeCommand(),
+		contractTransferCommand(),
+		newFnConsensusCommand(),
+		newReplayCommand(),
+		newPluginsCommand(),
+		newMultiSigCommand(),
+		newSponsorCommand(),
 	)
 	err := RootCmd.Execute()
 	if err != nil {
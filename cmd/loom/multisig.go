@@ -0,0 +1,405 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/loomnetwork/go-loom"
+	glAuth "github.com/loomnetwork/go-loom/auth"
+	"github.com/loomnetwork/go-loom/cli"
+	"github.com/loomnetwork/go-loom/client"
+	"github.com/loomnetwork/go-loom/types"
+	"github.com/loomnetwork/go-loom/vm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/loomnetwork/loomchain/auth"
+)
+
+// multiSigTxFile holds a tx that should be signed by a multisig account, along with the partial
+// signatures collected so far. The file is passed around between the account key holders so each
+// one can add their signature offline, before the tx is broadcast.
+type multiSigTxFile struct {
+	Account *auth.MultiSigAccount `json:"account"`
+	// Serialized NonceTx
+	Tx         []byte                   `json:"tx"`
+	Signatures []*auth.PartialSignature `json:"signatures"`
+}
+
+func readMultiSigTxFile(path string) (*multiSigTxFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var txFile multiSigTxFile
+	if err := json.Unmarshal(data, &txFile); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	if err := auth.ValidateMultiSigAccount(txFile.Account); err != nil {
+		return nil, errors.Wrapf(err, "invalid multisig account in %s", path)
+	}
+	return &txFile, nil
+}
+
+func writeMultiSigTxFile(path string, txFile *multiSigTxFile) error {
+	data, err := json.MarshalIndent(txFile, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// addSignature adds the given partial signature to the file, replacing any previous signature by
+// the same key.
+func (f *multiSigTxFile) addSignature(sig *auth.PartialSignature) {
+	for i, s := range f.Signatures {
+		if s.KeyIndex == sig.KeyIndex {
+			f.Signatures[i] = sig
+			return
+		}
+	}
+	f.Signatures = append(f.Signatures, sig)
+}
+
+func newMultiSigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "multisig <command>",
+		Short: "Create, sign & broadcast txs from multisig accounts",
+	}
+	cmd.AddCommand(
+		newMultiSigAccountCommand(),
+		newMultiSigCallTxCommand(),
+		newMultiSigSignCommand(),
+		newMultiSigCombineCommand(),
+		newMultiSigBroadcastCommand(),
+	)
+	return cmd
+}
+
+const multiSigAccountCmdExample = `
+loom multisig account 2 loom:0x2a6b071aD396cEFdd16c731454af0d8c95ECD4B2 eth:0x5cecd1f7261e1f4c684e297be3edf03b825e01c4 \
+  tron:0x9a1aC42a17AAD6Dbc6d21c162989d0f701074044 -o account.json
+`
+
+func newMultiSigAccountCommand() *cobra.Command {
+	var chainID, outFile string
+	cmd := &cobra.Command{
+		Use:     "account <threshold> <sig type:address>...",
+		Short:   "Create a multisig account from a threshold & a list of keys (sig type is loom, eth, tron, or binance)",
+		Example: multiSigAccountCmdExample,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			threshold, err := strconv.ParseUint(args[0], 10, 32)
+			if err != nil {
+				return errors.Wrap(err, "invalid threshold")
+			}
+			var keys []*auth.MultiSigKey
+			for _, arg := range args[1:] {
+				parts := strings.SplitN(arg, ":", 2)
+				if len(parts) != 2 {
+					return errors.Errorf("invalid key %s, expected <sig type>:<address>", arg)
+				}
+				local, err := loom.LocalAddressFromHexString(parts[1])
+				if err != nil {
+					return errors.Wrapf(err, "invalid key address %s", parts[1])
+				}
+				keys = append(keys, &auth.MultiSigKey{SigType: parts[0], Local: local})
+			}
+			account, err := auth.NewMultiSigAccount(keys, uint32(threshold))
+			if err != nil {
+				return err
+			}
+			local, err := auth.MultiSigAddress(account)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Multisig account address: %s\n", loom.Address{ChainID: chainID, Local: local}.String())
+			if outFile != "" {
+				return writeMultiSigTxFile(outFile, &multiSigTxFile{Account: account})
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&chainID, "chain", "default", "chain ID")
+	cmd.Flags().StringVarP(&outFile, "output", "o", "", "write the account to a file that can be passed to call-tx")
+	return cmd
+}
+
+const multiSigCallTxCmdExample = `
+loom multisig call-tx -a account.json -c 0x9a1aC42a17AAD6Dbc6d21c162989d0f701074044 -i input.hex -o tx.json
+`
+
+func newMultiSigCallTxCommand() *cobra.Command {
+	var accountFile, contractAddr, inputFile, vmName, outFile string
+	cmd := &cobra.Command{
+		Use:     "call-tx",
+		Short:   "Create an unsigned call tx from a multisig account",
+		Example: multiSigCallTxCmdExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFile, err := readMultiSigTxFile(accountFile)
+			if err != nil {
+				return err
+			}
+			local, err := auth.MultiSigAddress(txFile.Account)
+			if err != nil {
+				return err
+			}
+			from := loom.Address{ChainID: cli.TxFlags.ChainID, Local: local}
+			to, err := cli.ParseAddress(contractAddr, cli.TxFlags.ChainID)
+			if err != nil {
+				return errors.Wrap(err, "invalid contract address")
+			}
+
+			var vmType vm.VMType
+			switch strings.ToLower(vmName) {
+			case "evm":
+				vmType = vm.VMType_EVM
+			case "plugin":
+				vmType = vm.VMType_PLUGIN
+			default:
+				return errors.Errorf("unsupported VM type %s", vmName)
+			}
+			input, err := ioutil.ReadFile(inputFile)
+			if err != nil {
+				return err
+			}
+			input, err = hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(input)), "0x"))
+			if err != nil {
+				return errors.Wrap(err, "invalid input data")
+			}
+
+			nonce, err := getAccountNonce(from)
+			if err != nil {
+				return err
+			}
+			callTxBytes, err := proto.Marshal(&vm.CallTx{VmType: vmType, Input: input})
+			if err != nil {
+				return err
+			}
+			msgTxBytes, err := proto.Marshal(&vm.MessageTx{
+				From: from.MarshalPB(),
+				To:   to.MarshalPB(),
+				Data: callTxBytes,
+			})
+			if err != nil {
+				return err
+			}
+			txBytes, err := proto.Marshal(&types.Transaction{
+				Id:   uint32(types.TxID_CALL),
+				Data: msgTxBytes,
+			})
+			if err != nil {
+				return err
+			}
+			txFile.Tx, err = proto.Marshal(&glAuth.NonceTx{Inner: txBytes, Sequence: nonce + 1})
+			if err != nil {
+				return err
+			}
+			txFile.Signatures = nil
+			if err := writeMultiSigTxFile(outFile, txFile); err != nil {
+				return err
+			}
+			fmt.Printf("Unsigned tx from %s written to %s\n", from.String(), outFile)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&accountFile, "account", "a", "", "multisig account file")
+	cmd.Flags().StringVarP(&contractAddr, "contract-addr", "c", "", "contract address")
+	cmd.Flags().StringVarP(&inputFile, "input", "i", "", "file with hex-encoded input data")
+	cmd.Flags().StringVar(&vmName, "vm", "evm", "type of contract to call, evm or plugin")
+	cmd.Flags().StringVarP(&outFile, "output", "o", "tx.json", "output file")
+	setChainFlags(cmd.Flags())
+	return cmd
+}
+
+func getAccountNonce(addr loom.Address) (uint64, error) {
+	var rawJSON json.RawMessage
+	rpcclient := client.NewJSONRPCClient(cli.TxFlags.URI + "/query")
+	params := map[string]interface{}{"key": "", "account": addr.String()}
+	if err := rpcclient.Call("nonce", params, "1", &rawJSON); err != nil {
+		return 0, errors.Wrapf(err, "failed to get nonce of %s", addr.String())
+	}
+	var nonce uint64
+	if err := amino.NewCodec().UnmarshalJSON(rawJSON, &nonce); err != nil {
+		return 0, errors.Wrap(err, "failed to decode nonce")
+	}
+	return nonce, nil
+}
+
+const multiSigSignCmdExample = `
+loom multisig sign tx.json -k priv_key --algo secp256k1
+`
+
+func newMultiSigSignCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "sign <tx file>",
+		Short:   "Add a signature to a multisig tx, the tx file is updated in place",
+		Example: multiSigSignCmdExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFile, err := readMultiSigTxFile(args[0])
+			if err != nil {
+				return err
+			}
+			if len(txFile.Tx) == 0 {
+				return errors.New("tx file doesn't contain a tx")
+			}
+
+			var sigType auth.SignedTxType
+			switch cli.TxFlags.Algo {
+			case "ed25519":
+				sigType = auth.LoomSignedTxType
+			case "secp256k1":
+				sigType = auth.EthereumSignedTxType
+			case "tron":
+				sigType = auth.TronSignedTxType
+			case "binance":
+				sigType = auth.BinanceSignedTxType
+			default:
+				return errors.Errorf("unsupported signing algo %s", cli.TxFlags.Algo)
+			}
+			signerAddr, signer, err := caller(cli.TxFlags.PrivFile, "", cli.TxFlags.Algo, cli.TxFlags.ChainID)
+			if err != nil {
+				return err
+			}
+			if signer == nil {
+				return fmt.Errorf("invalid private key")
+			}
+
+			for i, key := range txFile.Account.Keys {
+				if key.SigType != string(sigType) || signerAddr.Local.Compare(key.Local) != 0 {
+					continue
+				}
+				sig := &auth.PartialSignature{
+					KeyIndex:  uint32(i),
+					Signature: signer.Sign(txFile.Tx),
+				}
+				if sigType == auth.LoomSignedTxType {
+					sig.PublicKey = signer.PublicKey()
+				}
+				txFile.addSignature(sig)
+				if err := writeMultiSigTxFile(args[0], txFile); err != nil {
+					return err
+				}
+				fmt.Printf("Signed by key %d, %d of %d required signatures collected\n",
+					i, len(txFile.Signatures), txFile.Account.Threshold,
+				)
+				return nil
+			}
+			return errors.Errorf("key %s isn't part of the multisig account", signerAddr.Local.String())
+		},
+	}
+	cmd.Flags().StringVarP(&cli.TxFlags.PrivFile, "key", "k", "", "private key file")
+	cmd.Flags().StringVar(&cli.TxFlags.Algo, "algo", "ed25519", "Signing algo: ed25519, secp256k1, tron, binance")
+	return cmd
+}
+
+const multiSigCombineCmdExample = `
+loom multisig combine tx-alice.json tx-bob.json -o tx.json
+`
+
+func newMultiSigCombineCommand() *cobra.Command {
+	var outFile string
+	cmd := &cobra.Command{
+		Use:     "combine <tx file>...",
+		Short:   "Combine the signatures from copies of the same multisig tx signed by different keys",
+		Example: multiSigCombineCmdExample,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			combined, err := readMultiSigTxFile(args[0])
+			if err != nil {
+				return err
+			}
+			for _, path := range args[1:] {
+				txFile, err := readMultiSigTxFile(path)
+				if err != nil {
+					return err
+				}
+				if !proto.Equal(combined.Account, txFile.Account) || string(combined.Tx) != string(txFile.Tx) {
+					return errors.Errorf("%s doesn't contain the same tx as %s", path, args[0])
+				}
+				for _, sig := range txFile.Signatures {
+					combined.addSignature(sig)
+				}
+			}
+			if err := writeMultiSigTxFile(outFile, combined); err != nil {
+				return err
+			}
+			fmt.Printf("%d of %d required signatures collected\n", len(combined.Signatures), combined.Account.Threshold)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outFile, "output", "o", "tx.json", "output file")
+	return cmd
+}
+
+const multiSigBroadcastCmdExample = `
+loom multisig broadcast tx.json -u http://localhost:46658
+`
+
+func newMultiSigBroadcastCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "broadcast <tx file>",
+		Short:   "Broadcast a multisig tx once enough signatures have been collected",
+		Example: multiSigBroadcastCmdExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFile, err := readMultiSigTxFile(args[0])
+			if err != nil {
+				return err
+			}
+			if len(txFile.Signatures) < int(txFile.Account.Threshold) {
+				return errors.Errorf(
+					"not enough signatures, got %d, need %d", len(txFile.Signatures), txFile.Account.Threshold,
+				)
+			}
+			multiSigBytes, err := proto.Marshal(&auth.MultiSignature{
+				Account:    txFile.Account,
+				Signatures: txFile.Signatures,
+			})
+			if err != nil {
+				return err
+			}
+			signedTxBytes, err := proto.Marshal(&glAuth.SignedTx{
+				Inner:     txFile.Tx,
+				Signature: multiSigBytes,
+			})
+			if err != nil {
+				return err
+			}
+
+			return broadcastTxCommit(signedTxBytes)
+		},
+	}
+	setChainFlags(cmd.Flags())
+	return cmd
+}
+
+// broadcastTxCommit broadcasts a serialized tx and waits for it to be committed.
+func broadcastTxCommit(txBytes []byte) error {
+	var rawJSON json.RawMessage
+	rpcclient := client.NewJSONRPCClient(cli.TxFlags.URI + "/rpc")
+	params := map[string]interface{}{"tx": txBytes}
+	if err := rpcclient.Call("broadcast_tx_commit", params, "1", &rawJSON); err != nil {
+		return errors.Wrap(err, "failed to broadcast tx")
+	}
+	var result ctypes.ResultBroadcastTxCommit
+	if err := amino.NewCodec().UnmarshalJSON(rawJSON, &result); err != nil {
+		return errors.Wrap(err, "failed to decode broadcast result")
+	}
+	if result.CheckTx.IsErr() {
+		return errors.Errorf("CheckTx failed: %s", result.CheckTx.Log)
+	}
+	if result.DeliverTx.IsErr() {
+		return errors.Errorf("DeliverTx failed: %s", result.DeliverTx.Log)
+	}
+	fmt.Printf("Tx %s committed at height %d\n", result.Hash.String(), result.Height)
+	return nil
+}
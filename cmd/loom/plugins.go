@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/loomnetwork/go-loom/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
+
+	"github.com/loomnetwork/loomchain/plugin"
+	"github.com/loomnetwork/loomchain/rpc"
+)
+
+const defaultUnsafeRPCURI = "http://127.0.0.1:26680"
+
+func newPluginsCommand() *cobra.Command {
+	var unsafeURI string
+	cmd := &cobra.Command{
+		Use:   "plugins <command>",
+		Short: "Manage the external plugins loaded by a node (requires the unsafe RPC to be enabled)",
+	}
+	cmd.PersistentFlags().StringVarP(&unsafeURI, "unsafe-uri", "u", defaultUnsafeRPCURI, "Unsafe RPC URI of the node")
+	cmd.AddCommand(
+		newListPluginsCommand(&unsafeURI),
+		newReloadPluginsCommand(&unsafeURI),
+	)
+	return cmd
+}
+
+const listPluginsCmdExample = `
+loom plugins list -u http://127.0.0.1:26680
+`
+
+func newListPluginsCommand(unsafeURI *string) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the external plugins in the plugins dir of the node",
+		Example: listPluginsCmdExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var rawJSON json.RawMessage
+			rpcclient := client.NewJSONRPCClient(*unsafeURI)
+			if err := rpcclient.Call("unsafe_list_plugins", map[string]interface{}{}, "1", &rawJSON); err != nil {
+				return errors.Wrap(err, "failed to list plugins")
+			}
+			var plugins []*plugin.ExternalPluginInfo
+			if err := amino.NewCodec().UnmarshalJSON(rawJSON, &plugins); err != nil {
+				return errors.Wrap(err, "failed to decode plugin list")
+			}
+
+			if len(plugins) == 0 {
+				fmt.Println("No external plugins found.")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(w, "Name\tVersion\tFile\tLoaded")
+			for _, p := range plugins {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", p.Name, p.Version, p.File, p.Loaded)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+const reloadPluginsCmdExample = `
+loom plugins reload -u http://127.0.0.1:26680
+`
+
+func newReloadPluginsCommand(unsafeURI *string) *cobra.Command {
+	return &cobra.Command{
+		Use:     "reload",
+		Short:   "Unload external plugins whose executables have been updated, so they're reloaded on next use",
+		Example: reloadPluginsCmdExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var rawJSON json.RawMessage
+			rpcclient := client.NewJSONRPCClient(*unsafeURI)
+			if err := rpcclient.Call("unsafe_reload_plugins", map[string]interface{}{}, "1", &rawJSON); err != nil {
+				return errors.Wrap(err, "failed to reload plugins")
+			}
+			var result rpc.ReloadPluginsResult
+			if err := amino.NewCodec().UnmarshalJSON(rawJSON, &result); err != nil {
+				return errors.Wrap(err, "failed to decode reload result")
+			}
+
+			if len(result.Unloaded) == 0 {
+				fmt.Println("All loaded plugins are up to date.")
+				return nil
+			}
+			for _, name := range result.Unloaded {
+				fmt.Printf("Reloaded %s\n", name)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/abci/backend"
+	"github.com/loomnetwork/loomchain/cmd/loom/common"
+	"github.com/loomnetwork/loomchain/cmd/loom/replay"
+	"github.com/loomnetwork/loomchain/events"
+	"github.com/loomnetwork/loomchain/log"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/blockchain"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+const replayCmdLong = `
+Loads the app state at height N from app.db, re-executes blocks N+1 to M from the Tendermint block
+store, and checks that the app hash computed for each block matches the app hash stored in the
+header of the next block. If the app hashes diverge the replay stops, and the keys written by each
+tx in the diverging block are displayed (if the results of any of the txs differ from the results
+stored in the Tendermint state DB only the write sets of those txs are displayed).
+
+The replayed blocks are committed to app.db, so this command should only be run on a copy of the
+node's data directory. If app.db already contains a different version of a diverging block the
+replay will stop when the block is committed.
+`
+
+const replayCmdExample = `
+loom replay --from 12345 --to 12400
+`
+
+func newReplayCommand() *cobra.Command {
+	var fromHeight, toHeight int64
+	var dumpValues, logEthDbBatch bool
+
+	cfg, err := common.ParseConfig()
+	cmd := &cobra.Command{
+		Use:     "replay",
+		Short:   "Re-execute blocks from the block store & check the resulting app hashes",
+		Long:    replayCmdLong,
+		Example: replayCmdExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err != nil {
+				return err
+			}
+			if fromHeight < 0 {
+				return errors.New("invalid --from height")
+			}
+			cfg = replay.OverrideConfig(cfg, fromHeight+1)
+			log.Setup(cfg.LoomLogLevel, cfg.LogDestination)
+			configureGeth(cfg.Geth)
+
+			// The write set of each tx is recorded by the LogStore, so it must be the outermost
+			// app store.
+			cfg.LogStateDB = true
+			cfg.CachingStoreConfig.CachingEnabled = false
+			cfg.LogEthDbBatch = logEthDbBatch
+			// Events & block hashes were already indexed when the blocks were originally executed.
+			cfg.EventDispatcher.Dispatcher = events.DispatcherLog
+			cfg.BlockIndexStore.Enabled = false
+
+			b := initBackend(cfg, "", nil).(*backend.TendermintBackend)
+			chainID, err := b.ChainID()
+			if err != nil {
+				return err
+			}
+			if err := b.LoadGenesisValidators(); err != nil {
+				return err
+			}
+
+			dataDir := path.Join(cfg.RootPath(), "chaindata", "data")
+			blockStoreDB := dbm.NewDB("blockstore", "leveldb", dataDir)
+			defer blockStoreDB.Close()
+			stateDB := dbm.NewDB("state", "leveldb", dataDir)
+			defer stateDB.Close()
+
+			blockStore := blockchain.NewBlockStore(blockStoreDB)
+			// The app hash of the last block is stored in the header of the following block.
+			maxHeight := blockStore.Height() - 1
+			if toHeight == 0 {
+				toHeight = maxHeight
+			}
+			if toHeight <= fromHeight || toHeight > maxHeight {
+				return fmt.Errorf(
+					"invalid block range %d-%d, latest block that can be replayed is %d",
+					fromHeight+1, toHeight, maxHeight,
+				)
+			}
+
+			loader := newContractLoader(cfg)
+			defer loader.UnloadContracts()
+
+			app, err := loadApp(chainID, cfg, loader, b, fromHeight)
+			if err != nil {
+				return err
+			}
+			logStore, ok := app.Store.(*store.LogStore)
+			if !ok {
+				return errors.New("failed to access app store write sets")
+			}
+
+			if fromHeight == 0 {
+				app.InitChain(abci.RequestInitChain{ChainId: chainID})
+			} else {
+				// Sanity check the state the blocks are going to be executed on top of.
+				appHash := app.Info(abci.RequestInfo{}).LastBlockAppHash
+				if app.Store.Version() != fromHeight {
+					return fmt.Errorf("failed to load app state at height %d", fromHeight)
+				}
+				expected := blockStore.LoadBlockMeta(fromHeight + 1).Header.AppHash
+				if !bytes.Equal(appHash, expected) {
+					return fmt.Errorf(
+						"app hash at height %d is %X, but block %d expects %X",
+						fromHeight, appHash, fromHeight+1, expected,
+					)
+				}
+			}
+
+			r := &blockReplayer{
+				app:        app,
+				logStore:   logStore,
+				blockStore: blockStore,
+				stateDB:    stateDB,
+				dumpValues: dumpValues,
+			}
+			for height := fromHeight + 1; height <= toHeight; height++ {
+				if err := r.replayBlock(height); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("Replayed blocks %d-%d, no app hash divergence found\n", fromHeight+1, toHeight)
+			return nil
+		},
+	}
+	cmdFlags := cmd.Flags()
+	cmdFlags.Int64Var(&fromHeight, "from", 0, "Height of the app state the blocks should be executed on top of")
+	cmdFlags.Int64Var(
+		&toHeight, "to", 0, "Height of the last block to replay (defaults to the latest block that can be checked)",
+	)
+	cmdFlags.BoolVar(&dumpValues, "dump-values", false, "Display the values written by the diverging txs")
+	cmdFlags.BoolVar(&logEthDbBatch, "log-evm-batch", false, "Log the EVM state DB batches to a file")
+	cmd.MarkFlagRequired("from")
+	return cmd
+}
+
+// replayedTx tracks the changes made to the app store by a single phase of block execution, i.e.
+// BeginBlock, EndBlock, or a tx.
+type replayedTx struct {
+	desc     string
+	writeSet []store.WriteSetEntry
+	// Describes how the result of the tx differs from the result stored in the Tendermint state DB,
+	// empty if they match (or the original result is unknown).
+	mismatch string
+}
+
+type blockReplayer struct {
+	app        *loomchain.Application
+	logStore   *store.LogStore
+	blockStore *blockchain.BlockStore
+	stateDB    dbm.DB
+	dumpValues bool
+}
+
+func (r *blockReplayer) replayBlock(height int64) error {
+	block := r.blockStore.LoadBlock(height)
+	if block == nil {
+		return fmt.Errorf("block %d not found in block store", height)
+	}
+	commitInfo, byzVals, err := r.beginBlockValidatorInfo(block)
+	if err != nil {
+		return err
+	}
+	// The ABCI responses may have been pruned, in which case there's nothing to compare against.
+	abciResponses, err := sm.LoadABCIResponses(r.stateDB, height)
+	if err != nil {
+		abciResponses = nil
+	}
+
+	r.logStore.RecordWriteSet()
+	r.app.BeginBlock(abci.RequestBeginBlock{
+		Hash:                block.Hash(),
+		Header:              types.TM2PB.Header(&block.Header),
+		LastCommitInfo:      commitInfo,
+		ByzantineValidators: byzVals,
+	})
+	replayed := []*replayedTx{{desc: "BeginBlock", writeSet: r.logStore.TakeWriteSet()}}
+
+	for i, tx := range block.Txs {
+		res := r.app.DeliverTx(tx)
+		rtx := &replayedTx{
+			desc:     fmt.Sprintf("tx %d (%X)", i, tx.Hash()),
+			writeSet: r.logStore.TakeWriteSet(),
+		}
+		if abciResponses != nil && i < len(abciResponses.DeliverTx) {
+			orig := abciResponses.DeliverTx[i]
+			if orig.Code != res.Code || !bytes.Equal(orig.Data, res.Data) {
+				rtx.mismatch = fmt.Sprintf(
+					"expected code %d & data %X, got code %d & data %X (%s)",
+					orig.Code, orig.Data, res.Code, res.Data, res.Log,
+				)
+			}
+		}
+		replayed = append(replayed, rtx)
+	}
+
+	r.app.EndBlock(abci.RequestEndBlock{Height: height})
+	replayed = append(replayed, &replayedTx{desc: "EndBlock", writeSet: r.logStore.TakeWriteSet()})
+
+	appHash, err := r.commit()
+	expectedAppHash := r.blockStore.LoadBlockMeta(height + 1).Header.AppHash
+	if err == nil && bytes.Equal(appHash, expectedAppHash) {
+		fmt.Printf("Block %d: %d txs, app hash %X\n", height, len(block.Txs), appHash)
+		return nil
+	}
+
+	if err != nil {
+		fmt.Printf("Block %d: failed to commit, %v\n", height, err)
+	} else {
+		fmt.Printf("Block %d: expected app hash %X, got %X\n", height, expectedAppHash, appHash)
+	}
+	r.dumpWriteSets(replayed)
+	return fmt.Errorf("app hash diverged at height %d", height)
+}
+
+// commit commits the current block to the app store, SaveVersion errors (such as a conflicting
+// version already being in app.db) cause Application.Commit to panic, so they're recovered here.
+func (r *blockReplayer) commit() (appHash []byte, err error) {
+	defer func() {
+		if rval := recover(); rval != nil {
+			err = fmt.Errorf("%v", rval)
+		}
+	}()
+	return r.app.Commit().Data, nil
+}
+
+// beginBlockValidatorInfo reconstructs the validator info Tendermint passes to BeginBlock.
+func (r *blockReplayer) beginBlockValidatorInfo(block *types.Block) (abci.LastCommitInfo, []abci.Evidence, error) {
+	var commitInfo abci.LastCommitInfo
+	if block.Height > 1 {
+		lastValSet, err := sm.LoadValidators(r.stateDB, block.Height-1)
+		if err != nil {
+			return commitInfo, nil, errors.Wrapf(err, "failed to load validators at height %d", block.Height-1)
+		}
+		votes := make([]abci.VoteInfo, lastValSet.Size())
+		for i, val := range lastValSet.Validators {
+			var vote *types.Vote
+			if i < len(block.LastCommit.Precommits) {
+				vote = block.LastCommit.Precommits[i]
+			}
+			votes[i] = abci.VoteInfo{
+				Validator:       types.TM2PB.Validator(val),
+				SignedLastBlock: vote != nil,
+			}
+		}
+		commitInfo = abci.LastCommitInfo{
+			Round: int32(block.LastCommit.Round()),
+			Votes: votes,
+		}
+	}
+
+	byzVals := make([]abci.Evidence, len(block.Evidence.Evidence))
+	for i, ev := range block.Evidence.Evidence {
+		valSet, err := sm.LoadValidators(r.stateDB, ev.Height())
+		if err != nil {
+			return commitInfo, nil, errors.Wrapf(err, "failed to load validators at height %d", ev.Height())
+		}
+		byzVals[i] = types.TM2PB.Evidence(ev, valSet, block.Time)
+	}
+	return commitInfo, byzVals, nil
+}
+
+// dumpWriteSets displays the write sets of the txs whose results don't match the original results,
+// or the write sets of all the txs in the block if the divergence can't be narrowed down to a tx.
+func (r *blockReplayer) dumpWriteSets(replayed []*replayedTx) {
+	var diverged []*replayedTx
+	for _, rtx := range replayed {
+		if rtx.mismatch != "" {
+			diverged = append(diverged, rtx)
+		}
+	}
+	if len(diverged) == 0 {
+		fmt.Println("Results of all the txs match the original results, dumping the whole block")
+		diverged = replayed
+	}
+	for _, rtx := range diverged {
+		fmt.Printf("\n%s wrote %d keys\n", rtx.desc, len(rtx.writeSet))
+		if rtx.mismatch != "" {
+			fmt.Printf("  result mismatch: %s\n", rtx.mismatch)
+		}
+		for _, entry := range rtx.writeSet {
+			if entry.Deleted {
+				fmt.Printf("  delete %q\n", entry.Key)
+			} else if r.dumpValues {
+				fmt.Printf("  set %q = %X\n", entry.Key, entry.Value)
+			} else {
+				fmt.Printf("  set %q (%d bytes)\n", entry.Key, len(entry.Value))
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/loomnetwork/loomchain/config"
 	"github.com/loomnetwork/loomchain/registry"
+	lvm "github.com/loomnetwork/loomchain/vm"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -33,6 +35,7 @@ type deployTxFlags struct {
 	PrivFile   string `json:"privfile"`
 	Name       string `json:"name"`
 	Value      string `json:"value"`
+	VM         string `json:"vm"`
 }
 
 func setChainFlags(fs *pflag.FlagSet) {
@@ -181,9 +184,13 @@ func newDeployCommand() *cobra.Command {
 			if callerChainID == "" {
 				callerChainID = cli.TxFlags.ChainID
 			}
+			vmType, err := parseDeployVMType(flags.VM)
+			if err != nil {
+				return err
+			}
 			addr, runBytecode, txReceipt, err := deployTx(
 				flags.Bytecode, cli.TxFlags.PrivFile, flags.PublicFile, flags.Name, cli.TxFlags.Algo,
-				callerChainID, flags.Value,
+				callerChainID, flags.Value, vmType,
 			)
 			if err != nil {
 				return err
@@ -199,12 +206,27 @@ func newDeployCommand() *cobra.Command {
 	deployCmd.Flags().StringVarP(&flags.Name, "name", "n", "", "contract name")
 	deployCmd.Flags().StringVarP(&cli.TxFlags.PrivFile, "key", "k", "", "private key file")
 	deployCmd.Flags().StringVarP(&flags.Value, "value", "v", "0", "value amount")
+	deployCmd.Flags().StringVar(&flags.VM, "vm", "evm", "type of contract to deploy, evm or wasm")
 	setChainFlags(deployCmd.Flags())
 	return deployCmd
 }
 
+func parseDeployVMType(name string) (vm.VMType, error) {
+	switch strings.ToLower(name) {
+	case "", "evm":
+		return vm.VMType_EVM, nil
+	case "wasm":
+		return lvm.VMType_WASM, nil
+	default:
+		return vm.VMType_EVM, errors.Errorf("unsupported VM type %s", name)
+	}
+}
+
+// wasmMagic is the header of binary WebAssembly modules.
+var wasmMagic = []byte("\x00asm")
+
 func deployTx(
-	bcFile, privFile, pubFile, name, algo, callerChainID, valueString string,
+	bcFile, privFile, pubFile, name, algo, callerChainID, valueString string, vmType vm.VMType,
 ) (loom.Address, []byte, []byte, error) {
 	clientAddr, signer, err := caller(privFile, pubFile, algo, callerChainID)
 	if err != nil {
@@ -218,12 +240,18 @@ func deployTx(
 	if err != nil {
 		return *new(loom.Address), nil, nil, errors.Wrapf(err, "reading deployment file")
 	}
-	if string(bytetext[0:2]) == "0x" {
-		bytetext = bytetext[2:]
-	}
-	bytecode, err := hex.DecodeString(string(bytetext))
-	if err != nil {
-		return *new(loom.Address), nil, nil, errors.Wrapf(err, "decoding the data in deployment file")
+	var bytecode []byte
+	if vmType == lvm.VMType_WASM && bytes.HasPrefix(bytetext, wasmMagic) {
+		// WASM modules can be deployed straight from the binary file
+		bytecode = bytetext
+	} else {
+		if string(bytetext[0:2]) == "0x" {
+			bytetext = bytetext[2:]
+		}
+		bytecode, err = hex.DecodeString(string(bytetext))
+		if err != nil {
+			return *new(loom.Address), nil, nil, errors.Wrapf(err, "decoding the data in deployment file")
+		}
 	}
 
 	value := big.NewInt(0)
@@ -237,7 +265,7 @@ func deployTx(
 	}
 
 	rpcclient := client.NewDAppChainRPCClient(cli.TxFlags.ChainID, cli.TxFlags.URI+"/rpc", cli.TxFlags.URI+"/query")
-	respB, err := rpcclient.CommitDeployTxWithValue(clientAddr, signer, vm.VMType_EVM, bytecode, name, value)
+	respB, err := rpcclient.CommitDeployTxWithValue(clientAddr, signer, vmType, bytecode, name, value)
 	if err != nil {
 		return *new(loom.Address), nil, nil, errors.Wrapf(err, "CommitDeployTx")
 	}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/loomnetwork/go-loom/cli"
+	"github.com/loomnetwork/go-loom/client"
+	"github.com/loomnetwork/go-loom/types"
+	"github.com/loomnetwork/go-loom/vm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/loomnetwork/loomchain/auth"
+)
+
+func newSponsorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sponsor <command>",
+		Short: "Sponsor txs sent by other accounts",
+	}
+	cmd.AddCommand(
+		newSponsorSetPolicyCommand(),
+		newSponsorBroadcastCommand(),
+	)
+	return cmd
+}
+
+const sponsorSetPolicyCmdExample = `
+loom sponsor set-policy default:0x9a1aC42a17AAD6Dbc6d21c162989d0f701074044 -k priv_key
+`
+
+func newSponsorSetPolicyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "set-policy [contract]...",
+		Short:   "Restrict sponsored txs to the given contracts, or remove all restrictions if none are given",
+		Example: sponsorSetPolicyCmdExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy := &auth.SponsorPolicy{}
+			for _, arg := range args {
+				addr, err := cli.ParseAddress(arg, cli.TxFlags.ChainID)
+				if err != nil {
+					return errors.Wrapf(err, "invalid contract address %s", arg)
+				}
+				policy.Contracts = append(policy.Contracts, addr.MarshalPB())
+			}
+			if err := commitSponsorPolicyTx(policy); err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				fmt.Println("Sponsor policy removed")
+			} else {
+				fmt.Printf("Sponsored txs restricted to %s\n", strings.Join(args, ", "))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&cli.TxFlags.PrivFile, "key", "k", "", "private key file")
+	setChainFlags(cmd.Flags())
+	return cmd
+}
+
+func commitSponsorPolicyTx(policy *auth.SponsorPolicy) error {
+	callerChainID := cli.TxFlags.CallerChainID
+	if callerChainID == "" {
+		callerChainID = cli.TxFlags.ChainID
+	}
+	clientAddr, signer, err := caller(cli.TxFlags.PrivFile, "", cli.TxFlags.Algo, callerChainID)
+	if err != nil {
+		return errors.Wrapf(err, "initialization failed")
+	}
+	if signer == nil {
+		return fmt.Errorf("invalid private key")
+	}
+
+	policyBytes, err := proto.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	msgTxBytes, err := proto.Marshal(&vm.MessageTx{
+		From: clientAddr.MarshalPB(),
+		To:   clientAddr.MarshalPB(),
+		Data: policyBytes,
+	})
+	if err != nil {
+		return err
+	}
+	rpcclient := client.NewDAppChainRPCClient(cli.TxFlags.ChainID, cli.TxFlags.URI+"/rpc", cli.TxFlags.URI+"/query")
+	_, err = rpcclient.CommitTx(clientAddr, signer, &types.Transaction{
+		Id:   auth.SponsorPolicyTxID,
+		Data: msgTxBytes,
+	})
+	return err
+}
+
+const sponsorBroadcastCmdExample = `
+loom sponsor broadcast user_tx.hex -k priv_key -u http://localhost:46658
+`
+
+func newSponsorBroadcastCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "broadcast <user tx file>",
+		Short:   "Sponsor & broadcast a hex-encoded SignedTx that was signed by another account",
+		Example: sponsorBroadcastCmdExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			userTx, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+			if err != nil {
+				return errors.Wrapf(err, "failed to decode %s", args[0])
+			}
+
+			callerChainID := cli.TxFlags.CallerChainID
+			if callerChainID == "" {
+				callerChainID = cli.TxFlags.ChainID
+			}
+			sponsorAddr, signer, err := caller(cli.TxFlags.PrivFile, "", cli.TxFlags.Algo, callerChainID)
+			if err != nil {
+				return err
+			}
+			if signer == nil {
+				return fmt.Errorf("invalid private key")
+			}
+
+			sponsoredTxBytes, err := proto.Marshal(&auth.SponsoredTx{
+				UserTx:    userTx,
+				Sponsor:   sponsorAddr.MarshalPB(),
+				Signature: signer.Sign(userTx),
+				PublicKey: signer.PublicKey(),
+			})
+			if err != nil {
+				return err
+			}
+			return broadcastTxCommit(sponsoredTxBytes)
+		},
+	}
+	cmd.Flags().StringVarP(&cli.TxFlags.PrivFile, "key", "k", "", "private key file")
+	setChainFlags(cmd.Flags())
+	return cmd
+}
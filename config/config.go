@@ -17,6 +17,7 @@ import (
 	"github.com/loomnetwork/loomchain/events"
 	"github.com/loomnetwork/loomchain/evm"
 	hsmpv "github.com/loomnetwork/loomchain/privval/hsm"
+	remotepv "github.com/loomnetwork/loomchain/privval/remote"
 	receipts "github.com/loomnetwork/loomchain/receipts/handler"
 	registry "github.com/loomnetwork/loomchain/registry/factory"
 	"github.com/loomnetwork/loomchain/rpc/eth"
@@ -114,8 +115,13 @@ type Config struct {
 
 	//Contracts
 	ContractLoaders []string
+	// Interval (in seconds) at which the plugins dir should be checked for new & updated external
+	// plugins, zero disables the check.
+	ExternalPluginsWatchInterval int64
 	//Hsm
 	HsmConfig *hsmpv.HsmConfig
+	// Remote signer, alternative to HSM
+	RemoteSigner *remotepv.RemoteSignerConfig
 
 	// Oracle serializable
 	// todo Cannot be read in from file due to nested pointers to structs.
@@ -450,6 +456,7 @@ func DefaultConfig() *Config {
 	cfg.PlasmaCash = plasmacfg.DefaultConfig()
 	cfg.AppStore = store.DefaultConfig()
 	cfg.HsmConfig = hsmpv.DefaultConfig()
+	cfg.RemoteSigner = remotepv.DefaultConfig()
 	cfg.TxLimiter = throttle.DefaultTxLimiterConfig()
 	cfg.ContractTxLimiter = throttle.DefaultContractTxLimiterConfig()
 	cfg.GoContractDeployerWhitelist = throttle.DefaultGoContractDeployerWhitelistConfig()
@@ -497,6 +504,7 @@ func (c *Config) Clone() *Config {
 	clone.PlasmaCash = c.PlasmaCash.Clone()
 	clone.AppStore = c.AppStore.Clone()
 	clone.HsmConfig = c.HsmConfig.Clone()
+	clone.RemoteSigner = c.RemoteSigner.Clone()
 	clone.TxLimiter = c.TxLimiter.Clone()
 	clone.ContractTxLimiter = c.ContractTxLimiter.Clone()
 	clone.EventStore = c.EventStore.Clone()
@@ -599,6 +607,7 @@ TxLimiter:
   Enabled: {{ .TxLimiter.Enabled }}
   SessionDuration: {{ .TxLimiter.SessionDuration }}
   MaxTxsPerSession: {{ .TxLimiter.MaxTxsPerSession }} 
+  MaxAccounts: {{ .TxLimiter.MaxAccounts }}
 ContractTxLimiter:
   Enabled: {{ .ContractTxLimiter.Enabled }}
   ContractDataRefreshInterval: {{ .ContractTxLimiter.ContractDataRefreshInterval }}
@@ -611,6 +620,7 @@ ContractLoaders:
   {{- range .ContractLoaders}}
   - "{{. -}}" 
   {{- end}}
+ExternalPluginsWatchInterval: {{ .ExternalPluginsWatchInterval }}
 #
 # Logging
 #
@@ -675,7 +685,7 @@ BlockStore:
   CacheSize: {{ .BlockStore.CacheSize }}
 BlockIndexStore:  
   Enabled: {{ .BlockIndexStore.Enabled }}
-  # goleveldb | cleveldb | memdb
+  # goleveldb | cleveldb | badgerdb | memdb
   DBBackend: {{ .BlockIndexStore.DBBackend }}
   DBName: {{ .BlockIndexStore.DBName }}
   CacheSizeMegs: {{ .BlockIndexStore.CacheSizeMegs }}
@@ -732,6 +742,21 @@ HsmConfig:
   # key domain
   HsmSignKeyDomain: {{ .HsmConfig.HsmSignKeyDomain }}
 
+{{if .RemoteSigner -}}
+#
+# Remote signer, holds the validator key in a separate process, can't be enabled with HSM
+#
+RemoteSigner:
+  Enabled: {{ .RemoteSigner.Enabled }}
+  # Unix socket the remote signer is listening on, e.g. unix:///var/run/loom-signer.sock, other
+  # transports aren't supported since the connection to the signer isn't authenticated
+  Addr: "{{ .RemoteSigner.Addr }}"
+  # Max number of seconds to wait for a connection to the remote signer
+  ConnTimeout: {{ .RemoteSigner.ConnTimeout }}
+  # Max number of seconds to wait for the remote signer to respond to a request
+  RequestTimeout: {{ .RemoteSigner.RequestTimeout }}
+{{end}}
+
 #
 # App store
 #
@@ -769,7 +794,7 @@ EvmStore:
   # DBName defines evm database file name
   DBName: {{.EvmStore.DBName}}
   # DBBackend defines backend EVM store type
-  # available backend types are 'goleveldb', or 'cleveldb'
+  # available backend types are 'goleveldb', 'cleveldb', or 'badgerdb'
   DBBackend: {{.EvmStore.DBBackend}}
   # CacheSizeMegs defines cache size (in megabytes) of EVM store
   CacheSizeMegs: {{.EvmStore.CacheSizeMegs}}
@@ -784,6 +809,39 @@ EvmStore:
 Web3:
   # Specifies the maximum number of blocks eth_getLogs will query per request
   GetLogsMaxBlockRange: {{.Web3.GetLogsMaxBlockRange}}
+  # Specifies the maximum number of blocks eth_getLogs will query per request when the blocks are
+  # covered by the bloom-bits index (see loom db index-bloom-bits), only the blocks that aren't
+  # covered by the index count towards GetLogsMaxBlockRange.
+  GetLogsMaxIndexedBlockRange: {{.Web3.GetLogsMaxIndexedBlockRange}}
+  # Specifies the maximum number of requests that can be sent in a single JSON-RPC batch,
+  # zero means there's no limit.
+  MaxBatchRequestSize: {{.Web3.MaxBatchRequestSize}}
+  # Per-IP rate limits for individual methods, e.g.
+  # MethodRateLimits:
+  #   eth_getLogs:
+  #     RequestsPerSecond: 2
+  #     Burst: 10
+  {{- if .Web3.MethodRateLimits}}
+  MethodRateLimits:
+  {{- range $method, $limit := .Web3.MethodRateLimits}}
+    {{$method}}:
+      RequestsPerSecond: {{$limit.RequestsPerSecond}}
+      Burst: {{$limit.Burst}}
+  {{- end}}
+  {{- end}}
+  # IPs (or CIDR ranges) of the reverse proxies in front of the node, the client IP of requests
+  # relayed by these proxies is taken from the X-Forwarded-For or X-Real-IP header, e.g.
+  # TrustedProxies:
+  #   - 10.0.0.0/8
+  {{- if .Web3.TrustedProxies}}
+  TrustedProxies:
+  {{- range .Web3.TrustedProxies}}
+    - "{{.}}"
+  {{- end}}
+  {{- end}}
+  # Enables the debug_traceTransaction, debug_traceCall & debug_traceBlockByNumber methods,
+  # these replay txs & blocks so they shouldn't be enabled on public nodes.
+  DebugRPCEnabled: {{.Web3.DebugRPCEnabled}}
 {{end}}
 
 # 
@@ -0,0 +1,401 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/dgraph-io/badger"
+	"github.com/dgraph-io/badger/options"
+	"github.com/loomnetwork/loomchain/db/metrics"
+	"github.com/loomnetwork/loomchain/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// BadgerDB is a DBWrapper backed by Badger, an LSM store that keeps values in a separate value log,
+// which keeps write amplification & compaction stalls down on fast SSDs.
+type BadgerDB struct {
+	db       *badger.DB
+	counters *metrics.BadgerDBCounters
+}
+
+var _ DBWrapper = &BadgerDB{}
+
+func LoadBadgerDB(name, dir string, cacheSizeMeg int, bufferSizeMeg int, collectMetrics bool) (*BadgerDB, error) {
+	dbPath := filepath.Join(dir, name+".db")
+	opts := badger.DefaultOptions(dbPath)
+	// Tendermint DBs only sync on SetSync & WriteSync, the rest of the writes are buffered.
+	opts.SyncWrites = false
+	// The max size of a batch is derived from MaxTableSize, so it's never lowered below the default.
+	if maxTableSize := int64(bufferSizeMeg) * 1024 * 1024; maxTableSize > opts.MaxTableSize {
+		opts.MaxTableSize = maxTableSize
+	}
+	// Badger doesn't have a block cache, the closest thing is memory mapping the LSM tree, which
+	// only makes sense if the cache is meant to be big.
+	if cacheSizeMeg < 256 {
+		opts.TableLoadingMode = options.FileIO
+	}
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", dbPath)
+	}
+
+	bdb := &BadgerDB{
+		db:       db,
+		counters: &metrics.BadgerDBCounters{},
+	}
+
+	if collectMetrics {
+		err := prometheus.Register(
+			metrics.NewBadgerDBStatsCollector(fmt.Sprintf("badgerdb_%s", name), log.Default, db, bdb.counters),
+		)
+		if err != nil {
+			db.Close()
+			return nil, errors.Wrap(err, "failed to register BadgerDB stats collector")
+		}
+	}
+	return bdb, nil
+}
+
+// LoadReadOnlyBadgerDB opens an existing Badger DB in read-only mode.
+func LoadReadOnlyBadgerDB(name, dir string) (*BadgerDB, error) {
+	dbPath := filepath.Join(dir, name+".db")
+	opts := badger.DefaultOptions(dbPath)
+	opts.ReadOnly = true
+	opts.TableLoadingMode = options.FileIO
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", dbPath)
+	}
+	return &BadgerDB{
+		db:       db,
+		counters: &metrics.BadgerDBCounters{},
+	}, nil
+}
+
+// DB returns the underlying Badger DB.
+func (b *BadgerDB) DB() *badger.DB {
+	return b.db
+}
+
+func (b *BadgerDB) Get(key []byte) []byte {
+	var val []byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		var err error
+		val, err = badgerGet(txn, key)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+	atomic.AddInt64(&b.counters.BytesRead, int64(len(val)))
+	return val
+}
+
+func (b *BadgerDB) Has(key []byte) bool {
+	return b.Get(key) != nil
+}
+
+func (b *BadgerDB) Set(key, value []byte) {
+	if err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(nonNilBytes(key), nonNilBytes(value))
+	}); err != nil {
+		panic(err)
+	}
+	atomic.AddInt64(&b.counters.BytesWritten, int64(len(key)+len(value)))
+}
+
+func (b *BadgerDB) SetSync(key, value []byte) {
+	b.Set(key, value)
+	b.sync()
+}
+
+func (b *BadgerDB) Delete(key []byte) {
+	if err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(nonNilBytes(key))
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func (b *BadgerDB) DeleteSync(key []byte) {
+	b.Delete(key)
+	b.sync()
+}
+
+func (b *BadgerDB) sync() {
+	if err := b.db.Sync(); err != nil {
+		panic(err)
+	}
+}
+
+func (b *BadgerDB) Iterator(start, end []byte) dbm.Iterator {
+	return newBadgerDBIterator(b.db.NewTransaction(false), start, end, false, true, b.counters)
+}
+
+func (b *BadgerDB) ReverseIterator(start, end []byte) dbm.Iterator {
+	return newBadgerDBIterator(b.db.NewTransaction(false), start, end, true, true, b.counters)
+}
+
+func (b *BadgerDB) Close() {
+	if err := b.db.Close(); err != nil {
+		log.Error("Failed to close BadgerDB", "err", err)
+	}
+}
+
+func (b *BadgerDB) NewBatch() dbm.Batch {
+	return &badgerDBBatch{db: b}
+}
+
+func (b *BadgerDB) Print() {
+	it := b.Iterator(nil, nil)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", it.Key(), it.Value())
+	}
+}
+
+func (b *BadgerDB) Stats() map[string]string {
+	lsmSize, vlogSize := b.db.Size()
+	return map[string]string{
+		"badger.lsm_size":        fmt.Sprintf("%d", lsmSize),
+		"badger.vlog_size":       fmt.Sprintf("%d", vlogSize),
+		"badger.alive_snapshots": fmt.Sprintf("%d", atomic.LoadInt64(&b.counters.AliveSnapshots)),
+		"badger.alive_iterators": fmt.Sprintf("%d", atomic.LoadInt64(&b.counters.AliveIterators)),
+	}
+}
+
+// Compact merges all the levels of the LSM tree, and then reclaims space from the value log.
+func (b *BadgerDB) Compact() error {
+	if err := b.db.Flatten(1); err != nil {
+		return err
+	}
+	for {
+		// GC one value log file at a time until there's nothing left to rewrite.
+		if err := b.db.RunValueLogGC(0.5); err != nil {
+			if err == badger.ErrNoRewrite {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (b *BadgerDB) GetSnapshot() Snapshot {
+	atomic.AddInt64(&b.counters.AliveSnapshots, 1)
+	return &BadgerDBSnapshot{
+		txn:      b.db.NewTransaction(false),
+		counters: b.counters,
+	}
+}
+
+// BadgerDBSnapshot is a read-only view of a BadgerDB at the time the snapshot was created.
+type BadgerDBSnapshot struct {
+	txn      *badger.Txn
+	counters *metrics.BadgerDBCounters
+}
+
+var _ Snapshot = &BadgerDBSnapshot{}
+
+func (s *BadgerDBSnapshot) Get(key []byte) []byte {
+	val, err := badgerGet(s.txn, key)
+	if err != nil {
+		panic(err)
+	}
+	atomic.AddInt64(&s.counters.BytesRead, int64(len(val)))
+	return val
+}
+
+func (s *BadgerDBSnapshot) Has(key []byte) bool {
+	return s.Get(key) != nil
+}
+
+func (s *BadgerDBSnapshot) NewIterator(start, end []byte) dbm.Iterator {
+	return newBadgerDBIterator(s.txn, start, end, false, false, s.counters)
+}
+
+func (s *BadgerDBSnapshot) Release() {
+	s.txn.Discard()
+	atomic.AddInt64(&s.counters.AliveSnapshots, -1)
+}
+
+func badgerGet(txn *badger.Txn, key []byte) ([]byte, error) {
+	item, err := txn.Get(nonNilBytes(key))
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+type badgerDBIterator struct {
+	txn        *badger.Txn
+	source     *badger.Iterator
+	start, end []byte
+	reverse    bool
+	ownsTxn    bool // if true the txn will be discarded when the iterator is closed
+	counters   *metrics.BadgerDBCounters
+}
+
+var _ dbm.Iterator = &badgerDBIterator{}
+
+func newBadgerDBIterator(
+	txn *badger.Txn, start, end []byte, reverse bool, ownsTxn bool, counters *metrics.BadgerDBCounters,
+) *badgerDBIterator {
+	opts := badger.DefaultIteratorOptions
+	opts.Reverse = reverse
+	source := txn.NewIterator(opts)
+	if reverse {
+		if end == nil {
+			source.Rewind()
+		} else {
+			// In reverse mode Seek finds the largest key <= end, but end is exclusive.
+			source.Seek(end)
+			if source.Valid() && bytes.Equal(source.Item().Key(), end) {
+				source.Next()
+			}
+		}
+	} else {
+		if start == nil {
+			source.Rewind()
+		} else {
+			source.Seek(start)
+		}
+	}
+	atomic.AddInt64(&counters.AliveIterators, 1)
+	return &badgerDBIterator{
+		txn:      txn,
+		source:   source,
+		start:    start,
+		end:      end,
+		reverse:  reverse,
+		ownsTxn:  ownsTxn,
+		counters: counters,
+	}
+}
+
+func (it *badgerDBIterator) Domain() ([]byte, []byte) {
+	return it.start, it.end
+}
+
+func (it *badgerDBIterator) Valid() bool {
+	if !it.source.Valid() {
+		return false
+	}
+	key := it.source.Item().Key()
+	if it.reverse {
+		if it.start != nil && bytes.Compare(key, it.start) < 0 {
+			return false
+		}
+	} else {
+		if it.end != nil && bytes.Compare(key, it.end) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (it *badgerDBIterator) assertIsValid() {
+	if !it.Valid() {
+		panic("badgerDBIterator is invalid")
+	}
+}
+
+func (it *badgerDBIterator) Next() {
+	it.assertIsValid()
+	it.source.Next()
+}
+
+func (it *badgerDBIterator) Key() []byte {
+	it.assertIsValid()
+	return it.source.Item().KeyCopy(nil)
+}
+
+func (it *badgerDBIterator) Value() []byte {
+	it.assertIsValid()
+	val, err := it.source.Item().ValueCopy(nil)
+	if err != nil {
+		panic(err)
+	}
+	atomic.AddInt64(&it.counters.BytesRead, int64(len(val)))
+	return val
+}
+
+func (it *badgerDBIterator) Close() {
+	it.source.Close()
+	if it.ownsTxn {
+		it.txn.Discard()
+	}
+	atomic.AddInt64(&it.counters.AliveIterators, -1)
+}
+
+type badgerDBOp struct {
+	key, value []byte
+	delete     bool
+}
+
+type badgerDBBatch struct {
+	db  *BadgerDB
+	ops []badgerDBOp
+}
+
+var _ dbm.Batch = &badgerDBBatch{}
+
+func (b *badgerDBBatch) Set(key, value []byte) {
+	b.ops = append(b.ops, badgerDBOp{key: nonNilBytes(key), value: nonNilBytes(value)})
+}
+
+func (b *badgerDBBatch) Delete(key []byte) {
+	b.ops = append(b.ops, badgerDBOp{key: nonNilBytes(key), delete: true})
+}
+
+// Write applies all the batched operations atomically in a single Badger txn. Badger limits the
+// size of a txn (to roughly 15% of MaxTableSize), callers rely on batches being atomic, so if the
+// batch exceeds the limit none of the operations are applied, and Write panics.
+func (b *badgerDBBatch) Write() {
+	txn := b.db.db.NewTransaction(true)
+	defer txn.Discard()
+
+	var bytesWritten int64
+	for _, op := range b.ops {
+		if err := b.apply(txn, op); err != nil {
+			if err == badger.ErrTxnTooBig {
+				panic(errors.Wrapf(err, "BadgerDB batch with %d ops is too big to write atomically", len(b.ops)))
+			}
+			panic(err)
+		}
+		bytesWritten += int64(len(op.key) + len(op.value))
+	}
+	if err := txn.Commit(); err != nil {
+		panic(err)
+	}
+	atomic.AddInt64(&b.db.counters.BytesWritten, bytesWritten)
+	b.ops = nil
+}
+
+func (b *badgerDBBatch) apply(txn *badger.Txn, op badgerDBOp) error {
+	if op.delete {
+		return txn.Delete(op.key)
+	}
+	return txn.Set(op.key, op.value)
+}
+
+func (b *badgerDBBatch) WriteSync() {
+	b.Write()
+	b.db.sync()
+}
+
+// Badger doesn't accept nil keys or values, Tendermint DBs treat nil as empty.
+func nonNilBytes(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
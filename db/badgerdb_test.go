@@ -0,0 +1,167 @@
+package db
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/badger"
+	"github.com/loomnetwork/loomchain/db/metrics"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+func newTestBadgerDB(t *testing.T) (*BadgerDB, func()) {
+	dir, err := ioutil.TempDir("", "badgerdb")
+	require.NoError(t, err)
+	db, err := LoadBadgerDB("test", dir, 0, 0, false)
+	require.NoError(t, err)
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestBadgerDBGetSet(t *testing.T) {
+	db, cleanup := newTestBadgerDB(t)
+	defer cleanup()
+
+	require.Nil(t, db.Get([]byte("a")))
+	require.False(t, db.Has([]byte("a")))
+
+	db.Set([]byte("a"), []byte("1"))
+	db.SetSync([]byte("b"), []byte("2"))
+	require.Equal(t, []byte("1"), db.Get([]byte("a")))
+	require.Equal(t, []byte("2"), db.Get([]byte("b")))
+	require.True(t, db.Has([]byte("a")))
+
+	// nil values are stored as empty values
+	db.Set([]byte("c"), nil)
+	require.Equal(t, []byte{}, db.Get([]byte("c")))
+	require.True(t, db.Has([]byte("c")))
+
+	db.Delete([]byte("a"))
+	db.DeleteSync([]byte("b"))
+	require.Nil(t, db.Get([]byte("a")))
+	require.Nil(t, db.Get([]byte("b")))
+	require.False(t, db.Has([]byte("a")))
+}
+
+func TestBadgerDBIterator(t *testing.T) {
+	db, cleanup := newTestBadgerDB(t)
+	defer cleanup()
+
+	for i := 0; i < 10; i++ {
+		db.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("val%d", i)))
+	}
+
+	collect := func(it dbm.Iterator) []string {
+		defer it.Close()
+		var keys []string
+		for ; it.Valid(); it.Next() {
+			keys = append(keys, string(it.Key()))
+			require.Equal(t, "val"+string(it.Key())[3:], string(it.Value()))
+		}
+		return keys
+	}
+
+	require.Len(t, collect(db.Iterator(nil, nil)), 10)
+	require.Equal(t, []string{"key2", "key3", "key4"}, collect(db.Iterator([]byte("key2"), []byte("key5"))))
+	require.Equal(t, []string{"key8", "key9"}, collect(db.Iterator([]byte("key8"), nil)))
+	require.Equal(t, []string{"key0", "key1"}, collect(db.Iterator(nil, []byte("key2"))))
+
+	require.Equal(t, []string{"key4", "key3", "key2"}, collect(db.ReverseIterator([]byte("key2"), []byte("key5"))))
+	require.Equal(t, []string{"key9", "key8"}, collect(db.ReverseIterator([]byte("key8"), nil)))
+	require.Equal(t, []string{"key1", "key0"}, collect(db.ReverseIterator(nil, []byte("key2"))))
+	require.Equal(t, "key9", collect(db.ReverseIterator(nil, nil))[0])
+
+	require.Empty(t, collect(db.Iterator([]byte("x"), nil)))
+}
+
+func TestBadgerDBBatch(t *testing.T) {
+	db, cleanup := newTestBadgerDB(t)
+	defer cleanup()
+
+	db.Set([]byte("a"), []byte("1"))
+	batch := db.NewBatch()
+	batch.Set([]byte("b"), []byte("2"))
+	batch.Set([]byte("c"), []byte("3"))
+	batch.Delete([]byte("a"))
+	// nothing is written until the batch is
+	require.Equal(t, []byte("1"), db.Get([]byte("a")))
+	require.Nil(t, db.Get([]byte("b")))
+
+	batch.Write()
+	require.Nil(t, db.Get([]byte("a")))
+	require.Equal(t, []byte("2"), db.Get([]byte("b")))
+	require.Equal(t, []byte("3"), db.Get([]byte("c")))
+}
+
+func TestBadgerDBBatchTooBig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "badgerdb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := badger.DefaultOptions(dir)
+	opts.MaxTableSize = 1024 * 1024
+	bdb, err := badger.Open(opts)
+	require.NoError(t, err)
+	db := &BadgerDB{db: bdb, counters: &metrics.BadgerDBCounters{}}
+	defer db.Close()
+
+	batch := db.NewBatch()
+	value := make([]byte, 1024)
+	for i := 0; i < 1024; i++ {
+		batch.Set([]byte(fmt.Sprintf("key%d", i)), value)
+	}
+	require.Panics(t, func() { batch.Write() })
+	// none of the batched ops should've been applied
+	it := db.Iterator(nil, nil)
+	defer it.Close()
+	require.False(t, it.Valid())
+}
+
+func TestBadgerDBSnapshot(t *testing.T) {
+	db, cleanup := newTestBadgerDB(t)
+	defer cleanup()
+
+	db.Set([]byte("a"), []byte("1"))
+	db.Set([]byte("b"), []byte("2"))
+	snap := db.GetSnapshot()
+	defer snap.Release()
+
+	db.Set([]byte("a"), []byte("changed"))
+	db.Delete([]byte("b"))
+	db.Set([]byte("c"), []byte("3"))
+
+	require.Equal(t, []byte("1"), snap.Get([]byte("a")))
+	require.True(t, snap.Has([]byte("b")))
+	require.False(t, snap.Has([]byte("c")))
+
+	it := snap.NewIterator(nil, nil)
+	defer it.Close()
+	var keys []string
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	require.Equal(t, []string{"a", "b"}, keys)
+}
+
+func TestCopyDBToBadgerDB(t *testing.T) {
+	src, _ := LoadMemDB()
+	for i := 0; i < 100; i++ {
+		src.Set([]byte(fmt.Sprintf("key%03d", i)), []byte(fmt.Sprintf("val%d", i)))
+	}
+
+	dest, cleanup := newTestBadgerDB(t)
+	defer cleanup()
+
+	numKeys, err := CopyDB(src, dest, 7)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), numKeys)
+	require.NoError(t, CompareDBs(src, dest))
+
+	dest.Set([]byte("extra"), []byte{})
+	require.Error(t, CompareDBs(src, dest))
+}
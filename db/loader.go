@@ -10,6 +10,7 @@ const (
 	GoLevelDBBackend = "goleveldb"
 	CLevelDBBackend  = "cleveldb"
 	MemDBackend      = "memdb"
+	BadgerDBBackend  = "badgerdb"
 )
 
 type DBWrapper interface {
@@ -35,9 +36,24 @@ func LoadDB(
 		return LoadGoLevelDB(name, directory, cacheSizeMegs, bufferSizeMeg, collectMetrics)
 	case CLevelDBBackend:
 		return LoadCLevelDB(name, directory)
+	case BadgerDBBackend:
+		return LoadBadgerDB(name, directory, cacheSizeMegs, bufferSizeMeg, collectMetrics)
 	case MemDBackend:
 		return LoadMemDB()
 	default:
 		return nil, fmt.Errorf("unknown db backend: %s", dbBackend)
 	}
 }
+
+// LoadReadOnlyDB opens an existing DB in read-only mode, only the goleveldb & badgerdb backends
+// support read-only access.
+func LoadReadOnlyDB(dbBackend, name, directory string) (DBWrapper, error) {
+	switch dbBackend {
+	case GoLevelDBBackend:
+		return LoadReadOnlyGoLevelDB(name, directory)
+	case BadgerDBBackend:
+		return LoadReadOnlyBadgerDB(name, directory)
+	default:
+		return nil, fmt.Errorf("db backend %s doesn't support read-only access", dbBackend)
+	}
+}
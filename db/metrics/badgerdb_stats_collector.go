@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"sync/atomic"
+
+	"github.com/dgraph-io/badger"
+	"github.com/loomnetwork/go-loom"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &BadgerDBStatsCollector{}
+
+// BadgerDBCounters tracks DB activity that Badger doesn't expose itself, all the fields must be
+// updated atomically.
+type BadgerDBCounters struct {
+	AliveSnapshots int64
+	AliveIterators int64
+	BytesRead      int64
+	BytesWritten   int64
+}
+
+// BadgerDBStatsCollector is a prometheus.Collector for BadgerDB database
+type BadgerDBStatsCollector struct {
+	db               *badger.DB
+	counters         *BadgerDBCounters
+	name             string
+	log              *loom.Logger
+	badgerlsmsize    *prometheus.Desc
+	badgervlogsize   *prometheus.Desc
+	badgeralivesnaps *prometheus.Desc
+	badgeraliveiters *prometheus.Desc
+	badgerwriteio    *prometheus.Desc
+	badgerreadio     *prometheus.Desc
+}
+
+// NewBadgerDBStatsCollector creates a new Prometheus collector for BadgerDB stats.
+func NewBadgerDBStatsCollector(
+	name string, logger *loom.Logger, db *badger.DB, counters *BadgerDBCounters,
+) *BadgerDBStatsCollector {
+	const (
+		dbSubsystem = "db"
+		namespace   = "badgerdb"
+	)
+
+	labels := []string{"database"}
+
+	return &BadgerDBStatsCollector{
+		db:       db,
+		counters: counters,
+		name:     name,
+		log:      logger,
+
+		badgerlsmsize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, dbSubsystem, "badgerlsmsize"),
+			"size of the LSM tree on disk",
+			labels,
+			prometheus.Labels{"db": name},
+		),
+
+		badgervlogsize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, dbSubsystem, "badgervlogsize"),
+			"size of the value log on disk",
+			labels,
+			prometheus.Labels{"db": name},
+		),
+
+		badgeralivesnaps: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, dbSubsystem, "badgeralivesnaps"),
+			"number of live snapshots",
+			labels,
+			prometheus.Labels{"db": name},
+		),
+
+		badgeraliveiters: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, dbSubsystem, "badgeraliveiters"),
+			"number of live iterators",
+			labels,
+			prometheus.Labels{"db": name},
+		),
+
+		badgerwriteio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, dbSubsystem, "badgerwriteio"),
+			"number of bytes written",
+			labels,
+			prometheus.Labels{"db": name},
+		),
+
+		badgerreadio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, dbSubsystem, "badgerreadio"),
+			"number of bytes read",
+			labels,
+			prometheus.Labels{"db": name},
+		),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *BadgerDBStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ds := []*prometheus.Desc{
+		c.badgerlsmsize,
+		c.badgervlogsize,
+		c.badgeralivesnaps,
+		c.badgeraliveiters,
+		c.badgerreadio,
+		c.badgerwriteio,
+	}
+
+	for _, d := range ds {
+		ch <- d
+	}
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *BadgerDBStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	lsmSize, vlogSize := c.db.Size()
+	ch <- prometheus.MustNewConstMetric(
+		c.badgerlsmsize,
+		prometheus.GaugeValue,
+		float64(lsmSize),
+		c.name,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.badgervlogsize,
+		prometheus.GaugeValue,
+		float64(vlogSize),
+		c.name,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.badgeralivesnaps,
+		prometheus.GaugeValue,
+		float64(atomic.LoadInt64(&c.counters.AliveSnapshots)),
+		c.name,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.badgeraliveiters,
+		prometheus.GaugeValue,
+		float64(atomic.LoadInt64(&c.counters.AliveIterators)),
+		c.name,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.badgerreadio,
+		prometheus.GaugeValue,
+		float64(atomic.LoadInt64(&c.counters.BytesRead)),
+		c.name,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.badgerwriteio,
+		prometheus.GaugeValue,
+		float64(atomic.LoadInt64(&c.counters.BytesWritten)),
+		c.name,
+	)
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// Max number of bytes written to the destination DB in a single batch by CopyDB, some backends
+// (e.g. BadgerDB) limit the size of a batch.
+const maxCopyBatchBytes = 4 * 1024 * 1024
+
+// CopyDB copies all the keys & values from the source DB to the destination DB, writing them out
+// in batches of (at most) batchSize keys. Returns the number of keys copied.
+func CopyDB(src, dest dbm.DB, batchSize int) (uint64, error) {
+	if batchSize <= 0 {
+		return 0, errors.New("invalid batch size")
+	}
+
+	var numKeys uint64
+	it := src.Iterator(nil, nil)
+	defer it.Close()
+
+	batch := dest.NewBatch()
+	batchLen := 0
+	batchBytes := 0
+	for ; it.Valid(); it.Next() {
+		key, value := it.Key(), it.Value()
+		batch.Set(key, value)
+		batchLen++
+		batchBytes += len(key) + len(value)
+		numKeys++
+		if batchLen == batchSize || batchBytes >= maxCopyBatchBytes {
+			batch.Write()
+			batch = dest.NewBatch()
+			batchLen = 0
+			batchBytes = 0
+		}
+	}
+	if batchLen > 0 {
+		batch.Write()
+	}
+	return numKeys, nil
+}
+
+// CompareDBs checks that both DBs contain exactly the same keys & values.
+func CompareDBs(a, b dbm.DB) error {
+	itA := a.Iterator(nil, nil)
+	defer itA.Close()
+	itB := b.Iterator(nil, nil)
+	defer itB.Close()
+
+	for ; itA.Valid(); itA.Next() {
+		if !itB.Valid() {
+			return errors.Errorf("key %X is missing", itA.Key())
+		}
+		if !bytes.Equal(itA.Key(), itB.Key()) {
+			return errors.Errorf("key mismatch, expected %X, got %X", itA.Key(), itB.Key())
+		}
+		if !bytes.Equal(itA.Value(), itB.Value()) {
+			return errors.Errorf("value mismatch for key %X", itA.Key())
+		}
+		itB.Next()
+	}
+	if itB.Valid() {
+		return errors.Errorf("unexpected key %X", itB.Key())
+	}
+	return nil
+}
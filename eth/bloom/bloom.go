@@ -1,6 +1,8 @@
 package bloom
 
 import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/loomnetwork/go-loom/plugin/types"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/util"
@@ -32,3 +34,65 @@ func GenBloomFilter(msgs []*types.EventData) []byte {
 		return buff.Bytes()
 	}
 }
+
+const (
+	// EthBloomByteLength is the size of an Ethereum-style block bloom.
+	EthBloomByteLength = 256
+	// EthBloomBitLength is the number of bits in an Ethereum-style block bloom.
+	EthBloomBitLength = 8 * EthBloomByteLength
+)
+
+// GenEthBloom generates an Ethereum-style (2048 bit) bloom of the addresses & topics in the given
+// events. Unlike the bloom generated by GenBloomFilter the size of this bloom is fixed, which makes it
+// possible to index the blooms of many blocks at once (see evmaux.EvmAuxStore.IndexBloomBitsSection).
+func GenEthBloom(msgs []*types.EventData) []byte {
+	bloom := make([]byte, EthBloomByteLength)
+	for _, msg := range msgs {
+		if msg.Address != nil {
+			addEthBloomItem(bloom, msg.Address.Local)
+		}
+		for _, topic := range msg.Topics {
+			addEthBloomItem(bloom, TopicBytes(topic))
+		}
+	}
+	return bloom
+}
+
+// EthBloomBits returns the indices of the three bits that are set in an Ethereum-style bloom for the
+// given data.
+func EthBloomBits(data []byte) [3]uint {
+	hash := crypto.Keccak256(data)
+	var bits [3]uint
+	for i := 0; i < len(bits); i++ {
+		bits[i] = (uint(hash[2*i])<<8 | uint(hash[2*i+1])) & (EthBloomBitLength - 1)
+	}
+	return bits
+}
+
+// EthBloomContains checks if the given Ethereum-style bloom may contain the given data.
+func EthBloomContains(bloom []byte, data []byte) bool {
+	if len(bloom) != EthBloomByteLength {
+		return false
+	}
+	for _, bit := range EthBloomBits(data) {
+		if bloom[EthBloomByteLength-1-bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TopicBytes converts a hex-encoded event topic to the bytes that are added to an Ethereum-style bloom,
+// topics that aren't hex-encoded are added as is.
+func TopicBytes(topic string) []byte {
+	if b, err := hexutil.Decode(topic); err == nil {
+		return b
+	}
+	return []byte(topic)
+}
+
+func addEthBloomItem(bloom []byte, data []byte) {
+	for _, bit := range EthBloomBits(data) {
+		bloom[EthBloomByteLength-1-bit/8] |= 1 << (bit % 8)
+	}
+}
@@ -9,11 +9,12 @@ import (
 	etypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gogo/protobuf/proto"
-	"github.com/loomnetwork/go-loom/auth"
 	"github.com/loomnetwork/go-loom/plugin/types"
 	ltypes "github.com/loomnetwork/go-loom/types"
 	"github.com/loomnetwork/go-loom/vm"
 	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	"github.com/loomnetwork/loomchain/registry"
 	"github.com/loomnetwork/loomchain/rpc/eth"
 	"github.com/loomnetwork/loomchain/store"
 	evmaux "github.com/loomnetwork/loomchain/store/evm_aux"
@@ -73,8 +74,9 @@ func GetBlockByNumber(
 	// These three fields are null for pending blocks.
 	blockInfo.Hash = eth.EncBytes(blockResult.BlockMeta.BlockID.Hash)
 	blockInfo.Number = eth.EncInt(height)
-	bloomFilter := evmAuxStore.GetBloomFilter(uint64(height))
-	blockInfo.LogsBloom = eth.EncBytes(bloomFilter)
+	if err := setBlockRoots(&blockInfo, uint64(height), evmAuxStore); err != nil {
+		return resp, err
+	}
 	// We ignore the error here because if the block results can't be loaded for any reason
 	// we'll try to load the data we need from tx_index.db instead.
 	// TODO: Log the error returned by GetBlockResults.
@@ -118,6 +120,38 @@ func GetBlockByNumber(
 	return blockInfo, nil
 }
 
+// setBlockRoots sets the logs bloom, receipts root, and state root of the given block from the values
+// stored when the block was committed, the roots are zeroed if they weren't stored.
+func setBlockRoots(blockInfo *eth.JsonBlockObject, height uint64, evmAuxStore *evmaux.EvmAuxStore) error {
+	logsBloom, err := evmAuxStore.GetBlockBloom(height)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load logs bloom of block %d", height)
+	}
+	if len(logsBloom) > 0 {
+		blockInfo.LogsBloom = eth.EncBytes(logsBloom)
+	} else {
+		// blocks without any EVM txs don't have a stored bloom
+		blockInfo.LogsBloom = eth.ZeroedData256Bytes
+	}
+
+	receiptsRoot, err := evmAuxStore.GetReceiptsRoot(height)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load receipts root of block %d", height)
+	}
+	if len(receiptsRoot) > 0 {
+		blockInfo.ReceiptsRoot = eth.EncBytes(receiptsRoot)
+	}
+
+	stateRoot, err := evmAuxStore.GetStateRoot(height)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load state root of block %d", height)
+	}
+	if len(stateRoot) > 0 {
+		blockInfo.StateRoot = eth.EncBytes(stateRoot)
+	}
+	return nil
+}
+
 func GetTxObjectFromBlockResult(
 	blockResult *ctypes.ResultBlock, txResultData []byte, txIndex int64, evmAuxStore *evmaux.EvmAuxStore,
 ) (eth.JsonTxObject, *eth.Data, error) {
@@ -134,7 +168,7 @@ func GetTxObjectFromBlockResult(
 	}
 
 	var signedTx auth.SignedTx
-	if err := proto.Unmarshal([]byte(tx), &signedTx); err != nil {
+	if err := proto.Unmarshal(auth.UnwrapSponsoredTx(tx), &signedTx); err != nil {
 		return eth.GetEmptyTxObject(), nil, err
 	}
 
@@ -206,7 +240,7 @@ func GetTxObjectFromBlockResult(
 			txObj.Value = eth.EncBigInt(*callTx.Value.Value.Int)
 		}
 
-	case ltypes.TxID_MIGRATION:
+	case ltypes.TxID_MIGRATION, ltypes.TxID(registry.RegistryTxID), ltypes.TxID(auth.SponsorPolicyTxID):
 		to := eth.EncAddress(msg.To)
 		txObj.To = &to
 		input = msg.Data
@@ -0,0 +1,119 @@
+// +build evm
+
+package query
+
+import (
+	"github.com/loomnetwork/loomchain/eth/bloom"
+	"github.com/loomnetwork/loomchain/rpc/eth"
+	evmaux "github.com/loomnetwork/loomchain/store/evm_aux"
+	"github.com/pkg/errors"
+)
+
+// bloomBitsMatcher finds the blocks that may contain logs matching a filter using the bloom-bits index.
+type bloomBitsMatcher struct {
+	evmAuxStore *evmaux.EvmAuxStore
+	// Each clause is a list of items (addresses or topics), a block matches a clause if its bloom
+	// contains at least one of the items, and it matches the filter if it matches all the clauses.
+	clauses [][][3]uint
+}
+
+func newBloomBitsMatcher(evmAuxStore *evmaux.EvmAuxStore, filter eth.EthBlockFilter) *bloomBitsMatcher {
+	m := &bloomBitsMatcher{evmAuxStore: evmAuxStore}
+	if len(filter.Addresses) > 0 {
+		clause := make([][3]uint, 0, len(filter.Addresses))
+		for _, addr := range filter.Addresses {
+			clause = append(clause, bloom.EthBloomBits(addr))
+		}
+		m.clauses = append(m.clauses, clause)
+	}
+	for _, topics := range filter.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		clause := make([][3]uint, 0, len(topics))
+		for _, topic := range topics {
+			clause = append(clause, bloom.EthBloomBits(bloom.TopicBytes(topic)))
+		}
+		m.clauses = append(m.clauses, clause)
+	}
+	return m
+}
+
+// matchSection returns the heights of the blocks in the given (indexed) section that may contain
+// logs matching the filter, in ascending order.
+func (m *bloomBitsMatcher) matchSection(section uint64) ([]uint64, error) {
+	vectorLen := int(evmaux.BloomBitsSectionSize / 8)
+	bitVectors := map[uint][]byte{}
+	loadBits := func(bit uint) ([]byte, error) {
+		if bits, ok := bitVectors[bit]; ok {
+			return bits, nil
+		}
+		bits, err := m.evmAuxStore.GetBloomBits(bit, section)
+		if err != nil {
+			return nil, err
+		}
+		bitVectors[bit] = bits
+		return bits, nil
+	}
+
+	matches := make([]byte, vectorLen)
+	for i := range matches {
+		matches[i] = 0xff
+	}
+	for _, clause := range m.clauses {
+		clauseMatches := make([]byte, vectorLen)
+		for _, itemBits := range clause {
+			itemMatches := make([]byte, vectorLen)
+			copy(itemMatches, matches)
+			for _, bit := range itemBits {
+				bits, err := loadBits(bit)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to load bloom bits")
+				}
+				for i := range itemMatches {
+					itemMatches[i] &= bits[i]
+				}
+			}
+			for i := range clauseMatches {
+				clauseMatches[i] |= itemMatches[i]
+			}
+		}
+		matches = clauseMatches
+	}
+
+	var heights []uint64
+	first := section * evmaux.BloomBitsSectionSize
+	for i, b := range matches {
+		if b == 0 {
+			continue
+		}
+		for j := uint(0); j < 8; j++ {
+			if b&(0x80>>j) != 0 {
+				heights = append(heights, first+uint64(i)*8+uint64(j))
+			}
+		}
+	}
+	return heights, nil
+}
+
+// countUnindexedBlocks returns the number of blocks in the given height range that aren't covered by
+// the bloom-bits index.
+func countUnindexedBlocks(evmAuxStore *evmaux.EvmAuxStore, from, to uint64) (uint64, error) {
+	count := uint64(0)
+	for height := from; height <= to; {
+		section := height / evmaux.BloomBitsSectionSize
+		last := evmaux.LastBlockInSection(section)
+		if last > to {
+			last = to
+		}
+		indexed, err := evmAuxStore.IsBloomBitsSectionIndexed(section)
+		if err != nil {
+			return 0, err
+		}
+		if !indexed {
+			count += last - height + 1
+		}
+		height = last + 1
+	}
+	return count, nil
+}
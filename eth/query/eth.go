@@ -19,27 +19,108 @@ import (
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 )
 
+// QueryChain returns the logs matching the given filter. The number of blocks that aren't covered by
+// the bloom-bits index must not exceed maxBlockRange, and the total number of blocks in the filter
+// range must not exceed maxIndexedBlockRange.
 func QueryChain(
 	blockStore store.BlockStore, state loomchain.ReadOnlyState, ethFilter eth.EthFilter,
-	readReceipts loomchain.ReadReceiptHandler, evmAuxStore *evmaux.EvmAuxStore, maxBlockRange uint64,
+	readReceipts loomchain.ReadReceiptHandler, evmAuxStore *evmaux.EvmAuxStore,
+	maxBlockRange, maxIndexedBlockRange uint64,
 ) ([]*ptypes.EthFilterLog, error) {
-	start, err := eth.DecBlockHeight(state.Block().Height, ethFilter.FromBlock)
+	start, end, err := getLogRange(state, ethFilter, evmAuxStore, maxBlockRange, maxIndexedBlockRange)
 	if err != nil {
 		return nil, err
 	}
+	return GetBlockLogRange(blockStore, state, start, end, ethFilter.EthBlockFilter, readReceipts, evmAuxStore)
+}
+
+// QueryChainPaged returns up to limit logs matching the given filter, starting with the first matching
+// log at or after the given position. The position from which the next page of logs should be read is
+// also returned, or nil if there are no more logs in the filter range.
+func QueryChainPaged(
+	blockStore store.BlockStore, state loomchain.ReadOnlyState, ethFilter eth.EthFilter,
+	readReceipts loomchain.ReadReceiptHandler, evmAuxStore *evmaux.EvmAuxStore,
+	maxBlockRange, maxIndexedBlockRange uint64, from eth.LogCursor, limit int,
+) ([]*ptypes.EthFilterLog, *eth.LogCursor, error) {
+	start, end, err := getLogRange(state, ethFilter, evmAuxStore, maxBlockRange, maxIndexedBlockRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from.Height > start {
+		start = from.Height
+	}
+	eventLogs := []*ptypes.EthFilterLog{}
+	if start > end {
+		return eventLogs, nil, nil
+	}
+
+	var next *eth.LogCursor
+	err = forEachBlockLogs(
+		blockStore, state, start, end, ethFilter.EthBlockFilter, readReceipts, evmAuxStore,
+		func(height uint64, blockLogs []*ptypes.EthFilterLog) bool {
+			for _, log := range blockLogs {
+				pos := eth.LogCursor{
+					Height:   uint64(log.BlockNumber),
+					TxIndex:  uint32(log.TransactionIndex),
+					LogIndex: uint32(log.LogIndex),
+				}
+				if pos.Less(from) {
+					continue
+				}
+				if len(eventLogs) == limit {
+					next = &pos
+					return false
+				}
+				eventLogs = append(eventLogs, log)
+			}
+			if len(eventLogs) == limit {
+				// don't bother looking for the next matching log, the next page will start at the next block
+				if height < end {
+					next = &eth.LogCursor{Height: height + 1}
+				}
+				return false
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return eventLogs, next, nil
+}
+
+// getLogRange returns the block range specified in the given filter, if the range exceeds the limits
+// configured on the node an error is returned.
+func getLogRange(
+	state loomchain.ReadOnlyState, ethFilter eth.EthFilter, evmAuxStore *evmaux.EvmAuxStore,
+	maxBlockRange, maxIndexedBlockRange uint64,
+) (uint64, uint64, error) {
+	start, err := eth.DecBlockHeight(state.Block().Height, ethFilter.FromBlock)
+	if err != nil {
+		return 0, 0, err
+	}
 	end, err := eth.DecBlockHeight(state.Block().Height, ethFilter.ToBlock)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 	if end < start {
-		return nil, errors.New("invalid block range")
+		return 0, 0, errors.New("invalid block range")
 	}
 
 	if end-start > maxBlockRange {
-		return nil, fmt.Errorf("max allowed block range (%d) exceeded", maxBlockRange)
+		if end-start > maxIndexedBlockRange {
+			return 0, 0, fmt.Errorf("max allowed block range (%d) exceeded", maxIndexedBlockRange)
+		}
+		// Wide ranges are only allowed if most of the blocks are covered by the bloom-bits index
+		numUnindexed, err := countUnindexedBlocks(evmAuxStore, start, end)
+		if err != nil {
+			return 0, 0, err
+		}
+		if numUnindexed > maxBlockRange {
+			return 0, 0, fmt.Errorf("max allowed block range (%d) exceeded", maxBlockRange)
+		}
 	}
-
-	return GetBlockLogRange(blockStore, state, start, end, ethFilter.EthBlockFilter, readReceipts, evmAuxStore)
+	return start, end, nil
 }
 
 func DeprecatedQueryChain(
@@ -84,19 +165,78 @@ func GetBlockLogRange(
 	readReceipts loomchain.ReadReceiptHandler,
 	evmAuxStore *evmaux.EvmAuxStore,
 ) ([]*ptypes.EthFilterLog, error) {
+	eventLogs := []*ptypes.EthFilterLog{}
+	err := forEachBlockLogs(
+		blockStore, state, from, to, ethFilter, readReceipts, evmAuxStore,
+		func(_ uint64, blockLogs []*ptypes.EthFilterLog) bool {
+			eventLogs = append(eventLogs, blockLogs...)
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return eventLogs, nil
+}
+
+// forEachBlockLogs calls fn with the logs matching the filter in each block in the given range that
+// contains any matching logs, in ascending block order, until fn returns false.
+func forEachBlockLogs(
+	blockStore store.BlockStore,
+	state loomchain.ReadOnlyState,
+	from, to uint64,
+	ethFilter eth.EthBlockFilter,
+	readReceipts loomchain.ReadReceiptHandler,
+	evmAuxStore *evmaux.EvmAuxStore,
+	fn func(height uint64, blockLogs []*ptypes.EthFilterLog) bool,
+) error {
 	if from > to {
-		return nil, fmt.Errorf("from block (%v) greater than to block (%v)", from, to)
+		return fmt.Errorf("from block (%v) greater than to block (%v)", from, to)
 	}
-	eventLogs := []*ptypes.EthFilterLog{}
 
-	for height := from; height <= to; height++ {
-		blockLogs, err := getBlockLogs(blockStore, state, ethFilter, height, readReceipts, evmAuxStore)
+	// The range is processed one section of the bloom-bits index at a time, in indexed sections only
+	// the blocks matched by the index have to be checked, otherwise every block has to be checked.
+	matcher := newBloomBitsMatcher(evmAuxStore, ethFilter)
+	for height := from; height <= to; {
+		section := height / evmaux.BloomBitsSectionSize
+		last := evmaux.LastBlockInSection(section)
+		if last > to {
+			last = to
+		}
+
+		indexed, err := evmAuxStore.IsBloomBitsSectionIndexed(section)
 		if err != nil {
-			return nil, err
+			return errors.Wrap(err, "failed to check bloom-bits index")
+		}
+		var heights []uint64
+		if indexed {
+			matches, err := matcher.matchSection(section)
+			if err != nil {
+				return err
+			}
+			for _, h := range matches {
+				if h >= height && h <= last {
+					heights = append(heights, h)
+				}
+			}
+		} else {
+			for h := height; h <= last; h++ {
+				heights = append(heights, h)
+			}
+		}
+
+		for _, h := range heights {
+			blockLogs, err := getBlockLogs(blockStore, state, ethFilter, h, readReceipts, evmAuxStore)
+			if err != nil {
+				return err
+			}
+			if len(blockLogs) > 0 && !fn(h, blockLogs) {
+				return nil
+			}
 		}
-		eventLogs = append(eventLogs, blockLogs...)
+		height = last + 1
 	}
-	return eventLogs, nil
+	return nil
 }
 
 func getBlockLogs(
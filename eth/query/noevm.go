@@ -68,11 +68,18 @@ func GetTxByBlockAndIndex(_ store.BlockStore, _, _ uint64, _ *evmaux.EvmAuxStore
 
 func QueryChain(
 	_ store.BlockStore, _ loomchain.ReadOnlyState, _ eth.EthFilter,
-	_ loomchain.ReadReceiptHandler, _ *evmaux.EvmAuxStore, _ uint64,
+	_ loomchain.ReadReceiptHandler, _ *evmaux.EvmAuxStore, _, _ uint64,
 ) ([]*types.EthFilterLog, error) {
 	return nil, nil
 }
 
+func QueryChainPaged(
+	_ store.BlockStore, _ loomchain.ReadOnlyState, _ eth.EthFilter,
+	_ loomchain.ReadReceiptHandler, _ *evmaux.EvmAuxStore, _, _ uint64, _ eth.LogCursor, _ int,
+) ([]*types.EthFilterLog, *eth.LogCursor, error) {
+	return nil, nil, nil
+}
+
 func GetNumTxBlock(_ store.BlockStore, _ loomchain.ReadOnlyState, _ int64) (uint64, error) {
 	return 0, nil
 }
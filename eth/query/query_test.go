@@ -22,6 +22,7 @@ import (
 	"github.com/loomnetwork/loomchain/receipts/handler"
 	"github.com/loomnetwork/loomchain/rpc/eth"
 	"github.com/loomnetwork/loomchain/store"
+	evmaux "github.com/loomnetwork/loomchain/store/evm_aux"
 	"github.com/stretchr/testify/require"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	ttypes "github.com/tendermint/tendermint/types"
@@ -100,9 +101,9 @@ func TestQueryChain(t *testing.T) {
 	require.NoError(t, err)
 	ethFilter2, err := utils.UnmarshalEthFilter([]byte(getFilter("1", "10")))
 	require.NoError(t, err)
-	filterLogs1, err := QueryChain(blockStore, state30, ethFilter1, receiptHandler, evmAuxStore, 100000)
+	filterLogs1, err := QueryChain(blockStore, state30, ethFilter1, receiptHandler, evmAuxStore, 100000, 100000)
 	require.NoError(t, err, "error query chain, filter is %s", ethFilter1)
-	filterLogs2, err := QueryChain(blockStore, state30, ethFilter2, receiptHandler, evmAuxStore, 100000)
+	filterLogs2, err := QueryChain(blockStore, state30, ethFilter2, receiptHandler, evmAuxStore, 100000, 100000)
 	require.NoError(t, err, "error query chain, filter is %s", ethFilter2)
 	require.Equal(t, 2, len(filterLogs1)+len(filterLogs2), "wrong number of logs returned")
 
@@ -385,3 +386,45 @@ func getRandomTxHash() []byte {
 	h.Write(token)
 	return h.Sum(nil)
 }
+
+func TestBloomBitsMatcher(t *testing.T) {
+	evmAuxStore, err := common.NewMockEvmAuxStore()
+	require.NoError(t, err)
+	defer evmAuxStore.ClearData()
+
+	blockEvents := map[uint64][]*types.EventData{
+		3:  {{Topics: []string{"topic1"}, Address: addr1.MarshalPB()}},
+		7:  {{Topics: []string{"topic2"}, Address: addr2.MarshalPB()}},
+		11: {{Topics: []string{"topic1", "topic2"}, Address: addr2.MarshalPB()}},
+	}
+	tran, err := evmAuxStore.DB().OpenTransaction()
+	require.NoError(t, err)
+	for height, events := range blockEvents {
+		require.NoError(t, evmAuxStore.SetBlockBloom(tran, bloom.GenEthBloom(events), height))
+	}
+	require.NoError(t, tran.Commit())
+	require.NoError(t, evmAuxStore.SetBlockBloomsFrom(1))
+	require.NoError(t, evmAuxStore.IndexBloomBits(evmaux.BloomBitsSectionSize-1))
+	indexed, err := evmAuxStore.IsBloomBitsSectionIndexed(0)
+	require.NoError(t, err)
+	require.True(t, indexed)
+
+	match := func(filter eth.EthBlockFilter) []uint64 {
+		heights, err := newBloomBitsMatcher(evmAuxStore, filter).matchSection(0)
+		require.NoError(t, err)
+		return heights
+	}
+	require.Equal(t, []uint64{3}, match(eth.EthBlockFilter{Addresses: []loom.LocalAddress{addr1.Local}}))
+	require.Equal(t, []uint64{7, 11}, match(eth.EthBlockFilter{Addresses: []loom.LocalAddress{addr2.Local}}))
+	require.Equal(t, []uint64{3, 11}, match(eth.EthBlockFilter{Topics: [][]string{{"topic1"}}}))
+	require.Equal(t, []uint64{11}, match(eth.EthBlockFilter{
+		Addresses: []loom.LocalAddress{addr2.Local},
+		Topics:    [][]string{{"topic1"}},
+	}))
+	require.Equal(t, []uint64{3, 7, 11}, match(eth.EthBlockFilter{Topics: [][]string{{"topic1", "topic2"}}}))
+	require.Empty(t, match(eth.EthBlockFilter{Topics: [][]string{{"topic3"}}}))
+
+	unindexed, err := countUnindexedBlocks(evmAuxStore, 100, evmaux.BloomBitsSectionSize+99)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), unindexed)
+}
@@ -10,7 +10,7 @@ type EventStoreConfig struct {
 	// DBName defines database file name
 	DBName string
 	// DBBackend defines backend event store type
-	// available backend types are 'goleveldb', or 'cleveldb'
+	// available backend types are 'goleveldb', 'cleveldb', or 'badgerdb'
 	DBBackend string
 }
 
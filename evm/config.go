@@ -4,7 +4,7 @@ type EvmStoreConfig struct {
 	// DBName defines database file name
 	DBName string
 	// DBBackend defines backend EVM store type
-	// available backend types are 'goleveldb', or 'cleveldb'
+	// available backend types are 'goleveldb', 'cleveldb', or 'badgerdb'
 	DBBackend string
 	// CacheSizeMegs defines cache size (in megabytes) of EVM store
 	CacheSizeMegs int
@@ -22,6 +22,7 @@ import (
 	"github.com/loomnetwork/loomchain"
 	"github.com/loomnetwork/loomchain/features"
 	"github.com/loomnetwork/loomchain/log"
+	lvm "github.com/loomnetwork/loomchain/vm"
 )
 
 // EVMEnabled indicates whether or not Loom EVM integration is available
@@ -145,6 +146,10 @@ type Evm struct {
 	vmConfig        vm.Config
 	validateTxValue bool
 	gasLimit        uint64
+	// Only set if the Go contract bridge has been registered.
+	bridge *bridgeSession
+	// Gas meter of the contract calling the EVM, only set if the caller is metered.
+	gasMeter *lvm.GasMeter
 }
 
 func NewEvm(sdb vm.StateDB, lstate loomchain.State, abm *evmAccountBalanceManager, debug bool) *Evm {
@@ -154,10 +159,30 @@ func NewEvm(sdb vm.StateDB, lstate loomchain.State, abm *evmAccountBalanceManage
 	if p.gasLimit == 0 {
 		p.gasLimit = defaultGasLimit
 	}
+	// When a metered contract calls into the EVM the gas used by the EVM is charged to the caller,
+	// so the EVM can't use more gas than the caller has left.
+	if p.gasMeter = lvm.GasMeterFromContext(lstate.Context()); p.gasMeter != nil {
+		if remaining := p.gasMeter.GasLimit() - p.gasMeter.GasUsed(); remaining < p.gasLimit {
+			p.gasLimit = remaining
+		}
+	}
 
-	p.chainConfig = defaultChainConfig(lstate.FeatureEnabled(features.EvmConstantinopleFeature, false))
+	p.chainConfig = defaultChainConfig(lstate.FeatureEnabled)
 
 	p.vmConfig = defaultVmConfig(debug)
+	if tracer, ok := tracerFromContext(lstate.Context()).(vm.Tracer); ok {
+		p.vmConfig.Debug = true
+		p.vmConfig.Tracer = tracer
+	}
+	if goContractCallerFactory != nil {
+		p.bridge = newBridgeSession(lstate)
+		if lstate.FeatureEnabled(features.EvmGoContractBridgeFeature, false) {
+			p.sdb = newBridgeStateDB(sdb, p.bridge)
+			p.vmConfig.Precompiles = map[common.Address]vm.PrecompiledContract{
+				GoContractBridgeAddress: &goContractBridge{session: p.bridge},
+			}
+		}
+	}
 	p.validateTxValue = lstate.FeatureEnabled(features.CheckTxValueFeature, false)
 	p.context = vm.Context{
 		CanTransfer: core.CanTransfer,
@@ -206,8 +231,15 @@ func (e Evm) Create(caller loom.Address, code []byte, value *loom.BigUInt) ([]by
 		}
 	}
 
+	if e.bridge != nil {
+		if err = e.bridge.checkCaller(); err != nil {
+			return nil, loom.Address{}, err
+		}
+	}
+
 	runCode, address, leftOverGas, err := vmenv.Create(vm.AccountRef(origin), code, e.gasLimit, val)
 	usedGas = e.gasLimit - leftOverGas
+	e.chargeGasMeter(usedGas)
 	loomAddress := loom.Address{
 		ChainID: caller.ChainID,
 		Local:   address.Bytes(),
@@ -242,8 +274,15 @@ func (e Evm) Call(caller, addr loom.Address, input []byte, value *loom.BigUInt)
 			return nil, errors.Errorf("value %v must be non negative", value)
 		}
 	}
+
+	if e.bridge != nil {
+		if err = e.bridge.checkCaller(); err != nil {
+			return nil, err
+		}
+	}
 	ret, leftOverGas, err := vmenv.Call(vm.AccountRef(origin), contract, input, e.gasLimit, val)
 	usedGas = e.gasLimit - leftOverGas
+	e.chargeGasMeter(usedGas)
 	return ret, err
 }
 
@@ -251,10 +290,24 @@ func (e Evm) StaticCall(caller, addr loom.Address, input []byte) ([]byte, error)
 	origin := common.BytesToAddress(caller.Local)
 	contract := common.BytesToAddress(addr.Local)
 	vmenv := e.NewEnv(origin)
-	ret, _, err := vmenv.StaticCall(vm.AccountRef(origin), contract, input, e.gasLimit)
+	if e.bridge != nil {
+		if err := e.bridge.checkCaller(); err != nil {
+			return nil, err
+		}
+	}
+	ret, leftOverGas, err := vmenv.StaticCall(vm.AccountRef(origin), contract, input, e.gasLimit)
+	e.chargeGasMeter(e.gasLimit - leftOverGas)
 	return ret, err
 }
 
+// chargeGasMeter charges the gas used by the EVM to the gas meter of the calling contract (if any).
+func (e Evm) chargeGasMeter(usedGas uint64) {
+	if e.gasMeter != nil {
+		// The EVM gas limit is capped to the gas left in the meter, so this can't exceed the limit.
+		e.gasMeter.ConsumeGas(usedGas)
+	}
+}
+
 func (e Evm) GetCode(addr loom.Address) []byte {
 	return e.sdb.GetCode(common.BytesToAddress(addr.Local))
 }
@@ -289,6 +342,11 @@ func (e Evm) EstimateGas(caller, addr loom.Address, input []byte, value *loom.Bi
 
 	var leftOverGas uint64
 	var err error
+	if e.bridge != nil {
+		if err = e.bridge.checkCaller(); err != nil {
+			return 0, err
+		}
+	}
 	// Assume that trasaction with empty To field is contract deploy transaction.
 	if addr.Compare(loom.RootAddress(addr.ChainID)) == 0 {
 		_, _, leftOverGas, err = vmenv.Create(vm.AccountRef(origin), input, gasLimit, val)
@@ -309,16 +367,29 @@ func (e Evm) NewEnv(origin common.Address) *vm.EVM {
 	return vm.NewEVM(e.context, e.sdb, &e.chainConfig, e.vmConfig)
 }
 
-func defaultChainConfig(enableConstantinople bool) params.ChainConfig {
+// defaultChainConfig returns the chain config the EVM should use, the hard forks enabled in the config
+// are determined by the EVM feature flags.
+func defaultChainConfig(featureEnabled func(name string, defaultVal bool) bool) params.ChainConfig {
 	cliqueCfg := params.CliqueConfig{
 		Period: 10,   // Number of seconds between blocks to enforce
 		Epoch:  1000, // Epoch length to reset votes and checkpoint
 	}
 
-	var constantinopleBlock *big.Int
+	// Each hard fork builds on the previous one, so enabling a fork also enables all the ones before it.
+	enableIstanbul := featureEnabled(features.EvmIstanbulFeature, false)
+	enablePetersburg := enableIstanbul || featureEnabled(features.EvmPetersburgFeature, false)
+	enableConstantinople := enablePetersburg || featureEnabled(features.EvmConstantinopleFeature, false)
+
+	var constantinopleBlock, petersburgBlock, istanbulBlock *big.Int
 	if enableConstantinople {
 		constantinopleBlock = big.NewInt(0)
 	}
+	if enablePetersburg {
+		petersburgBlock = big.NewInt(0)
+	}
+	if enableIstanbul {
+		istanbulBlock = big.NewInt(0)
+	}
 
 	return params.ChainConfig{
 		ChainID:        big.NewInt(0), // Chain id identifies the current chain and is used for replay protection
@@ -332,6 +403,8 @@ func defaultChainConfig(enableConstantinople bool) params.ChainConfig {
 		EIP158Block:         big.NewInt(0),                        // EIP158 HF block
 		ByzantiumBlock:      big.NewInt(0),                        // Byzantium switch block (nil = no fork, 0 = already on byzantium)
 		ConstantinopleBlock: constantinopleBlock,                  // Constantinople switch block (nil = no fork, 0 = already activated)
+		PetersburgBlock:     petersburgBlock,                      // Petersburg switch block (nil = no fork, 0 = already activated)
+		IstanbulBlock:       istanbulBlock,                        // Istanbul switch block (nil = no fork, 0 = already on istanbul)
 		// Various consensus engines
 		Ethash: new(params.EthashConfig),
 		Clique: &cliqueCfg,
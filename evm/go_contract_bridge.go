@@ -0,0 +1,296 @@
+// +build evm
+
+package evm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/loomnetwork/go-loom"
+	ptypes "github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/store"
+	lvm "github.com/loomnetwork/loomchain/vm"
+	"github.com/pkg/errors"
+)
+
+// The Go contract bridge is a precompile that allows EVM contracts to call Go contracts, from Solidity
+// it can be called via the IGoContractBridge interface:
+//
+//   interface IGoContractBridge {
+//       function callGoContract(string contractName, string method, bytes args) external returns (bytes);
+//       function staticCallGoContract(string contractName, string method, bytes args)
+//           external view returns (bytes);
+//   }
+//
+// The Go contract is resolved by name via the contract registry, args must be the protobuf-encoded
+// method args, and the protobuf-encoded method result is returned. The EVM contract that calls the
+// precompile will be the caller (message sender) seen by the Go contract. Go contracts called via
+// STATICCALL (or from within a static call) can't modify the app state, otherwise any events emitted
+// by the Go contract are added to the logs of the current EVM tx.
+//
+// The bridge is a stateful precompile, the patched go-ethereum (see patches/go-ethereum) passes the
+// EVM executing the call, the calling contract, the gas supplied to the call, and whether or not the
+// call is read-only to precompiles that implement vm.StatefulPrecompiledContract. The bridge isn't
+// added to the global precompile sets, it's only added to the precompiles of EVM instances created
+// after the EvmGoContractBridgeFeature is enabled, until then the bridge address is an empty account.
+//
+// Go contract state changes are buffered in memory, and are only written to the app state when the
+// EVM state is committed. If the EVM call frame that called the bridge (or any of the frames above
+// it) is reverted the Go contract state changes made within that frame are reverted too. The Go
+// contract execution is metered using the same gas meter as Go contract txs, and the gas used is
+// deducted from the gas supplied to the precompile. Go contracts called via the bridge can't call
+// back into the EVM.
+
+// GoContractBridgeAddress is the address of the Go contract bridge precompile.
+var GoContractBridgeAddress = common.HexToAddress("0x0000000000000000000000000000000000000100")
+
+const (
+	// The base cost matches the cost of a Go contract calling another Go contract, everything the
+	// called contract does is metered separately.
+	goContractBridgeBaseGas    = uint64(700)
+	goContractBridgeGasPerByte = uint64(16)
+
+	goContractBridgeABI = `[
+		{
+			"type": "function", "name": "callGoContract", "constant": false,
+			"inputs": [
+				{"name": "contractName", "type": "string"},
+				{"name": "method", "type": "string"},
+				{"name": "args", "type": "bytes"}
+			],
+			"outputs": [{"name": "", "type": "bytes"}]
+		},
+		{
+			"type": "function", "name": "staticCallGoContract", "constant": true,
+			"inputs": [
+				{"name": "contractName", "type": "string"},
+				{"name": "method", "type": "string"},
+				{"name": "args", "type": "bytes"}
+			],
+			"outputs": [{"name": "", "type": "bytes"}]
+		}
+	]`
+)
+
+var (
+	bridgeABI abi.ABI
+
+	goContractCallerFactory GoContractCallerFactoryFunc
+)
+
+type bridgeCtxKey struct{}
+
+func init() {
+	var err error
+	bridgeABI, err = abi.JSON(strings.NewReader(goContractBridgeABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// EnableGoContractBridge registers the Go contract bridge, the given factory will be used to call
+// Go contracts from EVM contracts once the EvmGoContractBridgeFeature is enabled.
+func EnableGoContractBridge(factory GoContractCallerFactoryFunc) {
+	goContractCallerFactory = factory
+}
+
+type goContractBridge struct {
+	session *bridgeSession
+}
+
+var _ vm.StatefulPrecompiledContract = &goContractBridge{}
+
+// RequiredGas returns zero because the gas used by the bridge is charged by RunStateful.
+func (b *goContractBridge) RequiredGas(input []byte) uint64 {
+	return 0
+}
+
+func (b *goContractBridge) Run(input []byte) ([]byte, error) {
+	return nil, errors.New("[Go contract bridge] must be called via RunStateful")
+}
+
+func (b *goContractBridge) RunStateful(
+	evm *vm.EVM, caller common.Address, input []byte, suppliedGas uint64, readOnly bool,
+) ([]byte, uint64, error) {
+	return b.session.run(caller, input, suppliedGas, readOnly)
+}
+
+// bridgeSession tracks the calls made to the Go contract bridge by a single EVM instance.
+type bridgeSession struct {
+	state loomchain.State
+	sdb   vm.StateDB
+	// Go contract state changes made via the bridge, each successful call that wasn't read-only adds
+	// a layer on top of the previous one.
+	layers []*store.OverlayStore
+	// Number of layers that existed when each of the EVM snapshots was taken.
+	snapshots map[int]int
+}
+
+func newBridgeSession(state loomchain.State) *bridgeSession {
+	return &bridgeSession{
+		state:     state,
+		snapshots: make(map[int]int),
+	}
+}
+
+// checkCaller returns an error if the EVM is being called by a Go contract invoked via the bridge.
+func (s *bridgeSession) checkCaller() error {
+	if ctx := s.state.Context(); ctx != nil && ctx.Value(bridgeCtxKey{}) != nil {
+		return errors.New("EVM contracts can't be called by Go contracts invoked via the Go contract bridge")
+	}
+	return nil
+}
+
+// top returns the store Go contracts called via the bridge should read from.
+func (s *bridgeSession) top() store.KVReader {
+	if len(s.layers) == 0 {
+		return s.state
+	}
+	return s.layers[len(s.layers)-1]
+}
+
+func (s *bridgeSession) snapshot(id int) {
+	s.snapshots[id] = len(s.layers)
+}
+
+// revertToSnapshot discards all the Go contract state changes made after the given EVM snapshot
+// was taken.
+func (s *bridgeSession) revertToSnapshot(id int) {
+	numLayers, ok := s.snapshots[id]
+	if !ok {
+		panic(errors.Errorf("[Go contract bridge] unknown snapshot %d", id))
+	}
+	s.layers = s.layers[:numLayers]
+	for snapshotID := range s.snapshots {
+		if snapshotID >= id {
+			delete(s.snapshots, snapshotID)
+		}
+	}
+}
+
+// commit writes all the Go contract state changes made via the bridge to the app state.
+func (s *bridgeSession) commit() {
+	for _, layer := range s.layers {
+		for _, entry := range layer.WriteSet() {
+			if entry.Deleted {
+				s.state.Delete(entry.Key)
+			} else {
+				s.state.Set(entry.Key, entry.Value)
+			}
+		}
+	}
+	s.layers = nil
+	s.snapshots = make(map[int]int)
+}
+
+func (s *bridgeSession) run(
+	callerAddr common.Address, input []byte, suppliedGas uint64, callReadOnly bool,
+) ([]byte, uint64, error) {
+	gas := goContractBridgeBaseGas + uint64(len(input))*goContractBridgeGasPerByte
+	if suppliedGas < gas {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	if len(input) < 4 {
+		return nil, 0, errors.New("[Go contract bridge] invalid input")
+	}
+	method, err := bridgeABI.MethodById(input[:4])
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "[Go contract bridge] unknown method")
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "[Go contract bridge] failed to unpack args")
+	}
+	contractName, _ := args[0].(string)
+	contractMethod, _ := args[1].(string)
+	methodArgs, _ := args[2].([]byte)
+	readOnly := callReadOnly || method.Const
+
+	ctx := s.state.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	gasMeter := lvm.NewGasMeter(suppliedGas - gas)
+	ctx = lvm.WithGasMeter(context.WithValue(ctx, bridgeCtxKey{}, true), gasMeter)
+	layer := store.NewOverlayStore(s.top())
+	goCaller := goContractCallerFactory(s.state.WithStore(layer).WithContext(ctx))
+	caller := loom.Address{
+		ChainID: s.state.Block().ChainID,
+		Local:   callerAddr.Bytes(),
+	}
+	result, events, err := goCaller.CallGoContract(caller, contractName, contractMethod, methodArgs, readOnly)
+	if err == nil && gasMeter.IsOutOfGas() {
+		err = lvm.ErrOutOfGas
+	}
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "[Go contract bridge] failed to call %s.%s", contractName, contractMethod)
+	}
+	output, err := method.Outputs.Pack(result)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !readOnly {
+		s.layers = append(s.layers, layer)
+		blockNumber := uint64(s.state.Block().Height)
+		for _, event := range events {
+			s.sdb.AddLog(goContractEventToLog(event, blockNumber))
+		}
+	}
+	return output, suppliedGas - gas - gasMeter.GasUsed(), nil
+}
+
+// bridgeStateDB wraps the StateDB used by an EVM that has the Go contract bridge enabled, so that
+// Go contract state changes are reverted along with the EVM state changes.
+type bridgeStateDB struct {
+	vm.StateDB
+	session *bridgeSession
+}
+
+func newBridgeStateDB(sdb vm.StateDB, session *bridgeSession) *bridgeStateDB {
+	session.sdb = sdb
+	return &bridgeStateDB{
+		StateDB: sdb,
+		session: session,
+	}
+}
+
+func (s *bridgeStateDB) Snapshot() int {
+	id := s.StateDB.Snapshot()
+	s.session.snapshot(id)
+	return id
+}
+
+func (s *bridgeStateDB) RevertToSnapshot(id int) {
+	s.StateDB.RevertToSnapshot(id)
+	s.session.revertToSnapshot(id)
+}
+
+// goContractEventToLog converts an event emitted by a Go contract to an EVM log. Topics that are
+// 32-byte hex strings are used as is, all other topics are hashed.
+func goContractEventToLog(event *ptypes.EventData, blockNumber uint64) *types.Log {
+	topics := make([]common.Hash, 0, len(event.Topics))
+	for _, topic := range event.Topics {
+		if b, err := hexutil.Decode(topic); err == nil && len(b) == common.HashLength {
+			topics = append(topics, common.BytesToHash(b))
+		} else {
+			topics = append(topics, crypto.Keccak256Hash([]byte(topic)))
+		}
+	}
+	var addr common.Address
+	if event.Address != nil {
+		addr = common.BytesToAddress(event.Address.Local)
+	}
+	return &types.Log{
+		Address:     addr,
+		Topics:      topics,
+		Data:        event.EncodedBody,
+		BlockNumber: blockNumber,
+	}
+}
@@ -0,0 +1,191 @@
+// +build evm
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/loomnetwork/go-loom"
+	ptypes "github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
+	"github.com/loomnetwork/loomchain/store"
+	lvm "github.com/loomnetwork/loomchain/vm"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+const (
+	// Forwards the calldata to the Go contract bridge via CALL and returns the result.
+	bridgeCallerCode = "0x601e80600b6000396000f3" +
+		"366000600037" + "600060003660006000610100" + "5af1" + "3d600060003e" + "3d6000f3"
+	// Forwards the calldata to the Go contract bridge via STATICCALL and returns the result.
+	bridgeStaticCallerCode = "0x601c80600b6000396000f3" +
+		"366000600037" + "60006000366000610100" + "5afa" + "3d600060003e" + "3d6000f3"
+	// Forwards the calldata to the Go contract bridge via CALL and then reverts.
+	bridgeRevertingCallerCode = "0x601a80600b6000396000f3" +
+		"366000600037" + "600060003660006000610100" + "5af1" + "50" + "60006000fd"
+)
+
+type goContractCall struct {
+	caller       loom.Address
+	contractName string
+	method       string
+	args         []byte
+	readOnly     bool
+}
+
+// Records all the calls made via the bridge, and stores the args of each call that isn't read-only
+// under the contract name & method.
+type fakeGoContractCaller struct {
+	state loomchain.State
+	calls *[]goContractCall
+}
+
+func (c *fakeGoContractCaller) CallGoContract(
+	caller loom.Address, contractName, method string, args []byte, readOnly bool,
+) ([]byte, []*ptypes.EventData, error) {
+	*c.calls = append(*c.calls, goContractCall{caller, contractName, method, args, readOnly})
+	gasMeter := lvm.GasMeterFromContext(c.state.Context())
+	if gasMeter == nil {
+		return nil, nil, errors.New("missing gas meter")
+	}
+	if method == "expensive" {
+		if err := gasMeter.ConsumeGas(math.MaxUint64); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !readOnly {
+		c.state.Set([]byte(contractName+"."+method), args)
+	}
+	event := &ptypes.EventData{
+		Topics:      []string{"event:Transfer", hexutil.Encode(common.HexToHash("0x1234").Bytes())},
+		Address:     loom.MustParseAddress("default:0x0000000000000000000000000000000000000123").MarshalPB(),
+		EncodedBody: args,
+	}
+	return append([]byte(method), args...), []*ptypes.EventData{event}, nil
+}
+
+func TestGoContractBridge(t *testing.T) {
+	var calls []goContractCall
+	EnableGoContractBridge(func(state loomchain.State) GoContractCaller {
+		return &fakeGoContractCaller{state: state, calls: &calls}
+	})
+	defer EnableGoContractBridge(nil)
+	numPrecompiles := len(vm.PrecompiledContractsByzantium)
+
+	caller := loom.Address{ChainID: "default", Local: common.HexToAddress("0xca11e7").Bytes()}
+	bridgeAddr := loom.Address{ChainID: "default", Local: GoContractBridgeAddress.Bytes()}
+	input, err := bridgeABI.Pack("callGoContract", "coin", "transfer", []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	// The bridge should behave like an empty account until the feature is enabled
+	header := abci.Header{ChainID: "default", Height: BlockHeight, Time: blockTime}
+	state := loomchain.NewStoreState(context.Background(), store.NewMemStore(), header, nil, nil)
+	levm, err := NewLoomEvm(state, nil, nil, false)
+	require.NoError(t, err)
+	ret, err := levm.Call(caller, bridgeAddr, input, nil)
+	require.NoError(t, err)
+	require.Empty(t, ret)
+	require.Len(t, calls, 0)
+
+	state.SetFeature(features.EvmGoContractBridgeFeature, true)
+	levm, err = NewLoomEvm(state, nil, nil, false)
+	require.NoError(t, err)
+	ret, err = levm.Call(caller, bridgeAddr, input, nil)
+	require.NoError(t, err)
+	result, err := bridgeABI.Methods["callGoContract"].Outputs.UnpackValues(ret)
+	require.NoError(t, err)
+	require.Equal(t, []byte("transfer\x01\x02\x03"), result[0])
+	require.Len(t, calls, 1)
+	require.Equal(t, goContractCall{caller, "coin", "transfer", []byte{1, 2, 3}, false}, calls[0])
+	logs := levm.sdb.Logs()
+	require.Len(t, logs, 1)
+	require.Equal(t, common.HexToAddress("0x123"), logs[0].Address)
+	require.Equal(t, crypto.Keccak256Hash([]byte("event:Transfer")), logs[0].Topics[0])
+	require.Equal(t, common.HexToHash("0x1234"), logs[0].Topics[1])
+	require.Equal(t, []byte{1, 2, 3}, logs[0].Data)
+
+	// Go contract state changes are only written to the app state when the EVM state is committed
+	require.Nil(t, state.Get([]byte("coin.transfer")))
+	require.Equal(t, []byte{1, 2, 3}, levm.bridge.top().Get([]byte("coin.transfer")))
+	_, err = levm.Commit()
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, state.Get([]byte("coin.transfer")))
+
+	// Calls made via the static method, or a static call, must be read-only
+	staticInput, err := bridgeABI.Pack("staticCallGoContract", "coin", "balanceOf", []byte{4})
+	require.NoError(t, err)
+	_, err = levm.Call(caller, bridgeAddr, staticInput, nil)
+	require.NoError(t, err)
+	require.True(t, calls[1].readOnly)
+	_, err = levm.StaticCall(caller, bridgeAddr, input)
+	require.NoError(t, err)
+	require.True(t, calls[2].readOnly)
+	require.Len(t, levm.sdb.Logs(), 1)
+
+	// The EVM contract calling the bridge should be the caller seen by the Go contract
+	_, contractAddr, err := levm.Create(caller, hexutil.MustDecode(bridgeCallerCode), nil)
+	require.NoError(t, err)
+	_, staticContractAddr, err := levm.Create(caller, hexutil.MustDecode(bridgeStaticCallerCode), nil)
+	require.NoError(t, err)
+
+	ret, err = levm.Call(caller, contractAddr, input, nil)
+	require.NoError(t, err)
+	result, err = bridgeABI.Methods["callGoContract"].Outputs.UnpackValues(ret)
+	require.NoError(t, err)
+	require.Equal(t, []byte("transfer\x01\x02\x03"), result[0])
+	require.Equal(t, goContractCall{contractAddr, "coin", "transfer", []byte{1, 2, 3}, false}, calls[3])
+	require.Len(t, levm.sdb.Logs(), 2)
+
+	_, err = levm.Call(caller, staticContractAddr, input, nil)
+	require.NoError(t, err)
+	require.Equal(t, goContractCall{staticContractAddr, "coin", "transfer", []byte{1, 2, 3}, true}, calls[4])
+
+	// A CALL made from within a static call must also be read-only
+	_, err = levm.StaticCall(caller, contractAddr, input)
+	require.NoError(t, err)
+	require.True(t, calls[5].readOnly)
+	require.Len(t, levm.sdb.Logs(), 2)
+
+	// Go contract state changes must be reverted along with the EVM call that made them
+	_, revertingContractAddr, err := levm.Create(caller, hexutil.MustDecode(bridgeRevertingCallerCode), nil)
+	require.NoError(t, err)
+	revertedInput, err := bridgeABI.Pack("callGoContract", "coin", "reverted", []byte{5})
+	require.NoError(t, err)
+	_, err = levm.Call(caller, revertingContractAddr, revertedInput, nil)
+	require.Error(t, err)
+	require.Equal(t, "reverted", calls[6].method)
+	require.Len(t, levm.sdb.Logs(), 2)
+	_, err = levm.Commit()
+	require.NoError(t, err)
+	require.Nil(t, state.Get([]byte("coin.reverted")))
+
+	// The Go contract execution is metered
+	expensiveInput, err := bridgeABI.Pack("callGoContract", "coin", "expensive", []byte{6})
+	require.NoError(t, err)
+	_, err = levm.Call(caller, bridgeAddr, expensiveInput, nil)
+	require.Error(t, err)
+	require.Equal(t, "expensive", calls[7].method)
+	_, err = levm.Commit()
+	require.NoError(t, err)
+	require.Nil(t, state.Get([]byte("coin.expensive")))
+
+	// Go contracts invoked via the bridge can't call back into the EVM
+	nestedState := state.WithContext(context.WithValue(context.Background(), bridgeCtxKey{}, true))
+	levm, err = NewLoomEvm(nestedState, nil, nil, false)
+	require.NoError(t, err)
+	_, err = levm.Call(caller, contractAddr, input, nil)
+	require.Error(t, err)
+
+	// The bridge should never be added to the global precompile set
+	require.Len(t, vm.PrecompiledContractsByzantium, numPrecompiles)
+	require.Nil(t, vm.PrecompiledContractsByzantium[GoContractBridgeAddress])
+}
@@ -2,6 +2,8 @@ package evm
 
 import (
 	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain"
 )
 
 // AccountBalanceManager can be implemented to override the builtin account balance management in the EVM.
@@ -14,3 +16,16 @@ type AccountBalanceManager interface {
 }
 
 type AccountBalanceManagerFactoryFunc func(readOnly bool) AccountBalanceManager
+
+// GoContractCaller is used by the Go contract bridge precompile to call Go contracts from EVM contracts.
+type GoContractCaller interface {
+	// CallGoContract calls a method of the Go contract registered under the given name, args should be
+	// the protobuf-encoded method args. Returns the protobuf-encoded method result & any events emitted
+	// by the Go contract.
+	CallGoContract(
+		caller loom.Address, contractName, method string, args []byte, readOnly bool,
+	) ([]byte, []*types.EventData, error)
+}
+
+// GoContractCallerFactoryFunc creates a GoContractCaller that operates on the given state.
+type GoContractCallerFactoryFunc func(state loomchain.State) GoContractCaller
@@ -89,6 +89,9 @@ func (levm LoomEvm) Commit() (common.Hash, error) {
 	if err := levm.db.Put(rootKey, root[:]); err != nil {
 		return root, err
 	}
+	if levm.bridge != nil {
+		levm.bridge.commit()
+	}
 	return root, err
 }
 
@@ -3,8 +3,10 @@
 package evm
 
 import (
+	"github.com/loomnetwork/go-loom"
 	"github.com/loomnetwork/loomchain"
 	lvm "github.com/loomnetwork/loomchain/vm"
+	"github.com/pkg/errors"
 )
 
 var (
@@ -25,3 +27,13 @@ func NewLoomVm(
 }
 
 func AddLoomPrecompiles() {}
+
+func EnableGoContractBridge(factory GoContractCallerFactoryFunc) {}
+
+func NewTracer(cfg TraceConfig) (Tracer, error) {
+	return nil, errors.New("EVM tracing is not supported in this build")
+}
+
+func GetAccountProof(loomState loomchain.State, addr loom.Address, storageKeys [][]byte) (*AccountProof, error) {
+	return nil, errors.New("EVM state proofs are not supported in this build")
+}
@@ -0,0 +1,29 @@
+package evm
+
+import (
+	"math/big"
+)
+
+// AccountProof is a Merkle proof of an EVM account and some of its storage slots, the account proof
+// can be verified against the EVM state root, and the storage proofs against the storage root of the
+// account, see EIP-1186.
+type AccountProof struct {
+	Address []byte
+	// RLP-encoded trie nodes from the state root to the account node.
+	AccountProof [][]byte
+	Balance      *big.Int
+	CodeHash     []byte
+	Nonce        uint64
+	StorageHash  []byte
+	StorageProof []StorageProof
+	// Root of the EVM state trie the account proof was generated against.
+	StateRoot []byte
+}
+
+// StorageProof is a Merkle proof of a single storage slot of an EVM account.
+type StorageProof struct {
+	Key   []byte
+	Value []byte
+	// RLP-encoded trie nodes from the storage root to the slot node.
+	Proof [][]byte
+}
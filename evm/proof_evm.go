@@ -0,0 +1,101 @@
+// +build evm
+
+package evm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/loomchain"
+	"github.com/pkg/errors"
+)
+
+// GetAccountProof generates a Merkle proof of the given EVM account, and of the given storage slots
+// of the account, against the EVM state root stored in the given state.
+func GetAccountProof(loomState loomchain.State, addr loom.Address, storageKeys [][]byte) (*AccountProof, error) {
+	ethDB := NewLoomEthdb(loomState, nil)
+	root, err := ethDB.Get(rootKey)
+	if err != nil {
+		return nil, err
+	}
+	stateRoot := common.BytesToHash(root)
+	stateDB := state.NewDatabase(ethDB)
+	sdb, err := state.New(stateRoot, stateDB)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load EVM state")
+	}
+
+	ethAddr := common.BytesToAddress(addr.Local)
+	stateTrie, err := stateDB.OpenTrie(stateRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open EVM state trie")
+	}
+	accountProof := &proofCollector{}
+	if err := stateTrie.Prove(crypto.Keccak256(ethAddr.Bytes()), 0, accountProof); err != nil {
+		return nil, errors.Wrap(err, "failed to prove account")
+	}
+
+	storageHash := emptyRoot
+	codeHash := emptyCodeHash
+	if sdb.Exist(ethAddr) {
+		if storageTrie := sdb.StorageTrie(ethAddr); storageTrie != nil {
+			storageHash = storageTrie.Hash()
+		}
+		codeHash = sdb.GetCodeHash(ethAddr)
+	}
+
+	result := &AccountProof{
+		Address:      ethAddr.Bytes(),
+		AccountProof: accountProof.nodes,
+		Balance:      sdb.GetBalance(ethAddr),
+		CodeHash:     codeHash.Bytes(),
+		Nonce:        sdb.GetNonce(ethAddr),
+		StorageHash:  storageHash.Bytes(),
+		StorageProof: make([]StorageProof, 0, len(storageKeys)),
+		StateRoot:    stateRoot.Bytes(),
+	}
+
+	var storageTrie state.Trie
+	if storageHash != emptyRoot {
+		storageTrie, err = stateDB.OpenStorageTrie(crypto.Keccak256Hash(ethAddr.Bytes()), storageHash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open storage trie")
+		}
+	}
+	for _, key := range storageKeys {
+		slot := common.BytesToHash(key)
+		storageProof := &proofCollector{}
+		if storageTrie != nil {
+			if err := storageTrie.Prove(crypto.Keccak256(slot.Bytes()), 0, storageProof); err != nil {
+				return nil, errors.Wrapf(err, "failed to prove storage slot %s", slot.Hex())
+			}
+		}
+		result.StorageProof = append(result.StorageProof, StorageProof{
+			Key:   slot.Bytes(),
+			Value: sdb.GetState(ethAddr, slot).Bytes(),
+			Proof: storageProof.nodes,
+		})
+	}
+	return result, nil
+}
+
+var (
+	// Root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	// Hash of empty code.
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+)
+
+// proofCollector collects the trie nodes written to it by Trie.Prove, in the order they're written
+// (from the root to the leaf).
+type proofCollector struct {
+	nodes [][]byte
+}
+
+func (c *proofCollector) Put(key []byte, value []byte) error {
+	node := make([]byte, len(value))
+	copy(node, value)
+	c.nodes = append(c.nodes, node)
+	return nil
+}
@@ -0,0 +1,91 @@
+// +build evm
+
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/loomnetwork/go-loom"
+	"github.com/stretchr/testify/require"
+)
+
+// getWithProof looks up the given key in a trie made up only of the given proof nodes, which fails
+// if the proof is incomplete or doesn't match the root.
+func getWithProof(t *testing.T, root []byte, key []byte, proof [][]byte) []byte {
+	proofDB := ethdb.NewMemDatabase()
+	for _, node := range proof {
+		require.NoError(t, proofDB.Put(crypto.Keccak256(node), node))
+	}
+	tr, err := trie.New(common.BytesToHash(root), trie.NewDatabase(proofDB))
+	require.NoError(t, err)
+	value, err := tr.TryGet(crypto.Keccak256(key))
+	require.NoError(t, err)
+	return value
+}
+
+func TestGetAccountProof(t *testing.T) {
+	state := mockState()
+	levm, err := NewLoomEvm(state, nil, nil, false)
+	require.NoError(t, err)
+
+	contractAddr := loom.Address{ChainID: "default", Local: common.HexToAddress("0x1234").Bytes()}
+	otherAddr := loom.Address{ChainID: "default", Local: common.HexToAddress("0x5678").Bytes()}
+	ethAddr := common.BytesToAddress(contractAddr.Local)
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+	slot := common.BigToHash(big.NewInt(1))
+	levm.sdb.CreateAccount(ethAddr)
+	levm.sdb.SetNonce(ethAddr, 3)
+	levm.sdb.AddBalance(ethAddr, big.NewInt(100))
+	levm.sdb.SetCode(ethAddr, code)
+	levm.sdb.SetState(ethAddr, slot, common.BigToHash(big.NewInt(42)))
+	// the account trie needs more than a single node to make the proof interesting
+	for i := 0; i < 20; i++ {
+		levm.sdb.AddBalance(common.BigToAddress(big.NewInt(int64(1000+i))), big.NewInt(1))
+	}
+	stateRoot, err := levm.Commit()
+	require.NoError(t, err)
+
+	proof, err := GetAccountProof(state, contractAddr, [][]byte{slot.Bytes(), common.BigToHash(big.NewInt(2)).Bytes()})
+	require.NoError(t, err)
+	require.Equal(t, stateRoot.Bytes(), proof.StateRoot)
+	require.Equal(t, ethAddr.Bytes(), proof.Address)
+	require.Equal(t, uint64(3), proof.Nonce)
+	require.Equal(t, int64(100), proof.Balance.Int64())
+	require.Equal(t, crypto.Keccak256(code), proof.CodeHash)
+	require.True(t, len(proof.AccountProof) > 1)
+
+	var account gstate.Account
+	accountData := getWithProof(t, proof.StateRoot, ethAddr.Bytes(), proof.AccountProof)
+	require.NoError(t, rlp.DecodeBytes(accountData, &account))
+	require.Equal(t, proof.Nonce, account.Nonce)
+	require.Equal(t, 0, proof.Balance.Cmp(account.Balance))
+	require.Equal(t, proof.CodeHash, account.CodeHash)
+	require.Equal(t, proof.StorageHash, account.Root.Bytes())
+
+	require.Len(t, proof.StorageProof, 2)
+	var value []byte
+	storageData := getWithProof(t, proof.StorageHash, proof.StorageProof[0].Key, proof.StorageProof[0].Proof)
+	require.NoError(t, rlp.DecodeBytes(storageData, &value))
+	require.Equal(t, common.BigToHash(big.NewInt(42)).Bytes(), proof.StorageProof[0].Value)
+	require.Equal(t, big.NewInt(42), new(big.Int).SetBytes(value))
+	// unset slots are proven to be absent
+	require.Equal(t, common.Hash{}.Bytes(), proof.StorageProof[1].Value)
+	require.Nil(t, getWithProof(t, proof.StorageHash, proof.StorageProof[1].Key, proof.StorageProof[1].Proof))
+
+	// accounts that don't exist are proven to be absent
+	proof, err = GetAccountProof(state, otherAddr, [][]byte{slot.Bytes()})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), proof.Nonce)
+	require.Equal(t, emptyRoot.Bytes(), proof.StorageHash)
+	require.Equal(t, emptyCodeHash.Bytes(), proof.CodeHash)
+	require.Nil(t, getWithProof(t, proof.StateRoot, proof.Address, proof.AccountProof))
+	require.Len(t, proof.StorageProof, 1)
+	require.Empty(t, proof.StorageProof[0].Proof)
+}
@@ -0,0 +1,418 @@
+// +build evm
+
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/pkg/errors"
+)
+
+var errTraceTimeout = errors.New("execution timeout")
+
+// NewTracer creates a tracer that can be attached to a context via WithTracer.
+func NewTracer(cfg TraceConfig) (Tracer, error) {
+	timeoutStr := cfg.Timeout
+	if timeoutStr == "" {
+		timeoutStr = DefaultTraceTimeout
+	}
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid trace timeout %s", timeoutStr)
+	}
+
+	switch cfg.Tracer {
+	case "":
+		return &structTracer{
+			StructLogger: vm.NewStructLogger(&vm.LogConfig{
+				DisableMemory:  cfg.DisableMemory,
+				DisableStack:   cfg.DisableStack,
+				DisableStorage: cfg.DisableStorage,
+				Limit:          cfg.Limit,
+			}),
+			timer: traceTimer{timeout: timeout},
+		}, nil
+	case CallTracer:
+		return &callTracer{
+			timer: traceTimer{timeout: timeout},
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported tracer %s", cfg.Tracer)
+	}
+}
+
+// traceTimer aborts EVM execution once a trace runs for longer than the timeout.
+type traceTimer struct {
+	timeout  time.Duration
+	deadline time.Time
+	timedOut bool
+}
+
+func (t *traceTimer) start() {
+	if t.deadline.IsZero() {
+		t.deadline = time.Now().Add(t.timeout)
+	}
+}
+
+// check cancels EVM execution if the deadline has passed, returns true if the trace timed out.
+func (t *traceTimer) check(env *vm.EVM) bool {
+	if !t.timedOut && time.Now().After(t.deadline) {
+		t.timedOut = true
+		env.Cancel()
+	}
+	return t.timedOut
+}
+
+// ExecutionResult is the trace produced by the struct logger, it matches the output of the default
+// tracer in go-ethereum.
+type ExecutionResult struct {
+	Gas         uint64            `json:"gas"`
+	Failed      bool              `json:"failed"`
+	ReturnValue string            `json:"returnValue"`
+	StructLogs  []StructLogResult `json:"structLogs"`
+}
+
+// StructLogResult is a single opcode executed by the EVM.
+type StructLogResult struct {
+	Pc      uint64             `json:"pc"`
+	Op      string             `json:"op"`
+	Gas     uint64             `json:"gas"`
+	GasCost uint64             `json:"gasCost"`
+	Depth   int                `json:"depth"`
+	Error   string             `json:"error,omitempty"`
+	Stack   *[]string          `json:"stack,omitempty"`
+	Memory  *[]string          `json:"memory,omitempty"`
+	Storage *map[string]string `json:"storage,omitempty"`
+}
+
+// structTracer logs every opcode executed by the EVM. A single Loom tx may execute the EVM multiple
+// times (e.g. a Go contract calling multiple EVM contracts), in which case the logs are concatenated
+// and the gas used is summed up.
+type structTracer struct {
+	*vm.StructLogger
+	timer   traceTimer
+	gasUsed uint64
+	output  []byte
+	err     error
+}
+
+func (t *structTracer) CaptureStart(
+	from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int,
+) error {
+	t.timer.start()
+	return t.StructLogger.CaptureStart(from, to, create, input, gas, value)
+}
+
+func (t *structTracer) CaptureState(
+	env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack,
+	contract *vm.Contract, depth int, err error,
+) error {
+	if t.timer.check(env) {
+		return nil
+	}
+	return t.StructLogger.CaptureState(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+}
+
+func (t *structTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	t.gasUsed += gasUsed
+	t.output = output
+	t.err = err
+	return t.StructLogger.CaptureEnd(output, gasUsed, d, err)
+}
+
+func (t *structTracer) Result() (interface{}, error) {
+	if t.timer.timedOut {
+		return nil, errTraceTimeout
+	}
+	return &ExecutionResult{
+		Gas:         t.gasUsed,
+		Failed:      t.err != nil,
+		ReturnValue: fmt.Sprintf("%x", t.output),
+		StructLogs:  formatStructLogs(t.StructLogs()),
+	}, nil
+}
+
+func formatStructLogs(logs []vm.StructLog) []StructLogResult {
+	formatted := make([]StructLogResult, len(logs))
+	for i, log := range logs {
+		formatted[i] = StructLogResult{
+			Pc:      log.Pc,
+			Op:      log.Op.String(),
+			Gas:     log.Gas,
+			GasCost: log.GasCost,
+			Depth:   log.Depth,
+		}
+		if log.Err != nil {
+			formatted[i].Error = log.Err.Error()
+		}
+		if log.Stack != nil {
+			stack := make([]string, len(log.Stack))
+			for j, value := range log.Stack {
+				stack[j] = fmt.Sprintf("%x", math.PaddedBigBytes(value, 32))
+			}
+			formatted[i].Stack = &stack
+		}
+		if log.Memory != nil {
+			memory := make([]string, 0, (len(log.Memory)+31)/32)
+			for j := 0; j+32 <= len(log.Memory); j += 32 {
+				memory = append(memory, fmt.Sprintf("%x", log.Memory[j:j+32]))
+			}
+			formatted[i].Memory = &memory
+		}
+		if log.Storage != nil {
+			storage := make(map[string]string, len(log.Storage))
+			for key, value := range log.Storage {
+				storage[fmt.Sprintf("%x", key)] = fmt.Sprintf("%x", value)
+			}
+			formatted[i].Storage = &storage
+		}
+	}
+	return formatted
+}
+
+// callFrame is a single call in the trace produced by the call tracer.
+type callFrame struct {
+	Type    string       `json:"type"`
+	From    string       `json:"from"`
+	To      string       `json:"to,omitempty"`
+	Value   string       `json:"value,omitempty"`
+	Gas     string       `json:"gas,omitempty"`
+	GasUsed string       `json:"gasUsed,omitempty"`
+	Input   string       `json:"input"`
+	Output  string       `json:"output,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Calls   []*callFrame `json:"calls,omitempty"`
+
+	gas            uint64 // gas available within the call, zero if unknown
+	gasIn, gasCost uint64
+	outOff, outLen uint64
+}
+
+// callTracer records the tree of calls made during EVM execution, it's a port of the JS callTracer
+// that ships with go-ethereum. A single Loom tx may execute the EVM multiple times (e.g. a Go
+// contract calling multiple EVM contracts), in which case a list of call trees is produced.
+type callTracer struct {
+	timer     traceTimer
+	callstack []*callFrame
+	descended bool
+	results   []*callFrame
+}
+
+func (t *callTracer) CaptureStart(
+	from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int,
+) error {
+	t.timer.start()
+	frame := &callFrame{
+		Type:  "CALL",
+		From:  addressToHex(from),
+		To:    addressToHex(to),
+		Input: hexutil.Encode(input),
+		Gas:   hexutil.EncodeUint64(gas),
+	}
+	if create {
+		frame.Type = "CREATE"
+	}
+	if value != nil {
+		frame.Value = hexutil.EncodeBig(value)
+	}
+	t.callstack = []*callFrame{frame}
+	t.descended = false
+	return nil
+}
+
+func (t *callTracer) CaptureState(
+	env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack,
+	contract *vm.Contract, depth int, err error,
+) error {
+	if t.timer.check(env) || len(t.callstack) == 0 {
+		return nil
+	}
+	if err != nil {
+		return t.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		inOff, inLen := stackUint64(stack, 1), stackUint64(stack, 2)
+		t.callstack = append(t.callstack, &callFrame{
+			Type:    op.String(),
+			From:    addressToHex(contract.Address()),
+			Input:   hexutil.Encode(memorySlice(memory, inOff, inLen)),
+			Value:   hexutil.EncodeBig(stack.Back(0)),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+		t.descended = true
+		return nil
+
+	case vm.SELFDESTRUCT:
+		t.appendCall(&callFrame{
+			Type:  op.String(),
+			From:  addressToHex(contract.Address()),
+			To:    addressToHex(common.BigToAddress(stack.Back(0))),
+			Input: "0x",
+			Value: hexutil.EncodeBig(env.StateDB.GetBalance(contract.Address())),
+		})
+		return nil
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		to := common.BigToAddress(stack.Back(1))
+		// Skip pre-compiles, they're just fancy opcodes
+		if _, ok := vm.PrecompiledContractsByzantium[to]; ok {
+			return nil
+		}
+		off := 1
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 0
+		}
+		inOff, inLen := stackUint64(stack, 2+off), stackUint64(stack, 3+off)
+		frame := &callFrame{
+			Type:    op.String(),
+			From:    addressToHex(contract.Address()),
+			To:      addressToHex(to),
+			Input:   hexutil.Encode(memorySlice(memory, inOff, inLen)),
+			gasIn:   gas,
+			gasCost: cost,
+			outOff:  stackUint64(stack, 4+off),
+			outLen:  stackUint64(stack, 5+off),
+		}
+		if off == 1 {
+			frame.Value = hexutil.EncodeBig(stack.Back(2))
+		}
+		t.callstack = append(t.callstack, frame)
+		t.descended = true
+		return nil
+	}
+
+	// If we've just descended into an inner call retrieve its true gas allowance, which may differ
+	// from the requested amount (2300 stipend, 63/64 rule).
+	if t.descended {
+		if depth >= len(t.callstack) {
+			t.callstack[len(t.callstack)-1].gas = gas
+		}
+		t.descended = false
+	}
+
+	if op == vm.REVERT {
+		t.callstack[len(t.callstack)-1].Error = "execution reverted"
+		return nil
+	}
+
+	// If an inner call just returned pop it off the call stack.
+	if depth == len(t.callstack)-1 {
+		frame := t.callstack[len(t.callstack)-1]
+		t.callstack = t.callstack[:len(t.callstack)-1]
+
+		ret := stack.Back(0)
+		if frame.Type == vm.CREATE.String() || frame.Type == vm.CREATE2.String() {
+			frame.GasUsed = hexutil.EncodeUint64(frame.gasIn - frame.gasCost - gas)
+			if ret.Sign() != 0 {
+				addr := common.BigToAddress(ret)
+				frame.To = addressToHex(addr)
+				frame.Output = hexutil.Encode(env.StateDB.GetCode(addr))
+			} else if frame.Error == "" {
+				frame.Error = "internal failure"
+			}
+		} else {
+			if frame.gas != 0 {
+				frame.GasUsed = hexutil.EncodeUint64(frame.gasIn - frame.gasCost + frame.gas - gas)
+			}
+			if ret.Sign() != 0 {
+				frame.Output = hexutil.Encode(memorySlice(memory, frame.outOff, frame.outLen))
+			} else if frame.Error == "" {
+				frame.Error = "internal failure"
+			}
+		}
+		if frame.gas != 0 {
+			frame.Gas = hexutil.EncodeUint64(frame.gas)
+		}
+		t.appendCall(frame)
+	}
+	return nil
+}
+
+func (t *callTracer) CaptureFault(
+	env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack,
+	contract *vm.Contract, depth int, err error,
+) error {
+	if len(t.callstack) == 0 || t.callstack[len(t.callstack)-1].Error != "" {
+		// the topmost call already failed, don't handle the additional fault
+		return nil
+	}
+	frame := t.callstack[len(t.callstack)-1]
+	frame.Error = err.Error()
+	// the failed call consumes all the gas available to it
+	if frame.gas != 0 {
+		frame.Gas = hexutil.EncodeUint64(frame.gas)
+		frame.GasUsed = frame.Gas
+	}
+	if len(t.callstack) > 1 {
+		t.callstack = t.callstack[:len(t.callstack)-1]
+		t.appendCall(frame)
+	}
+	return nil
+}
+
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if len(t.callstack) == 0 {
+		return nil
+	}
+	root := t.callstack[0]
+	root.GasUsed = hexutil.EncodeUint64(gasUsed)
+	root.Output = hexutil.Encode(output)
+	if err != nil {
+		root.Error = err.Error()
+	}
+	t.results = append(t.results, root)
+	t.callstack = nil
+	return nil
+}
+
+func (t *callTracer) Result() (interface{}, error) {
+	if t.timer.timedOut {
+		return nil, errTraceTimeout
+	}
+	if len(t.results) == 1 {
+		return t.results[0], nil
+	}
+	return t.results, nil
+}
+
+func (t *callTracer) appendCall(frame *callFrame) {
+	parent := t.callstack[len(t.callstack)-1]
+	parent.Calls = append(parent.Calls, frame)
+}
+
+func addressToHex(addr common.Address) string {
+	return strings.ToLower(addr.Hex())
+}
+
+// Returns the n-th item from the top of the stack as a uint64, values that don't fit are clamped.
+func stackUint64(stack *vm.Stack, n int) uint64 {
+	value := stack.Back(n)
+	if !value.IsUint64() {
+		return ^uint64(0)
+	}
+	return value.Uint64()
+}
+
+// Returns a copy of the given memory range, the range is truncated if it extends beyond the memory
+// that's been allocated.
+func memorySlice(memory *vm.Memory, offset, size uint64) []byte {
+	data := memory.Data()
+	if size == 0 || offset >= uint64(len(data)) {
+		return nil
+	}
+	end := offset + size
+	if end < offset || end > uint64(len(data)) {
+		end = uint64(len(data))
+	}
+	return common.CopyBytes(data[offset:end])
+}
@@ -0,0 +1,50 @@
+package evm
+
+import (
+	"context"
+)
+
+const (
+	// CallTracer is the name of the tracer that records the tree of calls made during EVM execution,
+	// the output is compatible with the callTracer in go-ethereum.
+	CallTracer = "callTracer"
+	// DefaultTraceTimeout is the max amount of time a single trace is allowed to run for.
+	DefaultTraceTimeout = "5s"
+)
+
+// TraceConfig specifies how EVM execution should be traced, it mirrors the tracing options accepted
+// by the debug_trace* methods in go-ethereum.
+type TraceConfig struct {
+	DisableStorage bool
+	DisableMemory  bool
+	DisableStack   bool
+	// Max number of struct logs to capture, zero means unlimited.
+	Limit int
+	// Name of the tracer to use, leave empty to use the struct logger.
+	Tracer string
+	// Max amount of time the trace is allowed to run for, e.g. "10s", DefaultTraceTimeout is used
+	// if this is empty.
+	Timeout string
+}
+
+// Tracer collects a trace of EVM execution.
+type Tracer interface {
+	// Result returns the JSON-serializable trace collected by the tracer.
+	Result() (interface{}, error)
+}
+
+type tracerCtxKey struct{}
+
+// WithTracer returns a copy of the given context that carries the given tracer, all EVM execution
+// performed against state with this context will be traced.
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerCtxKey{}, tracer)
+}
+
+func tracerFromContext(ctx context.Context) Tracer {
+	if ctx == nil {
+		return nil
+	}
+	tracer, _ := ctx.Value(tracerCtxKey{}).(Tracer)
+	return tracer
+}
@@ -154,4 +154,38 @@ const (
 
 	// Enables Constantinople hard fork in EVM interpreter
 	EvmConstantinopleFeature = "evm:constantinople"
+
+	// Enables Petersburg hard fork in EVM interpreter, implies EvmConstantinopleFeature
+	EvmPetersburgFeature = "evm:petersburg"
+
+	// Enables Istanbul hard fork in EVM interpreter (adds the CHAINID & SELFBALANCE opcodes, and
+	// reprices some of the existing opcodes), implies EvmPetersburgFeature
+	EvmIstanbulFeature = "evm:istanbul"
+
+	// Enables the precompile that allows EVM contracts to call Go contracts
+	EvmGoContractBridgeFeature = "evm:go-contract-bridge"
+
+	// Enables deployment & execution of WebAssembly contracts
+	WasmVMFeature = "vm:wasm"
+
+	// Enables gas metering of Go contract calls
+	GoContractGasMeteringFeature = "plugin:gas-metering"
+
+	// Enables txs that upgrade named contracts & transfer name ownership (requires registry v3)
+	RegistryTxFeature = "tx:registry"
+
+	// Enables M-of-N multisig accounts in MultiChainSignatureTxMiddleware
+	MultiSigAccountFeature = "auth:multisig"
+
+	// Enables the expiry height & time in ExtendedNonceTx to be checked by the nonce middleware
+	TxExpiryFeature = "auth:tx-expiry"
+
+	// Enables the nonce middleware to track an independent sequence for each nonce lane in ExtendedNonceTx
+	NonceLanesFeature = "auth:nonce-lanes"
+
+	// Enables txs to be sponsored by another account, which pays for the tx instead of the origin
+	SponsoredTxFeature = "auth:sponsored-tx"
+
+	// Enables the state tx limiter middleware, and restricts the in-memory karma throttle to CheckTx
+	StateTxLimiterFeature = "tx:state-limiter"
 )
@@ -24,3 +24,18 @@ func TestUnmarshalReactorState(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, rs.Messages)
 }
+
+func TestPersistReactorStateMessages(t *testing.T) {
+	db := dbm.NewMemDB()
+	rs := NewReactorState()
+	rs.CurrentNonces["fn1"] = 5
+	rs.Messages["fn1"] = Message{Payload: []byte("payload"), Hash: []byte("hash")}
+
+	require.NoError(t, saveReactorState(db, rs, false))
+
+	rs, err := loadReactorState(db)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), rs.CurrentNonces["fn1"])
+	require.Equal(t, []byte("payload"), rs.Messages["fn1"].Payload)
+	require.Equal(t, []byte("hash"), rs.Messages["fn1"].Hash)
+}
@@ -166,6 +166,13 @@ func (f *FnConsensusReactor) OnStart() error {
 	}
 
 	f.state = reactorState
+	for fnID, nonce := range f.state.CurrentNonces {
+		nonceGauge.With("fnID", fnID).Set(float64(nonce))
+	}
+	f.Logger.Info(
+		"FnConsensusReactor: restored reactor state",
+		"votesInProgress", len(f.state.CurrentVoteSets), "fnCount", len(f.state.CurrentNonces),
+	)
 
 	go f.initRoutine()
 
@@ -966,6 +973,15 @@ func (f *FnConsensusReactor) handleVoteSetChannelMessage(sender p2p.Peer, msgByt
 		return
 	}
 
+	// Persist the updated voteset so the vote can resume where it left off if the node restarts.
+	if err := saveReactorState(f.db, f.state, true); err != nil {
+		f.Logger.Error(
+			"FnConsensusReactor: unable to save state",
+			"fnID", fnID, "err", err, "method", voteSetMsgHandlerMethodID,
+		)
+		return
+	}
+
 	marshalledBytes, err := currentVoteSet.Marshal()
 	if err != nil {
 		f.Logger.Error(
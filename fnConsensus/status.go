@@ -0,0 +1,84 @@
+package fnConsensus
+
+import (
+	"sort"
+)
+
+// FnStatus describes the progress the reactor has made with a single Fn.
+type FnStatus struct {
+	FnID string
+	// Indicates whether the Fn is currently registered with the reactor, Fns that were registered
+	// before the node restarted will only show up as registered once they're registered again.
+	Registered bool
+	// Nonce of the vote currently in progress, or of the next vote.
+	Nonce int64
+	// Indicates whether a vote is currently in progress.
+	VoteInProgress bool
+	// Number of validators that signed the vote currently in progress.
+	NumSigned int
+	// Number of validators that are expected to sign the vote currently in progress.
+	NumValidators int
+}
+
+// ReactorStatus describes the current state of the reactor.
+type ReactorStatus struct {
+	IsValidator bool
+	Fns         []*FnStatus
+}
+
+// Status returns the current state of the reactor, including the state of any Fns that were
+// registered prior to the last restart.
+func (f *FnConsensusReactor) Status() *ReactorStatus {
+	status := &ReactorStatus{
+		IsValidator: f.cfg.IsValidator,
+		Fns:         []*FnStatus{},
+	}
+	// Non-validators don't track any state
+	if !f.cfg.IsValidator {
+		return status
+	}
+
+	fns := map[string]*FnStatus{}
+	for _, fnID := range f.fnRegistry.GetAll() {
+		fns[fnID] = &FnStatus{
+			FnID:       fnID,
+			Registered: true,
+			Nonce:      1,
+		}
+	}
+
+	f.stateMtx.Lock()
+	defer f.stateMtx.Unlock()
+
+	// The state is only loaded once the reactor starts
+	if f.state == nil {
+		return status
+	}
+
+	for fnID, nonce := range f.state.CurrentNonces {
+		if _, ok := fns[fnID]; !ok {
+			fns[fnID] = &FnStatus{FnID: fnID}
+		}
+		fns[fnID].Nonce = nonce
+	}
+
+	for fnID, voteSet := range f.state.CurrentVoteSets {
+		if _, ok := fns[fnID]; !ok {
+			fns[fnID] = &FnStatus{FnID: fnID}
+		}
+		fns[fnID].Nonce = voteSet.Nonce
+		fns[fnID].VoteInProgress = true
+		fns[fnID].NumSigned = voteSet.NumberOfVotes()
+		if voteSet.VoteBitArray != nil {
+			fns[fnID].NumValidators = voteSet.VoteBitArray.Size()
+		}
+	}
+
+	for _, fnStatus := range fns {
+		status.Fns = append(status.Fns, fnStatus)
+	}
+	sort.Slice(status.Fns, func(i, j int) bool {
+		return status.Fns[i].FnID < status.Fns[j].FnID
+	})
+	return status
+}
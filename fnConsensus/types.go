@@ -28,6 +28,12 @@ type fnIDToNonce struct {
 	FnID  string
 }
 
+type fnIDToMessage struct {
+	FnID    string
+	Payload []byte
+	Hash    []byte
+}
+
 type FnIndividualExecutionResponse struct {
 	Hash            []byte
 	OracleSignature []byte
@@ -43,6 +49,8 @@ type reactorStateMarshallable struct {
 	PreviousTimedOutVoteSets []*FnVoteSet
 	PreviousMajVoteSets      []*FnVoteSet
 	PreviousValidatorSet     *types.ValidatorSet
+	// NOTE: New fields must be added at the end to remain compatible with previously persisted state.
+	Messages []*fnIDToMessage
 }
 
 type ReactorState struct {
@@ -76,6 +84,7 @@ func (p *ReactorState) Marshal() ([]byte, error) {
 		PreviousTimedOutVoteSets: make([]*FnVoteSet, len(p.PreviousTimedOutVoteSets)),
 		PreviousMajVoteSets:      make([]*FnVoteSet, len(p.PreviousMajVoteSets)),
 		PreviousValidatorSet:     p.PreviousValidatorSet,
+		Messages:                 make([]*fnIDToMessage, len(p.Messages)),
 	}
 
 	i := 0
@@ -105,6 +114,18 @@ func (p *ReactorState) Marshal() ([]byte, error) {
 		i++
 	}
 
+	// The messages are needed to submit the result of any vote that was in progress when the node
+	// stopped, so they must be persisted along with the current votesets.
+	i = 0
+	for fnID, message := range p.Messages {
+		reactorStateMarshallable.Messages[i] = &fnIDToMessage{
+			FnID:    fnID,
+			Payload: message.Payload,
+			Hash:    message.Hash,
+		}
+		i++
+	}
+
 	return cdc.MarshalBinaryLengthPrefixed(reactorStateMarshallable)
 }
 
@@ -137,6 +158,13 @@ func (p *ReactorState) Unmarshal(bz []byte) error {
 		p.PreviousMajVoteSets[maj23VoteSet.Payload.Request.FnID] = maj23VoteSet
 	}
 
+	for _, message := range reactorStateMarshallable.Messages {
+		p.Messages[message.FnID] = Message{
+			Payload: message.Payload,
+			Hash:    message.Hash,
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,77 @@
+// +build evm
+
+package integration_tests
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/loomchain/evm"
+	"github.com/loomnetwork/loomchain/features"
+	"github.com/loomnetwork/loomchain/plugin"
+	"github.com/stretchr/testify/require"
+)
+
+// The contracts below are hand assembled since the Solidity compiler version used for the other test
+// contracts doesn't support the Istanbul opcodes. The deploy code of both contracts copies the 9 byte
+// runtime code following it into memory and returns it, the runtime code pushes the result of a single
+// opcode onto the stack, stores it in memory, and returns it.
+const (
+	// CHAINID PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	chainIDContractCode = "0x600980600b6000396000f3" + "4660005260206000f3"
+	// SELFBALANCE PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	selfBalanceContractCode = "0x600980600b6000396000f3" + "4760005260206000f3"
+)
+
+func TestEvmIstanbulOpcodes(t *testing.T) {
+	caller := loom.MustParseAddress("chain:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+	fakeCtx := plugin.CreateFakeContextWithEVM(caller, loom.RootAddress("chain"))
+
+	ethCoin, err := deployEthCoinContract(fakeCtx)
+	require.NoError(t, err)
+
+	chainIDAddr, err := deployCodeToEVM(fakeCtx, chainIDContractCode, caller)
+	require.NoError(t, err)
+	selfBalanceAddr, err := deployCodeToEVM(fakeCtx, selfBalanceContractCode, caller)
+	require.NoError(t, err)
+	amount := sciNot(5, 18)
+	require.NoError(t, ethCoin.mint(fakeCtx, selfBalanceAddr, amount))
+
+	// the new opcodes are invalid until Istanbul is enabled
+	_, err = staticCallEVMCode(fakeCtx, chainIDAddr)
+	require.Error(t, err)
+	_, err = staticCallEVMCode(fakeCtx, selfBalanceAddr)
+	require.Error(t, err)
+
+	// enabling Constantinople or Petersburg shouldn't make a difference
+	fakeCtx.State.SetFeature(features.EvmPetersburgFeature, true)
+	_, err = staticCallEVMCode(fakeCtx, chainIDAddr)
+	require.Error(t, err)
+
+	fakeCtx.State.SetFeature(features.EvmIstanbulFeature, true)
+	output, err := staticCallEVMCode(fakeCtx, chainIDAddr)
+	require.NoError(t, err)
+	// the EVM chain ID is always zero for now
+	require.Equal(t, common.LeftPadBytes(nil, 32), output)
+
+	output, err = staticCallEVMCode(fakeCtx, selfBalanceAddr)
+	require.NoError(t, err)
+	require.Equal(t, amount.String(), new(big.Int).SetBytes(output).String())
+}
+
+func deployCodeToEVM(ctx *plugin.FakeContextWithEVM, code string, caller loom.Address) (loom.Address, error) {
+	vm := evm.NewLoomVm(ctx.State, nil, nil, nil, false)
+	_, contractAddr, err := vm.Create(caller, common.FromHex(code), loom.NewBigUIntFromInt(0))
+	if err != nil {
+		return contractAddr, err
+	}
+	ctx.RegisterContract("", contractAddr, caller)
+	return contractAddr, nil
+}
+
+func staticCallEVMCode(ctx *plugin.FakeContextWithEVM, addr loom.Address) ([]byte, error) {
+	vm := evm.NewLoomVm(ctx.State, nil, nil, ctx.AccountBalanceManager, false)
+	return vm.StaticCall(ctx.Message().Sender, addr, nil)
+}
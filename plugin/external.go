@@ -8,8 +8,10 @@ import (
 	"os/exec"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	extplugin "github.com/hashicorp/go-plugin"
 	"google.golang.org/grpc"
@@ -54,16 +56,17 @@ func isExec(f os.FileInfo) bool {
 	return !f.IsDir() && f.Size() > 0 && f.Mode()&0111 > 0
 }
 
-func discoverExec(dir string) ([]string, error) {
+// discoverExec returns info about all the executables in the given dir.
+func discoverExec(dir string) ([]os.FileInfo, error) {
 	files, err := ioutil.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	var execs []string
+	var execs []os.FileInfo
 	for _, file := range files {
 		if isExec(file) {
-			execs = append(execs, file.Name())
+			execs = append(execs, file)
 		}
 	}
 
@@ -95,30 +98,123 @@ func loadExternal(path string) *extplugin.Client {
 	return extplugin.NewClient(cfg)
 }
 
+// ExternalPluginInfo describes an external plugin executable found in the plugins dir.
+type ExternalPluginInfo struct {
+	Name    string
+	Version string
+	File    string
+	// Indicates whether a process is currently running for this plugin.
+	Loaded bool
+}
+
+// ReloadableLoader is implemented by loaders that can pick up new & updated contracts without
+// restarting the node.
+type ReloadableLoader interface {
+	ListPlugins() ([]*ExternalPluginInfo, error)
+	// ReloadPlugins unloads any plugins whose executables have been replaced or removed since they
+	// were loaded, the new executables will be loaded next time the plugins are used. Returns the
+	// names of the plugins that were unloaded.
+	ReloadPlugins() ([]string, error)
+}
+
+// externalClient tracks the number of in-flight calls to an external plugin process, so the process
+// can be drained before it's killed.
+type externalClient struct {
+	*extplugin.Client
+	file    os.FileInfo
+	mu      sync.Mutex
+	active  int
+	retired bool
+}
+
+// acquire must be called before calling the plugin, returns false if the plugin has been superseded
+// and shouldn't be called anymore.
+func (c *externalClient) acquire() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.retired {
+		return false
+	}
+	c.active++
+	return true
+}
+
+// release must be called when a call to the plugin completes.
+func (c *externalClient) release() {
+	c.mu.Lock()
+	c.active--
+	drained := c.retired && c.active == 0
+	c.mu.Unlock()
+	if drained {
+		go c.Kill()
+	}
+}
+
+// retire prevents any new calls to the plugin, and kills the plugin process once all in-flight calls
+// complete.
+func (c *externalClient) retire() {
+	c.mu.Lock()
+	c.retired = true
+	drained := c.active == 0
+	c.mu.Unlock()
+	if drained {
+		go c.Kill()
+	}
+}
+
+// isStale checks if the plugin executable has been modified or removed since the plugin was loaded.
+func (c *externalClient) isStale(files []os.FileInfo) bool {
+	for _, file := range files {
+		if file.Name() == c.file.Name() {
+			return file.Size() != c.file.Size() || !file.ModTime().Equal(c.file.ModTime())
+		}
+	}
+	return true
+}
+
 type ExternalLoader struct {
 	Dir     string
-	clients map[string]*extplugin.Client
-	mu      sync.Mutex
+	clients map[string]*externalClient
+	// Superseded clients that may still be draining
+	retired []*externalClient
+	// Stops the goroutine started by Watch, nil if the plugins dir isn't being watched
+	stopWatching func()
+	mu           sync.Mutex
 }
 
 var _ Loader = &ExternalLoader{}
+var _ ReloadableLoader = &ExternalLoader{}
 
 func NewExternalLoader(dir string) *ExternalLoader {
 	return &ExternalLoader{
 		Dir:     dir,
-		clients: make(map[string]*extplugin.Client),
+		clients: make(map[string]*externalClient),
 	}
 }
 
-func (l *ExternalLoader) UnloadContracts() { l.Kill() }
+// UnloadContracts stops watching the plugins dir and kills all the plugin processes.
+func (l *ExternalLoader) UnloadContracts() {
+	l.mu.Lock()
+	stopWatching := l.stopWatching
+	l.stopWatching = nil
+	l.mu.Unlock()
+	if stopWatching != nil {
+		stopWatching()
+	}
+	l.Kill()
+}
 
 func (l *ExternalLoader) Kill() {
 	var wg sync.WaitGroup
 	l.mu.Lock()
+	clients := l.retired
 	for _, client := range l.clients {
+		clients = append(clients, client)
+	}
+	for _, client := range clients {
 		wg.Add(1)
 
-		go func(client *extplugin.Client) {
+		go func(client *externalClient) {
 			client.Kill()
 			wg.Done()
 		}(client)
@@ -128,20 +224,37 @@ func (l *ExternalLoader) Kill() {
 }
 
 func (l *ExternalLoader) LoadContract(name string, blockHeight int64) (plugin.Contract, error) {
-	client, err := l.loadClient(name)
+	client, contract, err := l.loadContract(name)
 	if err != nil {
 		return nil, err
 	}
+	return &externalContract{
+		loader:   l,
+		name:     name,
+		client:   client,
+		contract: contract,
+	}, nil
+}
+
+func (l *ExternalLoader) loadContract(name string) (*externalClient, plugin.Contract, error) {
+	client, err := l.loadClient(name)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	rpcClient, err := client.Client()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return fetchContract(rpcClient)
+	contract, err := fetchContract(rpcClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, contract, nil
 }
 
-func (l *ExternalLoader) loadClient(name string) (*extplugin.Client, error) {
+func (l *ExternalLoader) loadClient(name string) (*externalClient, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -159,7 +272,7 @@ func (l *ExternalLoader) loadClient(name string) (*extplugin.Client, error) {
 	return client, nil
 }
 
-func (l *ExternalLoader) loadClientFull(name string) (*extplugin.Client, error) {
+func (l *ExternalLoader) loadClientFull(name string) (*externalClient, error) {
 	files, err := discoverExec(l.Dir)
 	if err != nil {
 		return nil, ErrPluginNotFound
@@ -170,13 +283,13 @@ func (l *ExternalLoader) loadClientFull(name string) (*extplugin.Client, error)
 		return nil, err
 	}
 
-	var found string
+	var found os.FileInfo
 	for _, file := range files {
-		if strings.Contains(file, ".so.") {
+		if strings.Contains(file.Name(), ".so.") {
 			continue
 		}
 
-		info, err := parseFileName(file)
+		info, err := parseFileName(file.Name())
 		if err != nil {
 			continue
 		}
@@ -187,11 +300,186 @@ func (l *ExternalLoader) loadClientFull(name string) (*extplugin.Client, error)
 		}
 	}
 
-	if found == "" {
+	if found == nil {
 		return nil, ErrPluginNotFound
 	}
 
-	return loadExternal(path.Join(l.Dir, found)), nil
+	return &externalClient{
+		Client: loadExternal(path.Join(l.Dir, found.Name())),
+		file:   found,
+	}, nil
+}
+
+// ListPlugins returns info about all the external plugin executables in the plugins dir.
+func (l *ExternalLoader) ListPlugins() ([]*ExternalPluginInfo, error) {
+	files, err := discoverExec(l.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var plugins []*ExternalPluginInfo
+	for _, file := range files {
+		if strings.Contains(file.Name(), ".so.") {
+			continue
+		}
+		info, err := parseFileName(file.Name())
+		if err != nil {
+			continue
+		}
+		client := l.clients[info.Base+":"+info.Version]
+		plugins = append(plugins, &ExternalPluginInfo{
+			Name:    info.Base,
+			Version: info.Version,
+			File:    file.Name(),
+			Loaded:  client != nil && !client.isStale([]os.FileInfo{file}),
+		})
+	}
+	return plugins, nil
+}
+
+// ReloadPlugins unloads any plugins whose executables have been replaced or removed since they were
+// loaded. Plugin processes that are currently handling calls are killed once those calls complete.
+func (l *ExternalLoader) ReloadPlugins() ([]string, error) {
+	files, err := discoverExec(l.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var unloaded []string
+	for name, client := range l.clients {
+		if !client.isStale(files) {
+			continue
+		}
+		delete(l.clients, name)
+		client.retire()
+		l.retired = append(l.retired, client)
+		unloaded = append(unloaded, name)
+	}
+	sort.Strings(unloaded)
+
+	// forget the retired clients that have already been killed
+	retired := l.retired[:0]
+	for _, client := range l.retired {
+		if !client.Exited() {
+			retired = append(retired, client)
+		}
+	}
+	l.retired = retired
+	return unloaded, nil
+}
+
+// Watch periodically checks the plugins dir for new & updated plugin executables until the loader's
+// contracts are unloaded. Updated plugins are reloaded, new plugins are loaded when they're first used.
+func (l *ExternalLoader) Watch(interval time.Duration, logger *loom.Logger) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopWatching != nil {
+		return
+	}
+	l.stopWatching = func() {
+		close(quit)
+		<-done
+	}
+	go func() {
+		defer close(done)
+		known := map[string]bool{}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if plugins, err := l.ListPlugins(); err != nil {
+				logger.Error("Failed to list external plugins", "err", err)
+			} else {
+				for _, p := range plugins {
+					if !known[p.File] {
+						known[p.File] = true
+						logger.Info("Found external plugin", "name", p.Name, "version", p.Version)
+					}
+				}
+			}
+			if unloaded, err := l.ReloadPlugins(); err != nil {
+				logger.Error("Failed to reload external plugins", "err", err)
+			} else if len(unloaded) > 0 {
+				logger.Info("Reloaded external plugins", "plugins", strings.Join(unloaded, ","))
+			}
+
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// externalContract wraps a contract loaded from an external plugin, so the plugin process isn't
+// killed while a call to it is in flight.
+type externalContract struct {
+	loader   *ExternalLoader
+	name     string
+	client   *externalClient
+	contract plugin.Contract
+}
+
+var _ plugin.Contract = &externalContract{}
+
+// acquire returns the contract that should be called, the plugin may have been reloaded since the
+// contract was loaded, in which case the contract will be loaded from the new plugin.
+func (c *externalContract) acquire() (plugin.Contract, *externalClient, error) {
+	if c.client.acquire() {
+		return c.contract, c.client, nil
+	}
+	client, contract, err := c.loader.loadContract(c.name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !client.acquire() {
+		return nil, nil, errors.New("plugin was reloaded while in use")
+	}
+	return contract, client, nil
+}
+
+func (c *externalContract) Meta() (plugin.Meta, error) {
+	contract, client, err := c.acquire()
+	if err != nil {
+		return plugin.Meta{}, err
+	}
+	defer client.release()
+	return contract.Meta()
+}
+
+func (c *externalContract) Init(ctx plugin.Context, req *plugin.Request) error {
+	contract, client, err := c.acquire()
+	if err != nil {
+		return err
+	}
+	defer client.release()
+	return contract.Init(ctx, req)
+}
+
+func (c *externalContract) Call(ctx plugin.Context, req *plugin.Request) (*plugin.Response, error) {
+	contract, client, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer client.release()
+	return contract.Call(ctx, req)
+}
+
+func (c *externalContract) StaticCall(ctx plugin.StaticContext, req *plugin.Request) (*plugin.Response, error) {
+	contract, client, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer client.release()
+	return contract.StaticCall(ctx, req)
 }
 
 type GRPCAPIServer struct {
@@ -342,7 +630,7 @@ func bootApiServer(broker *extplugin.GRPCBroker, apiServer *GRPCAPIServer) (*grp
 	wg.Add(1)
 	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
 		defer wg.Done()
-		s = grpc.NewServer(opts...)
+		s = grpc.NewServer(append(opts, grpc.UnaryInterceptor(recoverOutOfGasInterceptor))...)
 		types.RegisterAPIServer(s, apiServer)
 		return s
 	}
@@ -356,6 +644,17 @@ func bootApiServer(broker *extplugin.GRPCBroker, apiServer *GRPCAPIServer) (*grp
 	return s, brokerID
 }
 
+// recoverOutOfGasInterceptor fails API calls made by external contracts that run out of gas. The API
+// server handles each call on its own goroutine, so the out of gas panic raised by the contract context
+// can't be recovered by PluginVM.run. The gas meter is left marked as out of gas, so the tx will fail
+// even if the contract ignores the error.
+func recoverOutOfGasInterceptor(
+	ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	defer recoverOutOfGas(&err)
+	return handler(ctx, req)
+}
+
 func makeContext(ctx plugin.StaticContext, req *types.Request, apiServer uint32) *types.ContractCallRequest {
 	block := ctx.Block()
 	msg := ctx.Message()
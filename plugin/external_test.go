@@ -1,6 +1,18 @@
 package plugin
 
-import "testing"
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/loomnetwork/loomchain/vm"
+)
 
 func TestPluginSoLoader(t *testing.T) {
 	e := NewExternalLoader("")
@@ -19,3 +31,76 @@ func TestPluginSoLoader(t *testing.T) {
 	}
 
 }
+
+func TestExternalLoaderReloadPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	execPath := path.Join(dir, "test.1.0.0")
+	require.NoError(t, ioutil.WriteFile(execPath, []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, ioutil.WriteFile(path.Join(dir, "readme.txt"), []byte("not a plugin"), 0644))
+
+	l := NewExternalLoader(dir)
+	plugins, err := l.ListPlugins()
+	require.NoError(t, err)
+	require.Equal(t, []*ExternalPluginInfo{{Name: "test", Version: "1.0.0", File: "test.1.0.0"}}, plugins)
+
+	// Simulate the plugin being loaded without actually starting the process
+	file, err := os.Stat(execPath)
+	require.NoError(t, err)
+	client := &externalClient{Client: loadExternal(execPath), file: file}
+	l.clients["test:1.0.0"] = client
+	plugins, err = l.ListPlugins()
+	require.NoError(t, err)
+	require.True(t, plugins[0].Loaded)
+
+	unloaded, err := l.ReloadPlugins()
+	require.NoError(t, err)
+	require.Len(t, unloaded, 0)
+
+	// Plugins whose executables were replaced should be unloaded, but only once they're drained
+	require.True(t, client.acquire())
+	require.NoError(t, ioutil.WriteFile(execPath, []byte("#!/bin/sh\nexit 0\n"), 0755))
+	unloaded, err = l.ReloadPlugins()
+	require.NoError(t, err)
+	require.Equal(t, []string{"test:1.0.0"}, unloaded)
+	require.Nil(t, l.clients["test:1.0.0"])
+	require.False(t, client.acquire())
+	require.Equal(t, 1, client.active)
+	client.release()
+	require.Equal(t, 0, client.active)
+
+	plugins, err = l.ListPlugins()
+	require.NoError(t, err)
+	require.False(t, plugins[0].Loaded)
+}
+
+func TestExternalLoaderWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := NewExternalLoader(dir)
+	l.Watch(10*time.Millisecond, loom.NewLoomLogger("error", ""))
+	require.NotNil(t, l.stopWatching)
+
+	// Unloading the contracts should stop the watcher, and wait for it to exit
+	l.UnloadContracts()
+	require.Nil(t, l.stopWatching)
+	l.UnloadContracts()
+}
+
+func TestRecoverOutOfGasInterceptor(t *testing.T) {
+	meter := vm.NewGasMeter(100)
+	ctx := &contractContext{gasMeter: meter}
+	handler := func(_ context.Context, req interface{}) (interface{}, error) {
+		ctx.useGas(readGas)
+		return req, nil
+	}
+	// API calls that run out of gas must fail instead of crashing the node
+	resp, err := recoverOutOfGasInterceptor(context.Background(), "req", nil, handler)
+	require.Equal(t, vm.ErrOutOfGas, err)
+	require.Nil(t, resp)
+	require.True(t, meter.IsOutOfGas())
+}
@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"math"
+
+	lp "github.com/loomnetwork/go-loom/plugin"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/vm"
+)
+
+// Gas costs of the operations Go contracts can perform via their context. These costs are part of
+// consensus, changing any of them will change the outcome of previously executed txs.
+const (
+	readGas         = 200
+	readByteGas     = 3
+	writeGas        = 5000
+	writeByteGas    = 20
+	deleteGas       = 5000
+	rangeGas        = 200
+	rangeEntryGas   = 50
+	emitGas         = 375
+	emitByteGas     = 8
+	contractCallGas = 700
+
+	// maxRangeEntries is the max number of entries a metered Go contract can read via a single
+	// Range call. The store doesn't provide an iterator, so the whole range is read before the gas
+	// for each entry is charged, this cap bounds the amount of data a single call can pull in.
+	maxRangeEntries = 10000
+)
+
+// outOfGasPanic is raised when a contract runs out of gas, contract context methods don't return
+// errors so this is the only way to abort execution.
+type outOfGasPanic struct{}
+
+// recoverOutOfGas must be deferred, it converts the panic raised when a contract runs out of gas
+// into vm.ErrOutOfGas, any other panics are propagated.
+func recoverOutOfGas(err *error) {
+	if r := recover(); r != nil {
+		if _, ok := r.(outOfGasPanic); !ok {
+			panic(r)
+		}
+		*err = vm.ErrOutOfGas
+	}
+}
+
+func gasMeterFromState(state loomchain.State) *vm.GasMeter {
+	return vm.GasMeterFromContext(state.Context())
+}
+
+func (c *contractContext) useGas(amount uint64) {
+	if c.gasMeter == nil {
+		return
+	}
+	if err := c.gasMeter.ConsumeGas(amount); err != nil {
+		panic(outOfGasPanic{})
+	}
+}
+
+func (c *contractContext) Get(key []byte) []byte {
+	c.useGas(readGas + readByteGas*uint64(len(key)))
+	value := c.State.Get(key)
+	c.useGas(readByteGas * uint64(len(value)))
+	return value
+}
+
+func (c *contractContext) Has(key []byte) bool {
+	c.useGas(readGas + readByteGas*uint64(len(key)))
+	return c.State.Has(key)
+}
+
+func (c *contractContext) Range(prefix []byte) lp.RangeData {
+	c.useGas(rangeGas + readByteGas*uint64(len(prefix)))
+	entries := c.State.Range(prefix)
+	if c.gasMeter != nil && len(entries) > maxRangeEntries {
+		// Reading a range that's too large uses up all the remaining gas.
+		c.useGas(math.MaxUint64)
+	}
+	for _, entry := range entries {
+		c.useGas(rangeEntryGas + readByteGas*uint64(len(entry.Key)+len(entry.Value)))
+	}
+	return entries
+}
+
+func (c *contractContext) Set(key, value []byte) {
+	c.useGas(writeGas + writeByteGas*uint64(len(key)+len(value)))
+	c.State.Set(key, value)
+}
+
+func (c *contractContext) Delete(key []byte) {
+	c.useGas(deleteGas + writeByteGas*uint64(len(key)))
+	c.State.Delete(key)
+}
@@ -0,0 +1,136 @@
+// +build evm
+
+package plugin
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/loomnetwork/go-loom"
+	loom_plugin "github.com/loomnetwork/go-loom/plugin"
+	contract "github.com/loomnetwork/go-loom/plugin/contractpb"
+	"github.com/loomnetwork/go-loom/testdata"
+	"github.com/loomnetwork/loomchain"
+	registry "github.com/loomnetwork/loomchain/registry/factory"
+	"github.com/loomnetwork/loomchain/store"
+	lvm "github.com/loomnetwork/loomchain/vm"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+type GasTestContract struct {
+}
+
+func (c *GasTestContract) Meta() (loom_plugin.Meta, error) {
+	return loom_plugin.Meta{
+		Name:    "gastest",
+		Version: "0.0.1",
+	}, nil
+}
+
+func (c *GasTestContract) Init(ctx contract.Context, req *loom_plugin.Request) error {
+	return nil
+}
+
+// Write stores a single value.
+func (c *GasTestContract) Write(ctx contract.Context, args *testdata.CallArgs) error {
+	return ctx.Set([]byte("key"), args)
+}
+
+// WriteMany stores args.Value values under the "many/" prefix.
+func (c *GasTestContract) WriteMany(ctx contract.Context, args *testdata.CallArgs) error {
+	for i := 0; i < int(args.Value); i++ {
+		if err := ctx.Set([]byte("many/"+strconv.Itoa(i)), args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMany reads all the values stored by WriteMany.
+func (c *GasTestContract) ReadMany(ctx contract.Context, args *testdata.CallArgs) error {
+	ctx.Range([]byte("many/"))
+	return nil
+}
+
+// Fill keeps storing values until it runs out of gas.
+func (c *GasTestContract) Fill(ctx contract.Context, args *testdata.CallArgs) error {
+	for i := 0; ; i++ {
+		if err := ctx.Set([]byte(strconv.Itoa(i)), args); err != nil {
+			return err
+		}
+	}
+}
+
+func TestPluginVMGasMetering(t *testing.T) {
+	loader := NewStaticLoader(contract.MakePluginContract(&GasTestContract{}))
+	block := abci.Header{
+		ChainID: "chain",
+		Height:  int64(34),
+		Time:    time.Unix(123456789, 0),
+	}
+	state := loomchain.NewStoreState(context.Background(), store.NewMemStore(), block, nil, nil)
+	createRegistry, err := registry.NewRegistryFactory(registry.LatestRegistryVersion)
+	require.NoError(t, err)
+
+	vm := NewPluginVM(loader, state, createRegistry(state), &fakeEventHandler{}, nil, nil, nil, nil)
+	contractAddr, err := deployGoContract(vm, "gastest:0.0.1", 0, loom.RootAddress("chain"))
+	require.NoError(t, err)
+
+	// Calls aren't metered unless the state carries a gas meter
+	require.NoError(t, callGoContractMethod(vm, vmAddr1, contractAddr, "Write", &testdata.CallArgs{}))
+
+	meter := lvm.NewGasMeter(100000)
+	meteredState := state.WithContext(lvm.WithGasMeter(context.Background(), meter))
+	vm = NewPluginVM(loader, meteredState, createRegistry(meteredState), &fakeEventHandler{}, nil, nil, nil, nil)
+	require.NoError(t, callGoContractMethod(vm, vmAddr1, contractAddr, "Write", &testdata.CallArgs{}))
+	require.True(t, meter.GasUsed() >= writeGas)
+	require.False(t, meter.IsOutOfGas())
+
+	err = callGoContractMethod(vm, vmAddr1, contractAddr, "Fill", &testdata.CallArgs{})
+	require.Equal(t, lvm.ErrOutOfGas, err)
+	require.True(t, meter.IsOutOfGas())
+	require.Equal(t, meter.GasLimit(), meter.GasUsed())
+
+	// Once the meter runs out of gas any further calls should fail
+	err = callGoContractMethod(vm, vmAddr1, contractAddr, "Write", &testdata.CallArgs{})
+	require.Equal(t, lvm.ErrOutOfGas, err)
+}
+
+func TestPluginVMGasMeteringRange(t *testing.T) {
+	loader := NewStaticLoader(contract.MakePluginContract(&GasTestContract{}))
+	block := abci.Header{
+		ChainID: "chain",
+		Height:  int64(34),
+		Time:    time.Unix(123456789, 0),
+	}
+	state := loomchain.NewStoreState(context.Background(), store.NewMemStore(), block, nil, nil)
+	createRegistry, err := registry.NewRegistryFactory(registry.LatestRegistryVersion)
+	require.NoError(t, err)
+
+	vm := NewPluginVM(loader, state, createRegistry(state), &fakeEventHandler{}, nil, nil, nil, nil)
+	contractAddr, err := deployGoContract(vm, "gastest:0.0.1", 0, loom.RootAddress("chain"))
+	require.NoError(t, err)
+	require.NoError(t, callGoContractMethod(vm, vmAddr1, contractAddr, "WriteMany", &testdata.CallArgs{Value: 10}))
+
+	// Each entry read via Range is charged for
+	meter := lvm.NewGasMeter(10000000)
+	meteredState := state.WithContext(lvm.WithGasMeter(context.Background(), meter))
+	meteredVM := NewPluginVM(
+		loader, meteredState, createRegistry(meteredState), &fakeEventHandler{}, nil, nil, nil, nil,
+	)
+	require.NoError(t, callGoContractMethod(meteredVM, vmAddr1, contractAddr, "ReadMany", &testdata.CallArgs{}))
+	require.True(t, meter.GasUsed() >= rangeGas+10*rangeEntryGas)
+	require.False(t, meter.IsOutOfGas())
+
+	// Reading more than maxRangeEntries entries in one call uses up all the gas
+	require.NoError(t, callGoContractMethod(
+		vm, vmAddr1, contractAddr, "WriteMany", &testdata.CallArgs{Value: maxRangeEntries + 1},
+	))
+	err = callGoContractMethod(meteredVM, vmAddr1, contractAddr, "ReadMany", &testdata.CallArgs{})
+	require.Equal(t, lvm.ErrOutOfGas, err)
+	require.True(t, meter.IsOutOfGas())
+	require.Equal(t, meter.GasLimit(), meter.GasUsed())
+}
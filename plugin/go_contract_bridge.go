@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/loomnetwork/go-loom"
+	lp "github.com/loomnetwork/go-loom/plugin"
+	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain"
+	levm "github.com/loomnetwork/loomchain/evm"
+	"github.com/loomnetwork/loomchain/registry/factory"
+	"github.com/pkg/errors"
+)
+
+// NewGoContractCallerFactory returns a factory that creates the GoContractCaller used by the Go
+// contract bridge precompile to call Go contracts from EVM contracts.
+func NewGoContractCallerFactory(
+	loader Loader,
+	createRegistry factory.RegistryFactoryFunc,
+	newABMFactory NewAccountBalanceManagerFactoryFunc,
+	logger *loom.Logger,
+) levm.GoContractCallerFactoryFunc {
+	return func(state loomchain.State) levm.GoContractCaller {
+		return &goContractCaller{
+			loader:         loader,
+			state:          state,
+			createRegistry: createRegistry,
+			newABMFactory:  newABMFactory,
+			logger:         logger,
+		}
+	}
+}
+
+type goContractCaller struct {
+	loader         Loader
+	state          loomchain.State
+	createRegistry factory.RegistryFactoryFunc
+	newABMFactory  NewAccountBalanceManagerFactoryFunc
+	logger         *loom.Logger
+}
+
+var _ levm.GoContractCaller = &goContractCaller{}
+
+func (c *goContractCaller) CallGoContract(
+	caller loom.Address, contractName, method string, args []byte, readOnly bool,
+) ([]byte, []*types.EventData, error) {
+	reg := c.createRegistry(c.state)
+	contractAddr, err := reg.Resolve(contractName)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to resolve contract %s", contractName)
+	}
+
+	body, err := proto.Marshal(&lp.ContractMethodCall{
+		Method: method,
+		Args:   args,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	reqBytes, err := proto.Marshal(&Request{
+		ContentType: lp.EncodingType_PROTOBUF3,
+		Accept:      lp.EncodingType_PROTOBUF3,
+		Body:        body,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Events emitted by the Go contract are collected so they can be added to the EVM tx receipt
+	// instead of being emitted separately.
+	eventCollector := &eventCollector{}
+	vm := NewPluginVM(c.loader, c.state, reg, eventCollector, c.logger, c.newABMFactory, nil, nil)
+	var respBytes []byte
+	if readOnly {
+		respBytes, err = vm.StaticCall(caller, contractAddr, reqBytes)
+	} else {
+		respBytes, err = vm.Call(caller, contractAddr, reqBytes, loom.NewBigUIntFromInt(0))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := &Response{}
+	if err := proto.Unmarshal(respBytes, resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, eventCollector.events, nil
+}
+
+// eventCollector is an event handler that just keeps track of all the events posted to it.
+type eventCollector struct {
+	loomchain.EventHandler
+	events []*types.EventData
+}
+
+func (ec *eventCollector) Post(height uint64, e *types.EventData) error {
+	ec.events = append(ec.events, e)
+	return nil
+}
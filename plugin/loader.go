@@ -70,6 +70,36 @@ func (m *MultiLoader) UnloadContracts() {
 	}
 }
 
+// ListPlugins returns info about the plugins available to any of the reloadable loaders.
+func (m *MultiLoader) ListPlugins() ([]*ExternalPluginInfo, error) {
+	var plugins []*ExternalPluginInfo
+	for _, loader := range m.loaders {
+		if rl, ok := loader.(ReloadableLoader); ok {
+			p, err := rl.ListPlugins()
+			if err != nil {
+				return nil, err
+			}
+			plugins = append(plugins, p...)
+		}
+	}
+	return plugins, nil
+}
+
+// ReloadPlugins reloads the plugins of all the reloadable loaders.
+func (m *MultiLoader) ReloadPlugins() ([]string, error) {
+	var unloaded []string
+	for _, loader := range m.loaders {
+		if rl, ok := loader.(ReloadableLoader); ok {
+			names, err := rl.ReloadPlugins()
+			if err != nil {
+				return nil, err
+			}
+			unloaded = append(unloaded, names...)
+		}
+	}
+	return unloaded, nil
+}
+
 // ContractOverride specifies a contract that should be loaded instead of another contract.
 // The override kicks in at a particular block height, and remains in force from that height
 // onwards. An override can itself be overridden by another override with a higher block height.
@@ -84,6 +84,7 @@ func (vm *PluginVM) CreateContractContext(
 		readOnly:     readOnly,
 		req:          &Request{},
 		logger:       vm.logger,
+		gasMeter:     gasMeterFromState(vm.State),
 	}
 }
 
@@ -93,14 +94,20 @@ func (vm *PluginVM) run(
 	code,
 	input []byte,
 	readOnly bool,
-) ([]byte, error) {
+) (_ []byte, err error) {
+	defer recoverOutOfGas(&err)
+
 	var pluginCode PluginCode
-	err := proto.Unmarshal(code, &pluginCode)
+	err = proto.Unmarshal(code, &pluginCode)
 	if err != nil {
 		return nil, err
 	}
 
-	contract, err := vm.Loader.LoadContract(pluginCode.Name, vm.State.Block().Height)
+	contractName, err := vm.contractVersion(addr, pluginCode.Name)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := vm.Loader.LoadContract(contractName, vm.State.Block().Height)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +151,25 @@ func (vm *PluginVM) run(
 	return proto.Marshal(res)
 }
 
+// contractVersion returns the name:version of the Go contract that should be loaded for the given
+// address, which may differ from the deployed version if the contract has been upgraded via the
+// registry.
+func (vm *PluginVM) contractVersion(addr loom.Address, deployedName string) (string, error) {
+	reg, ok := vm.Registry.(registry.VersionedRegistry)
+	if !ok {
+		return deployedName, nil
+	}
+	version, err := reg.GoContractVersion(addr)
+	if err != nil || version == "" {
+		return deployedName, err
+	}
+	meta, err := ParseMeta(deployedName)
+	if err != nil {
+		return "", err
+	}
+	return meta.Name + ":" + version, nil
+}
+
 func CreateAddress(parent loom.Address, nonce uint64) loom.Address {
 	var nonceBuf bytes.Buffer
 	err := binary.Write(&nonceBuf, binary.BigEndian, nonce)
@@ -239,23 +265,29 @@ type contractContext struct {
 	pluginName   string
 	logger       *loom.Logger
 	req          *Request
+	// Only set if gas metering is enabled for the current tx.
+	gasMeter *vm.GasMeter
 }
 
 var _ lp.Context = &contractContext{}
 
 func (c *contractContext) Call(addr loom.Address, input []byte) ([]byte, error) {
+	c.useGas(contractCallGas)
 	return c.VM.Call(c.address, addr, input, loom.NewBigUIntFromInt(0))
 }
 
 func (c *contractContext) CallEVM(addr loom.Address, input []byte, value *loom.BigUInt) ([]byte, error) {
+	c.useGas(contractCallGas)
 	return c.VM.CallEVM(c.address, addr, input, value)
 }
 
 func (c *contractContext) StaticCall(addr loom.Address, input []byte) ([]byte, error) {
+	c.useGas(contractCallGas)
 	return c.VM.StaticCall(c.address, addr, input)
 }
 
 func (c *contractContext) StaticCallEVM(addr loom.Address, input []byte) ([]byte, error) {
+	c.useGas(contractCallGas)
 	return c.VM.StaticCallEVM(c.address, addr, input)
 }
 
@@ -318,6 +350,7 @@ func (c *contractContext) EmitTopics(event []byte, topics ...string) {
 	if c.readOnly {
 		return
 	}
+	c.useGas(emitGas + emitByteGas*uint64(len(event)))
 	data := types.EventData{
 		Topics:          topics,
 		Caller:          c.caller.MarshalPB(),
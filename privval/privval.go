@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	"github.com/loomnetwork/go-loom/auth"
+	"github.com/pkg/errors"
 	"github.com/tendermint/tendermint/types"
 
 	hsmpv "github.com/loomnetwork/loomchain/privval/hsm"
+	remotepv "github.com/loomnetwork/loomchain/privval/remote"
 )
 
 type PrivValidator interface {
@@ -16,27 +18,52 @@ type PrivValidator interface {
 }
 
 // generate priv validator while generating ed25519 keypair
-func GenPrivVal(filePath string, hsmConfig *hsmpv.HsmConfig) (PrivValidator, error) {
+func GenPrivVal(
+	filePath string, hsmConfig *hsmpv.HsmConfig, remoteSignerConfig *remotepv.RemoteSignerConfig,
+) (PrivValidator, error) {
+	if err := checkSignerConfig(hsmConfig, remoteSignerConfig); err != nil {
+		return nil, err
+	}
 	if hsmConfig.HsmEnabled {
 		return hsmpv.GenHsmPV(hsmConfig, filePath)
 	}
+	if remoteSignerConfig != nil && remoteSignerConfig.Enabled {
+		return remotepv.GenRemotePV(remoteSignerConfig, filePath)
+	}
 
 	return GenFilePV(filePath)
 }
 
 // load priv validator
-func LoadPrivVal(filePath string, hsmConfig *hsmpv.HsmConfig) (PrivValidator, error) {
+func LoadPrivVal(
+	filePath string, hsmConfig *hsmpv.HsmConfig, remoteSignerConfig *remotepv.RemoteSignerConfig,
+) (PrivValidator, error) {
+	if err := checkSignerConfig(hsmConfig, remoteSignerConfig); err != nil {
+		return nil, err
+	}
 	if hsmConfig.HsmEnabled {
 		return hsmpv.LoadHsmPV(hsmConfig, filePath)
 	}
+	if remoteSignerConfig != nil && remoteSignerConfig.Enabled {
+		return remotepv.LoadRemotePV(remoteSignerConfig, filePath)
+	}
 
 	return LoadFilePV(filePath)
 }
 
+func checkSignerConfig(hsmConfig *hsmpv.HsmConfig, remoteSignerConfig *remotepv.RemoteSignerConfig) error {
+	if hsmConfig.HsmEnabled && remoteSignerConfig != nil && remoteSignerConfig.Enabled {
+		return errors.New("HSM and remote signer can't be enabled at the same time")
+	}
+	return remoteSignerConfig.Validate()
+}
+
 func NewEd25519Signer(pv PrivValidator) auth.Signer {
 	switch v := pv.(type) {
 	case *hsmpv.YubiHsmPV:
 		return auth.NewSigner(auth.SignerTypeYubiHsm, v.PrivateKey)
+	case *remotepv.RemotePV:
+		return remotepv.NewSigner(v)
 	case *FilePV:
 		privKey := [64]byte(v.GetPrivKey())
 		return auth.NewSigner(auth.SignerTypeEd25519, privKey[:])
@@ -0,0 +1,52 @@
+package remotepv
+
+import (
+	"fmt"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// RemoteSignerConfig specifies how to connect to a remote signer, i.e. a separate process that holds
+// the validator private key and signs votes, proposals & arbitrary messages on behalf of the node.
+type RemoteSignerConfig struct {
+	// Enables the remote signer, can't be enabled at the same time as the HSM.
+	Enabled bool
+	// Unix socket the remote signer is listening on, e.g. unix:///var/run/loom-signer.sock.
+	// The connection to the signer isn't authenticated, access to the signer is controlled by the
+	// permissions of the socket file, so other transports are not supported.
+	Addr string
+	// Max number of seconds to wait for a connection to the remote signer to be established.
+	ConnTimeout int
+	// Max number of seconds to wait for the remote signer to respond to a request.
+	RequestTimeout int
+}
+
+// DefaultConfig creates new instance of RemoteSignerConfig with default config
+func DefaultConfig() *RemoteSignerConfig {
+	return &RemoteSignerConfig{
+		Enabled:        false,
+		Addr:           "unix://loom-signer.sock",
+		ConnTimeout:    10,
+		RequestTimeout: 5,
+	}
+}
+
+// Validate checks the config is valid.
+func (c *RemoteSignerConfig) Validate() error {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+	if protocol, _ := cmn.ProtocolAndAddress(c.Addr); protocol != "unix" {
+		return fmt.Errorf("remote signer address %s is not a unix socket", c.Addr)
+	}
+	return nil
+}
+
+// Clone returns a deep clone of the config.
+func (c *RemoteSignerConfig) Clone() *RemoteSignerConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
@@ -0,0 +1,149 @@
+package remotepv
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// MockSigner is a remote signer that holds the private key in memory, it doesn't implement any
+// double signing protection so it should only be used for testing.
+type MockSigner struct {
+	chainID string
+	privKey ed25519.PrivKeyEd25519
+	logger  log.Logger
+
+	listener net.Listener
+	wg       sync.WaitGroup
+	quit     chan struct{}
+}
+
+// NewMockSigner creates a new mock signer that will sign votes & proposals for the given chain.
+func NewMockSigner(chainID string, privKey ed25519.PrivKeyEd25519, logger log.Logger) *MockSigner {
+	return &MockSigner{
+		chainID: chainID,
+		privKey: privKey,
+		logger:  logger,
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start starts listening for connections on the given unix socket, e.g. unix:///tmp/signer.sock
+func (s *MockSigner) Start(addr string) error {
+	protocol, address := cmn.ProtocolAndAddress(addr)
+	if protocol != "unix" {
+		return errors.Errorf("%s is not a unix socket", addr)
+	}
+	if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove stale socket %s", address)
+	}
+	listener, err := net.Listen(protocol, address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", addr)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go s.acceptConnections()
+	return nil
+}
+
+// Addr returns the address the signer is listening on.
+func (s *MockSigner) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stop closes the listener & waits for all the open connections to be closed.
+func (s *MockSigner) Stop() {
+	close(s.quit)
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *MockSigner) acceptConnections() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.quit:
+			default:
+				s.logger.Error("Mock signer failed to accept connection", "err", err)
+			}
+			return
+		}
+		s.wg.Add(1)
+		go s.handleConnection(conn)
+	}
+}
+
+func (s *MockSigner) handleConnection(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	// unblock the read when the signer is stopped
+	go func() {
+		<-s.quit
+		conn.Close()
+	}()
+
+	for {
+		var req RemoteSignerMsg
+		if _, err := cdc.UnmarshalBinaryLengthPrefixedReader(conn, &req, maxMsgSize); err != nil {
+			if err != io.EOF {
+				select {
+				case <-s.quit:
+				default:
+					s.logger.Debug("Mock signer failed to read request", "err", err)
+				}
+			}
+			return
+		}
+		resp := s.handleRequest(req)
+		if resp == nil {
+			s.logger.Error("Mock signer received unsupported request", "request", req)
+			return
+		}
+		if _, err := cdc.MarshalBinaryLengthPrefixedWriter(conn, resp); err != nil {
+			s.logger.Error("Mock signer failed to write response", "err", err)
+			return
+		}
+	}
+}
+
+func (s *MockSigner) handleRequest(req RemoteSignerMsg) RemoteSignerMsg {
+	switch r := req.(type) {
+	case *PubKeyRequest:
+		return &PubKeyResponse{PubKey: s.privKey.PubKey()}
+	case *SignVoteRequest:
+		sig, err := s.privKey.Sign(r.Vote.SignBytes(s.chainID))
+		if err != nil {
+			return &SignedVoteResponse{Error: &RemoteSignerError{Description: err.Error()}}
+		}
+		r.Vote.Signature = sig
+		return &SignedVoteResponse{Vote: r.Vote}
+	case *SignProposalRequest:
+		sig, err := s.privKey.Sign(r.Proposal.SignBytes(s.chainID))
+		if err != nil {
+			return &SignedProposalResponse{Error: &RemoteSignerError{Description: err.Error()}}
+		}
+		r.Proposal.Signature = sig
+		return &SignedProposalResponse{Proposal: r.Proposal}
+	case *SignBytesRequest:
+		sig, err := s.privKey.Sign(r.Bytes)
+		if err != nil {
+			return &SignedBytesResponse{Error: &RemoteSignerError{Description: err.Error()}}
+		}
+		return &SignedBytesResponse{Signature: sig}
+	case *PingRequest:
+		return &PingResponse{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package remotepv
+
+import (
+	"fmt"
+
+	amino "github.com/tendermint/go-amino"
+	"github.com/tendermint/tendermint/crypto"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Max size of a single message exchanged with the remote signer.
+const maxMsgSize = 1024 * 1024
+
+var cdc = amino.NewCodec()
+
+// The vote, proposal & ping messages reuse the amino routes of the Tendermint privval messages, but
+// the protocol itself isn't the same, the node dials the signer (rather than the other way around),
+// so signers built for Tendermint can't be used as is.
+func init() {
+	cryptoAmino.RegisterAmino(cdc)
+	cdc.RegisterInterface((*RemoteSignerMsg)(nil), nil)
+	cdc.RegisterConcrete(&PubKeyRequest{}, "tendermint/remotesigner/PubKeyRequest", nil)
+	cdc.RegisterConcrete(&PubKeyResponse{}, "tendermint/remotesigner/PubKeyResponse", nil)
+	cdc.RegisterConcrete(&SignVoteRequest{}, "tendermint/remotesigner/SignVoteRequest", nil)
+	cdc.RegisterConcrete(&SignedVoteResponse{}, "tendermint/remotesigner/SignedVoteResponse", nil)
+	cdc.RegisterConcrete(&SignProposalRequest{}, "tendermint/remotesigner/SignProposalRequest", nil)
+	cdc.RegisterConcrete(&SignedProposalResponse{}, "tendermint/remotesigner/SignedProposalResponse", nil)
+	cdc.RegisterConcrete(&PingRequest{}, "tendermint/remotesigner/PingRequest", nil)
+	cdc.RegisterConcrete(&PingResponse{}, "tendermint/remotesigner/PingResponse", nil)
+	cdc.RegisterConcrete(&SignBytesRequest{}, "loom/remotesigner/SignBytesRequest", nil)
+	cdc.RegisterConcrete(&SignedBytesResponse{}, "loom/remotesigner/SignedBytesResponse", nil)
+}
+
+// RemoteSignerMsg is sent between the node & the remote signer.
+type RemoteSignerMsg interface{}
+
+// RemoteSignerError is returned by the remote signer when it fails to process a request.
+type RemoteSignerError struct {
+	Code        int
+	Description string
+}
+
+func (e *RemoteSignerError) Error() string {
+	return fmt.Sprintf("remote signer error %d: %s", e.Code, e.Description)
+}
+
+// PubKeyRequest requests the public key of the validator.
+type PubKeyRequest struct{}
+
+// PubKeyResponse is the response to PubKeyRequest.
+type PubKeyResponse struct {
+	PubKey crypto.PubKey
+	Error  *RemoteSignerError
+}
+
+// SignVoteRequest requests the given vote to be signed.
+type SignVoteRequest struct {
+	Vote *types.Vote
+}
+
+// SignedVoteResponse is the response to SignVoteRequest.
+type SignedVoteResponse struct {
+	Vote  *types.Vote
+	Error *RemoteSignerError
+}
+
+// SignProposalRequest requests the given proposal to be signed.
+type SignProposalRequest struct {
+	Proposal *types.Proposal
+}
+
+// SignedProposalResponse is the response to SignProposalRequest.
+type SignedProposalResponse struct {
+	Proposal *types.Proposal
+	Error    *RemoteSignerError
+}
+
+// PingRequest is used to check the connection to the remote signer is still alive.
+type PingRequest struct{}
+
+// PingResponse is the response to PingRequest.
+type PingResponse struct{}
+
+// SignBytesRequest requests the given message to be signed, it's used by the oracles & the
+// fnConsensus reactor.
+type SignBytesRequest struct {
+	Bytes []byte
+}
+
+// SignedBytesResponse is the response to SignBytesRequest.
+type SignedBytesResponse struct {
+	Signature []byte
+	Error     *RemoteSignerError
+}
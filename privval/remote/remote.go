@@ -0,0 +1,470 @@
+package remotepv
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
+)
+
+const (
+	//nolint:unused
+	stepNone      int8 = 0 // Used to distinguish the initial state
+	stepPropose   int8 = 1
+	stepPrevote   int8 = 2
+	stepPrecommit int8 = 3
+)
+
+func voteToStep(vote *types.Vote) int8 {
+	switch vote.Type {
+	case types.PrevoteType:
+		return stepPrevote
+	case types.PrecommitType:
+		return stepPrecommit
+	default:
+		cmn.PanicSanity("Unknown vote type")
+		return 0
+	}
+}
+
+// RemotePV implements priv validator that delegates signing to a remote signer. The private key
+// never leaves the remote signer, but the last signed height/round/step is tracked locally (and
+// persisted to the priv validator file) to prevent double signing.
+type RemotePV struct {
+	LastHeight int64 `json:"last_height"`
+	LastRound  int   `json:"last_round"`
+	LastStep   int8  `json:"last_step"`
+
+	LastSignature []byte       `json:"last_signature,omitempty"`
+	LastSignBytes cmn.HexBytes `json:"last_signbytes,omitempty"`
+
+	Address types.Address `json:"address"`
+	PubKey  crypto.PubKey `json:"pub_key"`
+
+	cfg      *RemoteSignerConfig
+	filePath string
+	mtx      sync.Mutex
+
+	connMtx sync.Mutex
+	conn    net.Conn
+}
+
+// GenRemotePV creates a new priv validator file for the key held by the remote signer.
+func GenRemotePV(cfg *RemoteSignerConfig, filePath string) (*RemotePV, error) {
+	pv := &RemotePV{
+		cfg:      cfg,
+		filePath: filePath,
+	}
+	pubKey, err := pv.fetchPubKey()
+	if err != nil {
+		return nil, err
+	}
+	pv.PubKey = pubKey
+	pv.Address = pubKey.Address()
+	return pv, nil
+}
+
+// LoadRemotePV loads the priv validator file, and checks the key held by the remote signer matches
+// the key in the file.
+func LoadRemotePV(cfg *RemoteSignerConfig, filePath string) (*RemotePV, error) {
+	pvJSONBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pv := &RemotePV{}
+	if err := cdc.UnmarshalJSON(pvJSONBytes, &pv); err != nil {
+		return nil, errors.Wrapf(err, "failed to load priv validator from %s", filePath)
+	}
+	pv.cfg = cfg
+	pv.filePath = filePath
+
+	pubKey, err := pv.fetchPubKey()
+	if err != nil {
+		return nil, err
+	}
+	if !pubKey.Equals(pv.PubKey) {
+		return nil, fmt.Errorf(
+			"remote signer key %v doesn't match priv validator key %v", pubKey.Address(), pv.Address,
+		)
+	}
+	return pv, nil
+}
+
+// Reset parameters with given height
+func (pv *RemotePV) Reset(height int64) {
+	pv.LastHeight = height
+	pv.LastRound = 0
+	pv.LastStep = 0
+}
+
+// Save remote priv validator to file
+func (pv *RemotePV) Save() {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+
+	pv.save()
+}
+
+func (pv *RemotePV) save() {
+	outFile := pv.filePath
+	if outFile == "" {
+		panic("Cannot save remote PrivValidator: filePath not set")
+	}
+
+	jsonBytes, err := cdc.MarshalJSONIndent(pv, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	err = cmn.WriteFileAtomic(outFile, jsonBytes, 0600)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Close closes the connection to the remote signer.
+func (pv *RemotePV) Close() error {
+	pv.connMtx.Lock()
+	defer pv.connMtx.Unlock()
+
+	if pv.conn == nil {
+		return nil
+	}
+	err := pv.conn.Close()
+	pv.conn = nil
+	return err
+}
+
+// GetPubKey gets public key
+func (pv *RemotePV) GetPubKey() crypto.PubKey {
+	return pv.PubKey
+}
+
+// GetAddress gets address of public key
+func (pv *RemotePV) GetAddress() types.Address {
+	return pv.PubKey.Address()
+}
+
+// SignVote signs vote
+func (pv *RemotePV) SignVote(chainID string, vote *types.Vote) error {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+
+	if err := pv.signVote(chainID, vote); err != nil {
+		return fmt.Errorf("Error signing vote: %v", err)
+	}
+	return nil
+}
+
+// SignProposal signs proposal
+func (pv *RemotePV) SignProposal(chainID string, proposal *types.Proposal) error {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+
+	if err := pv.signProposal(chainID, proposal); err != nil {
+		return fmt.Errorf("Error signing proposal: %v", err)
+	}
+	return nil
+}
+
+// Sign signs an arbitrary message, there's no double signing protection for these.
+func (pv *RemotePV) Sign(msg []byte) ([]byte, error) {
+	resp, err := pv.request(&SignBytesRequest{Bytes: msg})
+	if err != nil {
+		return nil, err
+	}
+	signed, ok := resp.(*SignedBytesResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response %T from remote signer", resp)
+	}
+	if signed.Error != nil {
+		return nil, signed.Error
+	}
+	if !pv.PubKey.VerifyBytes(msg, signed.Signature) {
+		return nil, errors.New("remote signer returned invalid signature")
+	}
+	return signed.Signature, nil
+}
+
+// Ping checks the remote signer is reachable.
+func (pv *RemotePV) Ping() error {
+	resp, err := pv.request(&PingRequest{})
+	if err != nil {
+		return err
+	}
+	if _, ok := resp.(*PingResponse); !ok {
+		return fmt.Errorf("unexpected response %T from remote signer", resp)
+	}
+	return nil
+}
+
+func (pv *RemotePV) fetchPubKey() (crypto.PubKey, error) {
+	resp, err := pv.request(&PubKeyRequest{})
+	if err != nil {
+		return nil, err
+	}
+	pubKeyResp, ok := resp.(*PubKeyResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response %T from remote signer", resp)
+	}
+	if pubKeyResp.Error != nil {
+		return nil, pubKeyResp.Error
+	}
+	if _, ok := pubKeyResp.PubKey.(ed25519.PubKeyEd25519); !ok {
+		return nil, fmt.Errorf("remote signer returned unsupported key type %T", pubKeyResp.PubKey)
+	}
+	return pubKeyResp.PubKey, nil
+}
+
+// request sends a request to the remote signer and waits for a response, if the request fails
+// the connection is re-established and the request is sent one more time.
+func (pv *RemotePV) request(req RemoteSignerMsg) (RemoteSignerMsg, error) {
+	pv.connMtx.Lock()
+	defer pv.connMtx.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if pv.conn == nil {
+			if pv.conn, err = dialSigner(pv.cfg); err != nil {
+				continue
+			}
+		}
+		var resp RemoteSignerMsg
+		if resp, err = exchange(pv.conn, req, time.Duration(pv.cfg.RequestTimeout)*time.Second); err == nil {
+			return resp, nil
+		}
+		pv.conn.Close()
+		pv.conn = nil
+	}
+	return nil, errors.Wrap(err, "remote signer request failed")
+}
+
+// returns error if HRS regression or no LastSignBytes. returns true if HRS is unchanged
+func (pv *RemotePV) checkHRS(height int64, round int, step int8) (bool, error) {
+	if pv.LastHeight > height {
+		return false, errors.New("Height regression")
+	}
+
+	if pv.LastHeight == height {
+		if pv.LastRound > round {
+			return false, errors.New("Round regression")
+		}
+
+		if pv.LastRound == round {
+			if pv.LastStep > step {
+				return false, errors.New("Step regression")
+			} else if pv.LastStep == step {
+				if pv.LastSignBytes != nil {
+					if pv.LastSignature == nil {
+						panic("pv: LastSignature is nil but LastSignBytes is not!")
+					}
+					return true, nil
+				}
+				return false, errors.New("No LastSignature found")
+			}
+		}
+	}
+	return false, nil
+}
+
+// Persist height/round/step and signature
+func (pv *RemotePV) saveSigned(height int64, round int, step int8,
+	signBytes []byte, sig []byte) {
+
+	pv.LastHeight = height
+	pv.LastRound = round
+	pv.LastStep = step
+	pv.LastSignature = sig
+	pv.LastSignBytes = signBytes
+	pv.save()
+}
+
+// signVote checks if the vote is good to sign and sets the vote signature.
+// It may need to set the timestamp as well if the vote is otherwise the same as
+// a previously signed vote (ie. we crashed after signing but before the vote hit the WAL).
+func (pv *RemotePV) signVote(chainID string, vote *types.Vote) error {
+	height, round, step := vote.Height, vote.Round, voteToStep(vote)
+	signBytes := vote.SignBytes(chainID)
+
+	sameHRS, err := pv.checkHRS(height, round, step)
+	if err != nil {
+		return err
+	}
+
+	// We might crash before writing to the wal,
+	// causing us to try to re-sign for the same HRS.
+	// If signbytes are the same, use the last signature.
+	// If they only differ by timestamp, use last timestamp and signature
+	// Otherwise, return error
+	if sameHRS {
+		if bytes.Equal(signBytes, pv.LastSignBytes) {
+			vote.Signature = pv.LastSignature
+		} else if timestamp, ok := checkVotesOnlyDifferByTimestamp(pv.LastSignBytes, signBytes); ok {
+			vote.Timestamp = timestamp
+			vote.Signature = pv.LastSignature
+		} else {
+			err = fmt.Errorf("Conflicting data")
+		}
+		return err
+	}
+
+	// It passed the checks. Sign the vote
+	resp, err := pv.request(&SignVoteRequest{Vote: vote})
+	if err != nil {
+		return err
+	}
+	signed, ok := resp.(*SignedVoteResponse)
+	if !ok {
+		return fmt.Errorf("unexpected response %T from remote signer", resp)
+	}
+	if signed.Error != nil {
+		return signed.Error
+	}
+	if signed.Vote == nil || !bytes.Equal(signed.Vote.SignBytes(chainID), signBytes) {
+		return errors.New("remote signer modified the vote")
+	}
+	sig := signed.Vote.Signature
+	if !pv.PubKey.VerifyBytes(signBytes, sig) {
+		return errors.New("remote signer returned invalid signature")
+	}
+	pv.saveSigned(height, round, step, signBytes, sig)
+	vote.Signature = sig
+	return nil
+}
+
+// signProposal checks if the proposal is good to sign and sets the proposal signature.
+// It may need to set the timestamp as well if the proposal is otherwise the same as
+// a previously signed proposal ie. we crashed after signing but before the proposal hit the WAL).
+func (pv *RemotePV) signProposal(chainID string, proposal *types.Proposal) error {
+	height, round, step := proposal.Height, proposal.Round, stepPropose
+	signBytes := proposal.SignBytes(chainID)
+
+	sameHRS, err := pv.checkHRS(height, round, step)
+	if err != nil {
+		return err
+	}
+
+	// We might crash before writing to the wal,
+	// causing us to try to re-sign for the same HRS.
+	// If signbytes are the same, use the last signature.
+	// If they only differ by timestamp, use last timestamp and signature
+	// Otherwise, return error
+	if sameHRS {
+		if bytes.Equal(signBytes, pv.LastSignBytes) {
+			proposal.Signature = pv.LastSignature
+		} else if timestamp, ok := checkProposalsOnlyDifferByTimestamp(pv.LastSignBytes, signBytes); ok {
+			proposal.Timestamp = timestamp
+			proposal.Signature = pv.LastSignature
+		} else {
+			err = fmt.Errorf("Conflicting data")
+		}
+		return err
+	}
+
+	// It passed the checks. Sign the proposal
+	resp, err := pv.request(&SignProposalRequest{Proposal: proposal})
+	if err != nil {
+		return err
+	}
+	signed, ok := resp.(*SignedProposalResponse)
+	if !ok {
+		return fmt.Errorf("unexpected response %T from remote signer", resp)
+	}
+	if signed.Error != nil {
+		return signed.Error
+	}
+	if signed.Proposal == nil || !bytes.Equal(signed.Proposal.SignBytes(chainID), signBytes) {
+		return errors.New("remote signer modified the proposal")
+	}
+	sig := signed.Proposal.Signature
+	if !pv.PubKey.VerifyBytes(signBytes, sig) {
+		return errors.New("remote signer returned invalid signature")
+	}
+	pv.saveSigned(height, round, step, signBytes, sig)
+	proposal.Signature = sig
+	return nil
+}
+
+// returns the timestamp from the lastSignBytes.
+// returns true if the only difference in the votes is their timestamp.
+func checkVotesOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.Time, bool) {
+	var lastVote, newVote types.CanonicalVote
+	if err := cdc.UnmarshalBinaryLengthPrefixed(lastSignBytes, &lastVote); err != nil {
+		panic(fmt.Sprintf("LastSignBytes cannot be unmarshalled into vote: %v", err))
+	}
+	if err := cdc.UnmarshalBinaryLengthPrefixed(newSignBytes, &newVote); err != nil {
+		panic(fmt.Sprintf("signBytes cannot be unmarshalled into vote: %v", err))
+	}
+
+	lastTime := lastVote.Timestamp
+	// set the times to the same value and check equality
+	now := tmtime.Now()
+	lastVote.Timestamp = now
+	newVote.Timestamp = now
+	lastVoteBytes, _ := cdc.MarshalJSON(lastVote)
+	newVoteBytes, _ := cdc.MarshalJSON(newVote)
+
+	return lastTime, bytes.Equal(newVoteBytes, lastVoteBytes)
+}
+
+// returns the timestamp from the lastSignBytes.
+// returns true if the only difference in the proposals is their timestamp
+func checkProposalsOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.Time, bool) {
+	var lastProposal, newProposal types.CanonicalProposal
+	if err := cdc.UnmarshalBinaryLengthPrefixed(lastSignBytes, &lastProposal); err != nil {
+		panic(fmt.Sprintf("LastSignBytes cannot be unmarshalled into proposal: %v", err))
+	}
+	if err := cdc.UnmarshalBinaryLengthPrefixed(newSignBytes, &newProposal); err != nil {
+		panic(fmt.Sprintf("signBytes cannot be unmarshalled into proposal: %v", err))
+	}
+
+	lastTime := lastProposal.Timestamp
+	// set the times to the same value and check equality
+	now := tmtime.Now()
+	lastProposal.Timestamp = now
+	newProposal.Timestamp = now
+	lastProposalBytes, _ := cdc.MarshalBinaryLengthPrefixed(lastProposal)
+	newProposalBytes, _ := cdc.MarshalBinaryLengthPrefixed(newProposal)
+
+	return lastTime, bytes.Equal(newProposalBytes, lastProposalBytes)
+}
+
+// dialSigner connects to the remote signer, only unix sockets are supported (see RemoteSignerConfig).
+func dialSigner(cfg *RemoteSignerConfig) (net.Conn, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	_, address := cmn.ProtocolAndAddress(cfg.Addr)
+	conn, err := net.DialTimeout("unix", address, time.Duration(cfg.ConnTimeout)*time.Second)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to remote signer at %s", cfg.Addr)
+	}
+	return conn, nil
+}
+
+// exchange writes a message to the given connection, and reads back the response.
+func exchange(conn net.Conn, req RemoteSignerMsg, timeout time.Duration) (RemoteSignerMsg, error) {
+	if timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := cdc.MarshalBinaryLengthPrefixedWriter(conn, req); err != nil {
+		return nil, err
+	}
+	var resp RemoteSignerMsg
+	if _, err := cdc.UnmarshalBinaryLengthPrefixedReader(conn, &resp, maxMsgSize); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,96 @@
+package remotepv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+const testChainID = "default"
+
+func newTestVote(height int64, round int, voteType types.SignedMsgType) *types.Vote {
+	return &types.Vote{
+		Height:    height,
+		Round:     round,
+		Type:      voteType,
+		Timestamp: time.Unix(1000, 0).UTC(),
+	}
+}
+
+func TestRemotePV(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "remotepv")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	privKey := ed25519.GenPrivKey()
+	signer := NewMockSigner(testChainID, privKey, log.NewNopLogger())
+	require.NoError(t, signer.Start("unix://"+filepath.Join(tmpDir, "signer.sock")))
+	defer func() { signer.Stop() }()
+
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	cfg.Addr = signer.Addr().Network() + "://" + signer.Addr().String()
+	pvFile := filepath.Join(tmpDir, "priv_validator.json")
+
+	pv, err := GenRemotePV(cfg, pvFile)
+	require.NoError(t, err)
+	require.Equal(t, privKey.PubKey(), pv.GetPubKey())
+	pv.Save()
+	require.NoError(t, pv.Ping())
+
+	vote := newTestVote(10, 0, types.PrevoteType)
+	require.NoError(t, pv.SignVote(testChainID, vote))
+	require.True(t, privKey.PubKey().VerifyBytes(vote.SignBytes(testChainID), vote.Signature))
+	require.NoError(t, pv.Close())
+
+	// the HRS should be persisted, so signing the same vote again after a restart must return the
+	// same signature, while signing a conflicting vote or an older vote must fail
+	pv, err = LoadRemotePV(cfg, pvFile)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), pv.LastHeight)
+
+	sameVote := newTestVote(10, 0, types.PrevoteType)
+	require.NoError(t, pv.SignVote(testChainID, sameVote))
+	require.Equal(t, vote.Signature, sameVote.Signature)
+
+	conflictingVote := newTestVote(10, 0, types.PrevoteType)
+	conflictingVote.BlockID = types.BlockID{Hash: []byte("block")}
+	require.Error(t, pv.SignVote(testChainID, conflictingVote))
+
+	require.Error(t, pv.SignVote(testChainID, newTestVote(9, 0, types.PrecommitType)))
+	require.NoError(t, pv.SignVote(testChainID, newTestVote(10, 0, types.PrecommitType)))
+
+	msg := []byte("oracle message")
+	pubKey := [ed25519.PubKeyEd25519Size]byte(privKey.PubKey().(ed25519.PubKeyEd25519))
+	require.Equal(t, pubKey[:], NewSigner(pv).PublicKey())
+	require.True(t, privKey.PubKey().VerifyBytes(msg, NewSigner(pv).Sign(msg)))
+	require.NoError(t, pv.Close())
+
+	// a signer holding a different key must be rejected
+	signer.Stop()
+	signer = NewMockSigner(testChainID, ed25519.GenPrivKey(), log.NewNopLogger())
+	require.NoError(t, signer.Start(cfg.Addr))
+	_, err = LoadRemotePV(cfg, pvFile)
+	require.Error(t, err)
+}
+
+func TestRemotePVRequiresUnixSocket(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	require.NoError(t, cfg.Validate())
+
+	cfg.Addr = "tcp://127.0.0.1:26659"
+	require.Error(t, cfg.Validate())
+	_, err := GenRemotePV(cfg, "")
+	require.Error(t, err)
+
+	signer := NewMockSigner(testChainID, ed25519.GenPrivKey(), log.NewNopLogger())
+	require.Error(t, signer.Start(cfg.Addr))
+}
@@ -0,0 +1,32 @@
+package remotepv
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+// Signer implements the go-loom auth.Signer interface, messages are signed by the remote signer.
+type Signer struct {
+	pv *RemotePV
+}
+
+// NewSigner creates a Signer that signs messages with the key held by the remote signer.
+func NewSigner(pv *RemotePV) *Signer {
+	return &Signer{pv: pv}
+}
+
+// Sign signs the given message, panics if the remote signer fails to sign the message since the
+// auth.Signer interface doesn't allow errors to be returned.
+func (s *Signer) Sign(msg []byte) []byte {
+	sig, err := s.pv.Sign(msg)
+	if err != nil {
+		panic(errors.Wrap(err, "remote signer failed to sign message"))
+	}
+	return sig
+}
+
+// PublicKey returns the public key of the remote signer.
+func (s *Signer) PublicKey() []byte {
+	pubKey := [ed25519.PubKeyEd25519Size]byte(s.pv.PubKey.(ed25519.PubKeyEd25519))
+	return pubKey[:]
+}
@@ -0,0 +1,51 @@
+package loomchain
+
+import (
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// ProvableState is a read-only snapshot of the app state at a specific height that keeps track of
+// all the keys read from it, so that Merkle proofs can be generated for them after the fact.
+type ProvableState struct {
+	*StoreStateSnapshot
+	recorder *store.ReadRecorder
+	prover   store.StateProver
+	height   int64
+}
+
+// ProvableStateAt returns a snapshot of the app state as it was at the end of the block at the given
+// height, the proofs generated from the snapshot can be verified against the app hash in the header
+// of the next block.
+func (a *Application) ProvableStateAt(height int64, header abci.Header) (*ProvableState, error) {
+	prover, ok := a.Store.(store.StateProver)
+	if !ok {
+		return nil, errors.New("app store doesn't support state proofs")
+	}
+	hs, ok := a.Store.(store.HistoricalSnapshotter)
+	if !ok {
+		return nil, errors.New("app store doesn't retain historical state")
+	}
+	snap, err := hs.GetSnapshotAt(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "state at height %d is not available", height)
+	}
+	recorder := store.NewReadRecorder(snap)
+	return &ProvableState{
+		StoreStateSnapshot: NewStoreStateSnapshot(nil, recorder, header, nil, a.GetValidatorSet),
+		recorder:           recorder,
+		prover:             prover,
+		height:             height,
+	}, nil
+}
+
+// Height returns the height of the block the state snapshot was taken at.
+func (s *ProvableState) Height() int64 {
+	return s.height
+}
+
+// Prove generates proofs for all the keys that have been read from the state so far.
+func (s *ProvableState) Prove() ([]*store.KeyProof, error) {
+	return s.recorder.Prove(s.prover, s.height)
+}
@@ -3,8 +3,11 @@ package common
 import (
 	"encoding/binary"
 
+	ecommon "github.com/ethereum/go-ethereum/common"
+	etypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/loomnetwork/go-loom/plugin/types"
 	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/eth/bloom"
 	"github.com/pkg/errors"
 )
 
@@ -32,3 +35,30 @@ func ConvertEventData(events []*loomchain.EventData) []*types.EventData {
 	}
 	return typesEvents
 }
+
+// ReceiptsRoot computes the root of the Merkle-Patricia trie of the given receipts in the same way
+// Ethereum computes the receipts root of a block.
+func ReceiptsRoot(receipts []*types.EvmTxReceipt) []byte {
+	ethReceipts := make(etypes.Receipts, 0, len(receipts))
+	for _, receipt := range receipts {
+		logs := make([]*etypes.Log, 0, len(receipt.Logs))
+		for _, event := range receipt.Logs {
+			var addr ecommon.Address
+			if event.Address != nil {
+				addr = ecommon.BytesToAddress(event.Address.Local)
+			}
+			topics := make([]ecommon.Hash, 0, len(event.Topics))
+			for _, topic := range event.Topics {
+				topics = append(topics, ecommon.BytesToHash(bloom.TopicBytes(topic)))
+			}
+			logs = append(logs, &etypes.Log{Address: addr, Topics: topics, Data: event.EncodedBody})
+		}
+		ethReceipts = append(ethReceipts, &etypes.Receipt{
+			Status:            uint64(receipt.Status),
+			CumulativeGasUsed: uint64(receipt.CumulativeGasUsed),
+			Bloom:             etypes.BytesToBloom(bloom.GenEthBloom(receipt.Logs)),
+			Logs:              logs,
+		})
+	}
+	return etypes.DeriveSha(ethReceipts).Bytes()
+}
@@ -136,10 +136,26 @@ func (r *ReceiptHandler) CommitBlock(height int64) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	receipts := make([]*types.EvmTxReceipt, 0, len(r.receiptsCache))
+	for _, receipt := range r.receiptsCache {
+		// LevelDbReceipts.CommitBlock skips these too
+		if receipt != nil && len(receipt.TxHash) > 0 {
+			receipts = append(receipts, receipt)
+		}
+	}
 	err := r.leveldbReceipts.CommitBlock(r.receiptsCache, uint64(height))
 	r.txHashList = [][]byte{}
 	r.receiptsCache = []*types.EvmTxReceipt{}
-	return err
+	if err != nil {
+		return err
+	}
+	if err := r.evmAuxStore.SetReceiptsRoot(common.ReceiptsRoot(receipts), uint64(height)); err != nil {
+		return errors.Wrap(err, "failed to store receipts root")
+	}
+	if err := r.evmAuxStore.UpdateBloomBits(uint64(height)); err != nil {
+		return errors.Wrap(err, "failed to update bloom-bits index")
+	}
+	return nil
 }
 
 // TODO: this doesn't need the entire state passed in, just the block header
@@ -166,9 +182,11 @@ func (r *ReceiptHandler) CacheReceipt(
 	} else {
 		status = common.StatusTxFail
 	}
+	// the receipt must record the nonce in the lane the tx was sent on
+	nonce := auth.NonceOnLane(state, caller, auth.NonceLane(state.Context()))
 	receipt, err := leveldb.WriteReceipt(
 		state.Block(), caller, addr, events, status, r.eventHandler,
-		int32(len(r.receiptsCache)), int64(auth.Nonce(state, caller)), txHash,
+		int32(len(r.receiptsCache)), int64(nonce), txHash,
 	)
 	if err != nil {
 		return []byte{}, errors.Wrap(err, "receipt not written, returning empty hash")
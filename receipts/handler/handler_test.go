@@ -96,6 +96,7 @@ func TestReceiptsHandlerChain(t *testing.T) {
 	pendingHashList = reader.GetPendingTxHashList()
 	require.EqualValues(t, 0, len(pendingHashList))
 
+	var committedReceipts []*types.EvmTxReceipt
 	for index, txHash := range txHashList {
 		txReceipt, err := reader.GetReceipt(txHash)
 		require.NoError(t, err)
@@ -103,8 +104,14 @@ func TestReceiptsHandlerChain(t *testing.T) {
 		require.EqualValues(t, index*2+1, txReceipt.Nonce)
 		require.EqualValues(t, 2*index, txReceipt.TransactionIndex)
 		require.EqualValues(t, common.StatusTxSuccess, txReceipt.Status)
+		committedReceipts = append(committedReceipts, &txReceipt)
 	}
 
+	receiptsRoot, err := evmAuxStore.GetReceiptsRoot(height)
+	require.NoError(t, err)
+	require.Equal(t, common.ReceiptsRoot(committedReceipts), receiptsRoot)
+	require.NotEqual(t, common.ReceiptsRoot(nil), receiptsRoot)
+
 	require.NoError(t, receiptHandler.Close())
 	require.NoError(t, receiptHandler.ClearData())
 }
@@ -225,6 +225,9 @@ func (lr *LevelDbReceipts) CommitBlock(receipts []*types.EvmTxReceipt, height ui
 	if err := lr.evmAuxStore.SetBloomFilter(lr.tran, filter, height); err != nil {
 		return errors.Wrap(err, "set bloom filter")
 	}
+	if err := lr.evmAuxStore.SetBlockBloom(lr.tran, bloom.GenEthBloom(events), height); err != nil {
+		return errors.Wrap(err, "set block bloom")
+	}
 
 	if err := lr.tran.Commit(); err != nil {
 		return errors.Wrap(err, "committing level db transaction")
@@ -233,6 +236,72 @@ func (lr *LevelDbReceipts) CommitBlock(receipts []*types.EvmTxReceipt, height ui
 	return nil
 }
 
+// BackfillBlockBlooms regenerates the Ethereum-style blooms of the blocks in the given height range
+// from the stored receipts, and then indexes any sections of the bloom-bits index that can be indexed.
+// The range must overlap, or be adjacent to, the range of blocks whose blooms are already stored.
+func (lr *LevelDbReceipts) BackfillBlockBlooms(from, to uint64, progress func(height uint64)) error {
+	if from == 0 || to < from {
+		return errors.Errorf("invalid block range %d - %d", from, to)
+	}
+	bloomsFrom, ok, err := lr.evmAuxStore.BlockBloomsFrom()
+	if err != nil {
+		return err
+	}
+	if ok && to+1 < bloomsFrom {
+		return errors.Errorf("block range must extend to at least height %d", bloomsFrom-1)
+	}
+
+	db := lr.evmAuxStore.DB()
+	for height := from; height <= to; {
+		tran, err := db.OpenTransaction()
+		if err != nil {
+			return errors.Wrap(err, "opening leveldb transaction")
+		}
+		// write the blooms in batches to avoid creating huge transactions
+		for end := height + evmaux.BloomBitsSectionSize; height <= to && height < end; height++ {
+			bloom, err := lr.genBlockBloom(height)
+			if err == nil {
+				err = lr.evmAuxStore.SetBlockBloom(tran, bloom, height)
+			}
+			if err != nil {
+				tran.Discard()
+				return err
+			}
+		}
+		if err := tran.Commit(); err != nil {
+			return errors.Wrap(err, "committing level db transaction")
+		}
+		if progress != nil {
+			progress(height - 1)
+		}
+	}
+
+	if !ok || from < bloomsFrom {
+		if err := lr.evmAuxStore.SetBlockBloomsFrom(from); err != nil {
+			return err
+		}
+	}
+	return lr.evmAuxStore.IndexBloomBits(to)
+}
+
+// genBlockBloom generates the Ethereum-style bloom of the block at the given height from the receipts
+// of the EVM txs in the block.
+func (lr *LevelDbReceipts) genBlockBloom(height uint64) ([]byte, error) {
+	txHashList, err := lr.evmAuxStore.GetTxHashList(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load tx hashes at height %d", height)
+	}
+	var events []*types.EventData
+	for _, txHash := range txHashList {
+		receipt, err := lr.GetReceipt(txHash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "receipt for tx %X at height %d is not available", txHash, height)
+		}
+		events = append(events, receipt.Logs...)
+	}
+	return bloom.GenEthBloom(events), nil
+}
+
 func (lr *LevelDbReceipts) ClearData() {
 	lr.evmAuxStore.ClearData()
 }
@@ -109,7 +109,8 @@ func confirmDbConsistency(t *testing.T, handler *LevelDbReceipts,
 		require.EqualValues(t, receipts[i].BlockNumber, getDBReceipt.BlockNumber)
 		require.EqualValues(t, 0, bytes.Compare(receipts[i].TxHash, getDBReceipt.TxHash))
 	}
-	metadataCount := uint64(commit * 2)
+	// tx hash list, bloom filter, and block bloom per block
+	metadataCount := uint64(commit * 3)
 
 	dbActualSize, err := countDbEntries(handler.evmAuxStore)
 	require.NoError(t, err)
@@ -5,6 +5,7 @@ import (
 	common "github.com/loomnetwork/loomchain/registry"
 	registry_v1 "github.com/loomnetwork/loomchain/registry/v1"
 	registry_v2 "github.com/loomnetwork/loomchain/registry/v2"
+	registry_v3 "github.com/loomnetwork/loomchain/registry/v3"
 )
 
 type RegistryVersion int32
@@ -12,7 +13,8 @@ type RegistryVersion int32
 const (
 	RegistryV1            RegistryVersion = 1
 	RegistryV2            RegistryVersion = 2
-	LatestRegistryVersion RegistryVersion = RegistryV2
+	RegistryV3            RegistryVersion = 3
+	LatestRegistryVersion RegistryVersion = RegistryV3
 )
 
 // RegistryVersionFromInt safely converts an int to RegistryVersion.
@@ -40,6 +42,10 @@ func NewRegistryFactory(v RegistryVersion) (RegistryFactoryFunc, error) {
 		return func(s loomchain.State) common.Registry {
 			return &registry_v2.StateRegistry{State: s}
 		}, nil
+	case RegistryV3:
+		return func(s loomchain.State) common.Registry {
+			return &registry_v3.StateRegistry{State: s}
+		}, nil
 	}
 	return nil, common.ErrInvalidVersion
 }
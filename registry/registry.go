@@ -6,11 +6,16 @@ import (
 	"github.com/loomnetwork/go-loom"
 )
 
+// RegistryTxID is the ID of the txs handled by the RegistryTx handler.
+const RegistryTxID = 5
+
 var (
 	ErrAlreadyRegistered = errors.New("name is already registered")
 	ErrNotFound          = errors.New("name is not registered")
 	ErrInvalidVersion    = errors.New("invalid registry version")
 	ErrNotImplemented    = errors.New("not implemented in this registry version")
+	ErrNotOwner          = errors.New("caller is not the owner of the name")
+	ErrInvalidHeight     = errors.New("upgrade height must not precede the current or any previously scheduled version")
 )
 
 // Registry stores contract meta data.
@@ -24,3 +29,20 @@ type Registry interface {
 	// GetRecord looks up the meta data previously stored for the given contract
 	GetRecord(contractAddr loom.Address) (*Record, error)
 }
+
+// VersionedRegistry is a registry that keeps track of the version history of named contracts, and
+// allows the owner of a name to upgrade the contract the name points to, or transfer ownership of
+// the name to someone else.
+type VersionedRegistry interface {
+	Registry
+	// ScheduleUpgrade points the given name at a new contract address and/or Go contract version,
+	// starting at the height specified in the version.
+	ScheduleUpgrade(contractName string, version *ContractVersion, caller loom.Address) error
+	// TransferOwnership transfers ownership of the given name to a new owner.
+	TransferOwnership(contractName string, newOwner, caller loom.Address) error
+	// GetNameRecord looks up the owner & version history of the given name.
+	GetNameRecord(contractName string) (*NameRecord, error)
+	// GoContractVersion returns the version of the Go contract that should currently be loaded for
+	// the given address, or an empty string if the deployed version hasn't been upgraded.
+	GoContractVersion(contractAddr loom.Address) (string, error)
+}
@@ -32,7 +32,7 @@ func (m *Record) Reset()         { *m = Record{} }
 func (m *Record) String() string { return proto.CompactTextString(m) }
 func (*Record) ProtoMessage()    {}
 func (*Record) Descriptor() ([]byte, []int) {
-	return fileDescriptor_registry_8f33b09e6c22f9ce, []int{0}
+	return fileDescriptor_registry_ac0db229e128fe21, []int{0}
 }
 func (m *Record) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Record.Unmarshal(m, b)
@@ -73,25 +73,197 @@ func (m *Record) GetOwner() *types.Address {
 	return nil
 }
 
+type ContractVersion struct {
+	Address              *types.Address `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	GoVersion            string         `protobuf:"bytes,2,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	Height               int64          `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ContractVersion) Reset()         { *m = ContractVersion{} }
+func (m *ContractVersion) String() string { return proto.CompactTextString(m) }
+func (*ContractVersion) ProtoMessage()    {}
+func (*ContractVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_registry_ac0db229e128fe21, []int{1}
+}
+func (m *ContractVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractVersion.Unmarshal(m, b)
+}
+func (m *ContractVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractVersion.Marshal(b, m, deterministic)
+}
+func (dst *ContractVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractVersion.Merge(dst, src)
+}
+func (m *ContractVersion) XXX_Size() int {
+	return xxx_messageInfo_ContractVersion.Size(m)
+}
+func (m *ContractVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractVersion proto.InternalMessageInfo
+
+func (m *ContractVersion) GetAddress() *types.Address {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *ContractVersion) GetGoVersion() string {
+	if m != nil {
+		return m.GoVersion
+	}
+	return ""
+}
+
+func (m *ContractVersion) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type NameRecord struct {
+	Name                 string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Owner                *types.Address     `protobuf:"bytes,2,opt,name=owner" json:"owner,omitempty"`
+	Versions             []*ContractVersion `protobuf:"bytes,3,rep,name=versions" json:"versions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *NameRecord) Reset()         { *m = NameRecord{} }
+func (m *NameRecord) String() string { return proto.CompactTextString(m) }
+func (*NameRecord) ProtoMessage()    {}
+func (*NameRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_registry_ac0db229e128fe21, []int{2}
+}
+func (m *NameRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NameRecord.Unmarshal(m, b)
+}
+func (m *NameRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NameRecord.Marshal(b, m, deterministic)
+}
+func (dst *NameRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NameRecord.Merge(dst, src)
+}
+func (m *NameRecord) XXX_Size() int {
+	return xxx_messageInfo_NameRecord.Size(m)
+}
+func (m *NameRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_NameRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NameRecord proto.InternalMessageInfo
+
+func (m *NameRecord) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NameRecord) GetOwner() *types.Address {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *NameRecord) GetVersions() []*ContractVersion {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+type RegistryTx struct {
+	Name                 string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Upgrade              *ContractVersion `protobuf:"bytes,2,opt,name=upgrade" json:"upgrade,omitempty"`
+	NewOwner             *types.Address   `protobuf:"bytes,3,opt,name=new_owner,json=newOwner" json:"new_owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RegistryTx) Reset()         { *m = RegistryTx{} }
+func (m *RegistryTx) String() string { return proto.CompactTextString(m) }
+func (*RegistryTx) ProtoMessage()    {}
+func (*RegistryTx) Descriptor() ([]byte, []int) {
+	return fileDescriptor_registry_ac0db229e128fe21, []int{3}
+}
+func (m *RegistryTx) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegistryTx.Unmarshal(m, b)
+}
+func (m *RegistryTx) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegistryTx.Marshal(b, m, deterministic)
+}
+func (dst *RegistryTx) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegistryTx.Merge(dst, src)
+}
+func (m *RegistryTx) XXX_Size() int {
+	return xxx_messageInfo_RegistryTx.Size(m)
+}
+func (m *RegistryTx) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegistryTx.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegistryTx proto.InternalMessageInfo
+
+func (m *RegistryTx) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RegistryTx) GetUpgrade() *ContractVersion {
+	if m != nil {
+		return m.Upgrade
+	}
+	return nil
+}
+
+func (m *RegistryTx) GetNewOwner() *types.Address {
+	if m != nil {
+		return m.NewOwner
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Record)(nil), "Record")
+	proto.RegisterType((*ContractVersion)(nil), "ContractVersion")
+	proto.RegisterType((*NameRecord)(nil), "NameRecord")
+	proto.RegisterType((*RegistryTx)(nil), "RegistryTx")
 }
 
 func init() {
-	proto.RegisterFile("github.com/loomnetwork/loomchain/registry/registry.proto", fileDescriptor_registry_8f33b09e6c22f9ce)
-}
-
-var fileDescriptor_registry_8f33b09e6c22f9ce = []byte{
-	// 163 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x48, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0xc9, 0xcf, 0xcf, 0xcd, 0x4b, 0x2d, 0x29, 0xcf,
-	0x2f, 0xca, 0x06, 0xb3, 0x93, 0x33, 0x12, 0x33, 0xf3, 0xf4, 0x8b, 0x52, 0xd3, 0x33, 0x8b, 0x4b,
-	0x8a, 0x2a, 0xe1, 0x0c, 0xbd, 0x82, 0xa2, 0xfc, 0x92, 0x7c, 0x29, 0x03, 0x1c, 0x3a, 0xd3, 0xf3,
-	0x75, 0x41, 0x5c, 0xfd, 0x92, 0xca, 0x82, 0xd4, 0x62, 0x08, 0x09, 0xd1, 0xa1, 0x94, 0xc0, 0xc5,
-	0x16, 0x94, 0x9a, 0x9c, 0x5f, 0x94, 0x22, 0x24, 0xc4, 0xc5, 0x92, 0x97, 0x98, 0x9b, 0x2a, 0xc1,
-	0xa8, 0xc0, 0xa8, 0xc1, 0x19, 0x04, 0x66, 0x0b, 0x29, 0x71, 0xb1, 0x27, 0xa6, 0xa4, 0x14, 0xa5,
-	0x16, 0x17, 0x4b, 0x30, 0x29, 0x30, 0x6a, 0x70, 0x1b, 0x71, 0xe8, 0x39, 0x42, 0xf8, 0x41, 0x30,
-	0x09, 0x21, 0x39, 0x2e, 0xd6, 0xfc, 0xf2, 0xbc, 0xd4, 0x22, 0x09, 0x66, 0x34, 0x15, 0x10, 0xe1,
-	0x24, 0x36, 0xb0, 0x45, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x38, 0x51, 0x62, 0x0c, 0xd6,
-	0x00, 0x00, 0x00,
+	proto.RegisterFile("github.com/loomnetwork/loomchain/registry/registry.proto", fileDescriptor_registry_ac0db229e128fe21)
+}
+
+var fileDescriptor_registry_ac0db229e128fe21 = []byte{
+	// 286 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x51, 0x4d, 0x4b, 0xf3, 0x40,
+	0x10, 0x26, 0xcd, 0xfb, 0xb6, 0xcd, 0xf4, 0xa0, 0xec, 0x41, 0x82, 0xa0, 0x84, 0x80, 0x10, 0x44,
+	0x13, 0xa9, 0x17, 0xaf, 0xe2, 0x5d, 0x61, 0x11, 0xaf, 0x75, 0x9b, 0x0c, 0x9b, 0x60, 0xb3, 0x13,
+	0x76, 0xb7, 0xc6, 0xfe, 0x7b, 0x69, 0xbe, 0x90, 0x9a, 0x7a, 0x09, 0xf3, 0x64, 0x9e, 0x8f, 0x99,
+	0x1d, 0x78, 0x90, 0x85, 0xcd, 0xb7, 0xeb, 0x38, 0xa5, 0x32, 0xd9, 0x10, 0x95, 0x0a, 0x6d, 0x4d,
+	0xfa, 0xa3, 0xa9, 0xd3, 0x5c, 0x14, 0x2a, 0xd1, 0x28, 0x0b, 0x63, 0xf5, 0x6e, 0x28, 0xe2, 0x4a,
+	0x93, 0xa5, 0xf3, 0xbb, 0x23, 0x4a, 0x49, 0xb7, 0x7b, 0x98, 0xd8, 0x5d, 0x85, 0xa6, 0xfd, 0xb6,
+	0x8a, 0xf0, 0x1d, 0xa6, 0x1c, 0x53, 0xd2, 0x19, 0x63, 0xf0, 0x4f, 0x89, 0x12, 0x7d, 0x27, 0x70,
+	0x22, 0x8f, 0x37, 0x35, 0x0b, 0x61, 0x26, 0xb2, 0x4c, 0xa3, 0x31, 0xfe, 0x24, 0x70, 0xa2, 0xc5,
+	0x72, 0x1e, 0x3f, 0xb6, 0x98, 0xf7, 0x0d, 0x76, 0x09, 0xff, 0xa9, 0x56, 0xa8, 0x7d, 0xf7, 0x80,
+	0xd1, 0xfe, 0x0e, 0x37, 0x70, 0xf2, 0x44, 0xca, 0x6a, 0x91, 0xda, 0x37, 0xd4, 0xa6, 0x20, 0xf5,
+	0xd3, 0xd6, 0x39, 0x66, 0x7b, 0x01, 0x20, 0x69, 0xf5, 0xd9, 0x2a, 0x9a, 0x74, 0x8f, 0x7b, 0x92,
+	0x7a, 0x8b, 0x33, 0x98, 0xe6, 0x58, 0xc8, 0xdc, 0x36, 0xb1, 0x2e, 0xef, 0x50, 0xa8, 0x00, 0x9e,
+	0x45, 0x89, 0x7f, 0xec, 0x34, 0xcc, 0x3b, 0x19, 0x9d, 0x97, 0xdd, 0xc0, 0xbc, 0x4b, 0x35, 0xbe,
+	0x1b, 0xb8, 0xd1, 0x62, 0x79, 0x1a, 0x1f, 0x2c, 0xc0, 0x07, 0x46, 0x68, 0x00, 0x78, 0x77, 0x83,
+	0xd7, 0xaf, 0xd1, 0xbc, 0x6b, 0x98, 0x6d, 0x2b, 0xa9, 0x45, 0x86, 0x5d, 0xe2, 0x6f, 0xbb, 0x9e,
+	0xc0, 0xae, 0xc0, 0x53, 0x58, 0xaf, 0xc6, 0xdf, 0x73, 0xae, 0xb0, 0x7e, 0xd9, 0x77, 0xd6, 0xd3,
+	0xe6, 0x76, 0xf7, 0xdf, 0x03, 0x00, 0x33, 0xa0, 0xb9, 0x41, 0x29, 0x02, 0x00, 0x00,
 }
@@ -33,6 +33,12 @@ func contractRecordKey(contractAddr loom.Address) []byte {
 	return util.PrefixKey(contractRecordKeyPrefix, contractAddr.Bytes())
 }
 
+// ContractRecordKey returns the key the record of the given contract is stored under, the v3 registry
+// stores contract records under the same keys.
+func ContractRecordKey(contractAddr loom.Address) []byte {
+	return contractRecordKey(contractAddr)
+}
+
 // StateRegistry stores contract meta data for named & unnamed contracts, and allows lookup by
 // contract name or contract address.
 type StateRegistry struct {
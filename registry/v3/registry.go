@@ -0,0 +1,242 @@
+package registry
+
+import (
+	"errors"
+	"regexp"
+
+	proto "github.com/gogo/protobuf/proto"
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
+	common "github.com/loomnetwork/loomchain/registry"
+	registry_v2 "github.com/loomnetwork/loomchain/registry/v2"
+)
+
+var (
+	validVersionRE = regexp.MustCompile("^[a-zA-Z0-9\\.\\-]+$")
+
+	// Store Keys
+	nameRecordKeyPrefix = []byte("reg_nrec")
+)
+
+func nameRecordKey(contractName string) []byte {
+	return util.PrefixKey(nameRecordKeyPrefix, []byte(contractName))
+}
+
+// StateRegistry extends the v2 registry with a version history for each named contract. The owner of
+// a name can point the name at a new contract address and/or Go contract version starting at some
+// future height, and can transfer ownership of the name.
+//
+// Contract records & names are stored the same way as in the v2 registry, so names registered by
+// the v2 registry can be upgraded once the v3 registry is in use. Until the tx:registry feature is
+// enabled contracts are registered exactly the same way as by the v2 registry.
+type StateRegistry struct {
+	State loomchain.State
+}
+
+var _ common.VersionedRegistry = &StateRegistry{}
+
+func (r *StateRegistry) v2() *registry_v2.StateRegistry {
+	return &registry_v2.StateRegistry{State: r.State}
+}
+
+// Register stores the given contract meta data, the contract name may be empty.
+func (r *StateRegistry) Register(contractName string, contractAddr, owner loom.Address) error {
+	if err := r.v2().Register(contractName, contractAddr, owner); err != nil {
+		return err
+	}
+	if contractName == "" || !r.State.FeatureEnabled(features.RegistryTxFeature, false) {
+		return nil
+	}
+	return r.setNameRecord(&common.NameRecord{
+		Name:  contractName,
+		Owner: owner.MarshalPB(),
+		Versions: []*common.ContractVersion{
+			{
+				Address: contractAddr.MarshalPB(),
+				Height:  r.State.Block().Height,
+			},
+		},
+	})
+}
+
+// Resolve looks up the address the given name currently points to.
+func (r *StateRegistry) Resolve(contractName string) (loom.Address, error) {
+	record, err := r.getNameRecord(contractName)
+	if err != nil {
+		return loom.Address{}, err
+	}
+	if record == nil {
+		return r.v2().Resolve(contractName)
+	}
+	return loom.UnmarshalAddressPB(r.activeVersion(record).Address), nil
+}
+
+func (r *StateRegistry) GetRecord(contractAddr loom.Address) (*common.Record, error) {
+	return r.v2().GetRecord(contractAddr)
+}
+
+// ScheduleUpgrade points the given name at a new contract address and/or Go contract version,
+// starting at the height specified in the version. If the version doesn't specify an address the
+// name will keep pointing at the current address.
+func (r *StateRegistry) ScheduleUpgrade(
+	contractName string, version *common.ContractVersion, caller loom.Address,
+) error {
+	if version == nil {
+		return errors.New("version not specified")
+	}
+	record, err := r.GetNameRecord(contractName)
+	if err != nil {
+		return err
+	}
+	if loom.UnmarshalAddressPB(record.Owner).Compare(caller) != 0 {
+		return common.ErrNotOwner
+	}
+
+	lastVersion := record.Versions[len(record.Versions)-1]
+	if version.Height < r.State.Block().Height || version.Height <= lastVersion.Height {
+		return common.ErrInvalidHeight
+	}
+	if version.GoVersion != "" && !validVersionRE.MatchString(version.GoVersion) {
+		return errors.New("invalid Go contract version format")
+	}
+
+	newVersion := &common.ContractVersion{
+		Address:   version.Address,
+		GoVersion: version.GoVersion,
+		Height:    version.Height,
+	}
+	if newVersion.Address == nil {
+		newVersion.Address = r.activeVersion(record).Address
+	}
+	contractAddr := loom.UnmarshalAddressPB(newVersion.Address)
+	contractRecord, err := r.GetRecord(contractAddr)
+	if err != nil {
+		return err
+	}
+	// The name of the contract record is used to look up the Go contract version for an address,
+	// so an unnamed contract takes on the name it's being upgraded to, which only the owner of the
+	// unnamed contract is allowed to do.
+	if contractRecord.Name == "" {
+		if loom.UnmarshalAddressPB(contractRecord.Owner).Compare(caller) != 0 {
+			return common.ErrNotOwner
+		}
+		contractRecord.Name = contractName
+		if err := r.setRecord(contractAddr, contractRecord); err != nil {
+			return err
+		}
+	} else if contractRecord.Name != contractName {
+		return errors.New("contract is already registered under another name")
+	}
+
+	record.Versions = append(record.Versions, newVersion)
+	return r.setNameRecord(record)
+}
+
+// TransferOwnership transfers ownership of the given name to a new owner.
+func (r *StateRegistry) TransferOwnership(contractName string, newOwner, caller loom.Address) error {
+	if newOwner.IsEmpty() {
+		return errors.New("new owner not specified")
+	}
+	record, err := r.GetNameRecord(contractName)
+	if err != nil {
+		return err
+	}
+	if loom.UnmarshalAddressPB(record.Owner).Compare(caller) != 0 {
+		return common.ErrNotOwner
+	}
+	record.Owner = newOwner.MarshalPB()
+	return r.setNameRecord(record)
+}
+
+// GetNameRecord looks up the owner & version history of the given name.
+func (r *StateRegistry) GetNameRecord(contractName string) (*common.NameRecord, error) {
+	record, err := r.getNameRecord(contractName)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		return record, nil
+	}
+
+	// Names registered by the v2 registry don't have a name record until they're modified.
+	contractAddr, err := r.v2().Resolve(contractName)
+	if err != nil {
+		return nil, err
+	}
+	contractRecord, err := r.GetRecord(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	return &common.NameRecord{
+		Name:  contractName,
+		Owner: contractRecord.Owner,
+		Versions: []*common.ContractVersion{
+			{
+				Address: contractAddr.MarshalPB(),
+			},
+		},
+	}, nil
+}
+
+// GoContractVersion returns the version of the Go contract that should currently be loaded for the
+// given address, or an empty string if the deployed version hasn't been upgraded.
+func (r *StateRegistry) GoContractVersion(contractAddr loom.Address) (string, error) {
+	contractRecord, err := r.GetRecord(contractAddr)
+	if err == common.ErrNotFound || (err == nil && contractRecord.Name == "") {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	record, err := r.getNameRecord(contractRecord.Name)
+	if err != nil || record == nil {
+		return "", err
+	}
+	version := r.activeVersion(record)
+	if loom.UnmarshalAddressPB(version.Address).Compare(contractAddr) != 0 {
+		return "", nil
+	}
+	return version.GoVersion, nil
+}
+
+// activeVersion returns the most recent version that has taken effect at the current height.
+func (r *StateRegistry) activeVersion(record *common.NameRecord) *common.ContractVersion {
+	height := r.State.Block().Height
+	for i := len(record.Versions) - 1; i > 0; i-- {
+		if record.Versions[i].Height <= height {
+			return record.Versions[i]
+		}
+	}
+	return record.Versions[0]
+}
+
+func (r *StateRegistry) getNameRecord(contractName string) (*common.NameRecord, error) {
+	data := r.State.Get(nameRecordKey(contractName))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var record common.NameRecord
+	if err := proto.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *StateRegistry) setNameRecord(record *common.NameRecord) error {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+	r.State.Set(nameRecordKey(record.Name), data)
+	return nil
+}
+
+func (r *StateRegistry) setRecord(contractAddr loom.Address, record *common.Record) error {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+	r.State.Set(registry_v2.ContractRecordKey(contractAddr), data)
+	return nil
+}
@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
+	common "github.com/loomnetwork/loomchain/registry"
+	"github.com/loomnetwork/loomchain/store"
+)
+
+var (
+	owner     = loom.MustParseAddress("default:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+	newOwner  = loom.MustParseAddress("default:0x5cecd1f7261e1f4c684e297be3edf03b825e01c4")
+	contract1 = loom.MustParseAddress("default:0x9a1aC42a17AAD6Dbc6d21c162989d0f701074044")
+	contract2 = loom.MustParseAddress("default:0x2a6b071aD396cEFdd16c731454af0d8c95ECD4B2")
+)
+
+func registryAtHeight(kvStore store.KVStore, height int64) *StateRegistry {
+	state := loomchain.NewStoreState(context.Background(), kvStore, abci.Header{Height: height}, nil, nil)
+	state.SetFeature(features.RegistryTxFeature, true)
+	return &StateRegistry{State: state}
+}
+
+func TestRegisterWithoutRegistryTxFeature(t *testing.T) {
+	kvStore := store.NewMemStore()
+	state := loomchain.NewStoreState(context.Background(), kvStore, abci.Header{Height: 10}, nil, nil)
+	reg := &StateRegistry{State: state}
+	require.NoError(t, reg.Register("foo", contract1, owner))
+
+	// Until the feature is enabled names should be registered the same way as by the v2 registry
+	require.False(t, kvStore.Has(nameRecordKey("foo")))
+	addr, err := reg.Resolve("foo")
+	require.NoError(t, err)
+	require.Equal(t, contract1, addr)
+	record, err := reg.GetNameRecord("foo")
+	require.NoError(t, err)
+	require.Equal(t, owner, loom.UnmarshalAddressPB(record.Owner))
+	require.Equal(t, contract1, loom.UnmarshalAddressPB(record.Versions[0].Address))
+}
+
+func TestScheduleUpgrade(t *testing.T) {
+	kvStore := store.NewMemStore()
+	reg := registryAtHeight(kvStore, 10)
+	require.NoError(t, reg.Register("foo", contract1, owner))
+	require.NoError(t, reg.Register("", contract2, owner))
+
+	addr, err := reg.Resolve("foo")
+	require.NoError(t, err)
+	require.Equal(t, contract1, addr)
+
+	upgrade := &common.ContractVersion{Address: contract2.MarshalPB(), GoVersion: "2.0.0", Height: 20}
+	require.Equal(t, common.ErrNotOwner, reg.ScheduleUpgrade("foo", upgrade, newOwner))
+	require.Equal(t, common.ErrInvalidHeight, reg.ScheduleUpgrade("foo", &common.ContractVersion{Height: 5}, owner))
+	require.NoError(t, reg.ScheduleUpgrade("foo", upgrade, owner))
+	// Versions must be scheduled in order
+	require.Equal(t, common.ErrInvalidHeight, reg.ScheduleUpgrade("foo", &common.ContractVersion{Height: 15}, owner))
+
+	// The upgrade shouldn't take effect until the scheduled height
+	reg = registryAtHeight(kvStore, 19)
+	addr, err = reg.Resolve("foo")
+	require.NoError(t, err)
+	require.Equal(t, contract1, addr)
+	goVersion, err := reg.GoContractVersion(contract2)
+	require.NoError(t, err)
+	require.Equal(t, "", goVersion)
+
+	reg = registryAtHeight(kvStore, 20)
+	addr, err = reg.Resolve("foo")
+	require.NoError(t, err)
+	require.Equal(t, contract2, addr)
+	goVersion, err = reg.GoContractVersion(contract2)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", goVersion)
+
+	record, err := reg.GetNameRecord("foo")
+	require.NoError(t, err)
+	require.Len(t, record.Versions, 2)
+	require.Equal(t, int64(10), record.Versions[0].Height)
+	require.Equal(t, int64(20), record.Versions[1].Height)
+}
+
+func TestScheduleUpgradeToContractOwnedByAnotherAccount(t *testing.T) {
+	kvStore := store.NewMemStore()
+	reg := registryAtHeight(kvStore, 10)
+	require.NoError(t, reg.Register("foo", contract1, owner))
+	require.NoError(t, reg.Register("", contract2, newOwner))
+
+	// The name owner shouldn't be able to link a contract they don't own to their name
+	upgrade := &common.ContractVersion{Address: contract2.MarshalPB(), Height: 20}
+	require.Equal(t, common.ErrNotOwner, reg.ScheduleUpgrade("foo", upgrade, owner))
+	contractRecord, err := reg.GetRecord(contract2)
+	require.NoError(t, err)
+	require.Equal(t, "", contractRecord.Name)
+	record, err := reg.GetNameRecord("foo")
+	require.NoError(t, err)
+	require.Len(t, record.Versions, 1)
+
+	// The owner of the unnamed contract can't link it to a name they don't own either
+	require.Equal(t, common.ErrNotOwner, reg.ScheduleUpgrade("foo", upgrade, newOwner))
+}
+
+func TestTransferOwnership(t *testing.T) {
+	reg := registryAtHeight(store.NewMemStore(), 10)
+	require.NoError(t, reg.Register("foo", contract1, owner))
+
+	require.Equal(t, common.ErrNotOwner, reg.TransferOwnership("foo", newOwner, newOwner))
+	require.NoError(t, reg.TransferOwnership("foo", newOwner, owner))
+
+	record, err := reg.GetNameRecord("foo")
+	require.NoError(t, err)
+	require.Equal(t, newOwner, loom.UnmarshalAddressPB(record.Owner))
+
+	// Only the new owner should be able to upgrade the contract
+	upgrade := &common.ContractVersion{GoVersion: "1.1.0", Height: 11}
+	require.Equal(t, common.ErrNotOwner, reg.ScheduleUpgrade("foo", upgrade, owner))
+	require.NoError(t, reg.ScheduleUpgrade("foo", upgrade, newOwner))
+}
@@ -0,0 +1,85 @@
+package loomchain
+
+import (
+	"context"
+
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// ReplayTxHandlerFactoryFunc creates a TxHandler that can be used to re-execute previously committed
+// txs. The handler must not share any mutable state with the handler used by the app (event handler,
+// receipt handler, nonce cache, etc.), and must discard any receipts & events generated by the txs it
+// processes. Any state changes that must persist even when a tx fails should be written to kvStore.
+type ReplayTxHandlerFactoryFunc func(kvStore store.KVStore) (TxHandler, error)
+
+// ReplayTxResult is the result of re-executing a previously committed tx.
+type ReplayTxResult struct {
+	TxHandlerResult
+	Err error
+}
+
+// ReadOnlyStateAt returns a snapshot of the app state as it was at the end of the block at the given
+// height, header should be the header of the block that's going to be executed against the snapshot.
+func (a *Application) ReadOnlyStateAt(height int64, header abci.Header) (State, error) {
+	hs, ok := a.Store.(store.HistoricalSnapshotter)
+	if !ok {
+		return nil, errors.New("app store doesn't retain historical state")
+	}
+	snap, err := hs.GetSnapshotAt(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "state at height %d is not available", height)
+	}
+	return NewStoreStateSnapshot(nil, snap, header, nil, a.GetValidatorSet), nil
+}
+
+// ReplayBlock re-executes the txs in a previously committed block on top of the app state at the end
+// of the previous block, stopping after the tx at lastTxIndex. None of the resulting state changes
+// are persisted. txCtx is invoked before each tx is executed, and can be used to attach values (such
+// as an EVM tracer) to the context of the state the tx will be executed against.
+//
+// NOTE: Only the txs are re-executed, any changes BeginBlock made to the app state at the given
+//       height (such as features being activated) will not be reflected in the results.
+func (a *Application) ReplayBlock(
+	header abci.Header, blockHash []byte, txs [][]byte, lastTxIndex int,
+	txCtx func(txIndex int) context.Context,
+) ([]ReplayTxResult, error) {
+	if a.CreateReplayTxHandler == nil {
+		return nil, errors.New("tx replay is not supported")
+	}
+	if lastTxIndex < 0 || lastTxIndex >= len(txs) {
+		return nil, errors.Errorf("tx index %d out of bounds for %d txs", lastTxIndex, len(txs))
+	}
+
+	snapshot, err := a.ReadOnlyStateAt(header.Height-1, header)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Release()
+
+	// All the state changes made by the txs end up in this throwaway store.
+	blockStore := store.NewOverlayStore(snapshot)
+	txHandler, err := a.CreateReplayTxHandler(blockStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tx handler")
+	}
+
+	results := make([]ReplayTxResult, 0, lastTxIndex+1)
+	for i := 0; i <= lastTxIndex; i++ {
+		ctx := context.Background()
+		if txCtx != nil {
+			ctx = txCtx(i)
+		}
+		storeTx := store.WrapAtomic(blockStore).BeginTx()
+		state := NewStoreState(ctx, storeTx, header, blockHash, a.GetValidatorSet)
+		r, err := txHandler.ProcessTx(state, txs[i], false)
+		if err == nil {
+			storeTx.Commit()
+		} else {
+			storeTx.Rollback()
+		}
+		results = append(results, ReplayTxResult{TxHandlerResult: r, Err: err})
+	}
+	return results, nil
+}
@@ -0,0 +1,97 @@
+package loomchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestReplayBlock(t *testing.T) {
+	// Each successful tx bumps a counter and writes the new count under the tx bytes, failed txs
+	// write a key that must be rolled back.
+	txHandler := TxHandlerFunc(func(state State, txBytes []byte, isCheckTx bool) (TxHandlerResult, error) {
+		if string(txBytes) == "fail" {
+			state.Set([]byte("failed"), []byte{1})
+			return TxHandlerResult{}, errors.New("tx failed")
+		}
+		count := append([]byte{}, state.Get([]byte("counter"))...)
+		count[0]++
+		state.Set([]byte("counter"), count)
+		state.Set(txBytes, count)
+		return TxHandlerResult{Info: string(txBytes)}, nil
+	})
+
+	origStore, err := mockMultiWriterStore(-1)
+	require.NoError(t, err)
+	// used to apply the replayed changes on top of the same state the original block was executed on
+	replayedStore, err := mockMultiWriterStore(-1)
+	require.NoError(t, err)
+	for _, s := range []*store.MultiWriterAppStore{origStore, replayedStore} {
+		s.Set([]byte("counter"), []byte{0})
+		_, _, err = s.SaveVersion()
+		require.NoError(t, err)
+	}
+
+	header := abci.Header{Height: 2, Time: blockTime}
+	txs := [][]byte{[]byte("a"), []byte("fail"), []byte("b")}
+	for _, tx := range txs {
+		storeTx := store.WrapAtomic(origStore).BeginTx()
+		state := NewStoreState(context.Background(), storeTx, header, nil, nil)
+		if _, err := txHandler.ProcessTx(state, tx, false); err == nil {
+			storeTx.Commit()
+		} else {
+			storeTx.Rollback()
+		}
+	}
+	appHash, version, err := origStore.SaveVersion()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+
+	var overlay *store.OverlayStore
+	app := &Application{
+		Store: origStore,
+		CreateReplayTxHandler: func(kvStore store.KVStore) (TxHandler, error) {
+			overlay = kvStore.(*store.OverlayStore)
+			return txHandler, nil
+		},
+	}
+
+	results, err := app.ReplayBlock(header, nil, txs, len(txs)-1, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, "a", results[0].Info)
+	require.EqualError(t, results[1].Err, "tx failed")
+	require.NoError(t, results[2].Err)
+	require.Equal(t, "b", results[2].Info)
+
+	// replaying the block mustn't change the committed state
+	require.Equal(t, int64(2), origStore.Version())
+	require.Equal(t, appHash, origStore.Hash())
+
+	// applying the replayed changes to the previous state should produce the original app hash
+	for _, entry := range overlay.WriteSet() {
+		if entry.Deleted {
+			replayedStore.Delete(entry.Key)
+		} else {
+			replayedStore.Set(entry.Key, entry.Value)
+		}
+	}
+	replayedAppHash, _, err := replayedStore.SaveVersion()
+	require.NoError(t, err)
+	require.Equal(t, appHash, replayedAppHash)
+
+	// replay stops after the given tx
+	results, err = app.ReplayBlock(header, nil, txs, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, []byte{1}, overlay.Get([]byte("counter")))
+	require.Nil(t, overlay.Get([]byte("b")))
+
+	_, err = app.ReplayBlock(header, nil, txs, len(txs), nil)
+	require.Error(t, err)
+}
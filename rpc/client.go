@@ -49,6 +49,12 @@ type Client struct {
 
 	// Buffered channel of outbound messages.
 	send chan []byte
+
+	// Enforces the batch size & rate limits on requests received from the client.
+	limiter *eth.RequestLimiter
+
+	// IP of the client, used for rate limiting.
+	ip string
 }
 
 // readPump pumps messages from the websocket connection.
@@ -85,7 +91,7 @@ func (c *Client) readPump(funcMap map[string]eth.RPCFunc, logger log.TMLogger) {
 			return
 		}
 
-		outBytes, ethError := handleMessage(message, funcMap, c.conn)
+		outBytes, ethError := handleMessage(message, funcMap, c.conn, c.limiter, c.ip)
 
 		if ethError != nil {
 			logger.Error("Failed to handle WebSocket message (read pump)", "err", ethError.Error())
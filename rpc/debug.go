@@ -0,0 +1,280 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	levm "github.com/loomnetwork/loomchain/evm"
+	"github.com/loomnetwork/loomchain/log"
+	lcp "github.com/loomnetwork/loomchain/plugin"
+	"github.com/loomnetwork/loomchain/rpc/eth"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// TxReplayer is used by QueryServer to re-execute previously committed txs against historical state.
+type TxReplayer interface {
+	HistoricalStateProvider
+	ReplayBlock(
+		header abci.Header, blockHash []byte, txs [][]byte, lastTxIndex int,
+		txCtx func(txIndex int) context.Context,
+	) ([]loomchain.ReplayTxResult, error)
+}
+
+// DebugTraceTransaction re-executes a previously committed tx and returns a trace of the EVM
+// execution, the output matches the output of debug_traceTransaction in go-ethereum.
+func (s *QueryServer) DebugTraceTransaction(hash eth.Data, config *eth.JsonTraceConfig) (interface{}, error) {
+	if s.TxReplayer == nil {
+		return nil, errors.New("[debug_traceTransaction] tracing is not supported by this node")
+	}
+	txHash, err := eth.DecDataToBytes(hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceTransaction] invalid tx hash")
+	}
+
+	var height int64
+	var txIndex int
+	if receipt, err := s.ReceiptHandlerProvider.Reader().GetReceipt(txHash); err == nil {
+		height = int64(receipt.BlockNumber)
+		txIndex = int(receipt.TransactionIndex)
+	} else {
+		// not an EVM tx hash, might be a Tendermint tx hash
+		txResult, err := s.BlockStore.GetTxResult(txHash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[debug_traceTransaction] tx %s not found", hash)
+		}
+		height = txResult.Height
+		txIndex = int(txResult.Index)
+	}
+
+	blockResult, err := s.BlockStore.GetBlockByHeight(&height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[debug_traceTransaction] failed to load block %d", height)
+	}
+
+	tracer, err := levm.NewTracer(decTraceConfig(config))
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceTransaction] failed to create tracer")
+	}
+	results, err := s.TxReplayer.ReplayBlock(
+		abciHeaderFromBlock(blockResult),
+		blockResult.BlockMeta.BlockID.Hash,
+		blockTxs(blockResult),
+		txIndex,
+		func(i int) context.Context {
+			if i == txIndex {
+				return levm.WithTracer(context.Background(), tracer)
+			}
+			return context.Background()
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceTransaction] failed to replay tx")
+	}
+	if r := results[txIndex]; r.Err != nil {
+		log.Debug("[debug_traceTransaction] replayed tx failed", "tx", hash, "err", r.Err)
+	}
+	return tracer.Result()
+}
+
+// DebugTraceBlockByNumber re-executes all the txs in a previously committed block and returns a
+// trace of the EVM execution of each tx.
+func (s *QueryServer) DebugTraceBlockByNumber(
+	block eth.BlockHeight, config *eth.JsonTraceConfig,
+) ([]eth.JsonTxTraceResult, error) {
+	if s.TxReplayer == nil {
+		return nil, errors.New("[debug_traceBlockByNumber] tracing is not supported by this node")
+	}
+	snapshot := s.StateProvider.ReadOnlyState()
+	lastHeight := snapshot.Block().Height
+	snapshot.Release()
+
+	height, err := eth.DecBlockHeight(lastHeight, block)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceBlockByNumber] invalid block height")
+	}
+	h := int64(height)
+	blockResult, err := s.BlockStore.GetBlockByHeight(&h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[debug_traceBlockByNumber] failed to load block %d", h)
+	}
+
+	txs := blockTxs(blockResult)
+	if len(txs) == 0 {
+		return []eth.JsonTxTraceResult{}, nil
+	}
+
+	cfg := decTraceConfig(config)
+	tracers := make([]levm.Tracer, len(txs))
+	for i := range tracers {
+		if tracers[i], err = levm.NewTracer(cfg); err != nil {
+			return nil, errors.Wrap(err, "[debug_traceBlockByNumber] failed to create tracer")
+		}
+	}
+	_, err = s.TxReplayer.ReplayBlock(
+		abciHeaderFromBlock(blockResult),
+		blockResult.BlockMeta.BlockID.Hash,
+		txs,
+		len(txs)-1,
+		func(i int) context.Context {
+			return levm.WithTracer(context.Background(), tracers[i])
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceBlockByNumber] failed to replay block")
+	}
+
+	results := make([]eth.JsonTxTraceResult, len(txs))
+	for i, tracer := range tracers {
+		results[i].TxHash = eth.EncBytes(blockResult.Block.Data.Txs[i].Hash())
+		if result, err := tracer.Result(); err != nil {
+			results[i].Error = err.Error()
+		} else {
+			results[i].Result = result
+		}
+	}
+	return results, nil
+}
+
+// DebugTraceCall executes an EVM call on top of the state at the given block height and returns a
+// trace of the EVM execution, none of the resulting state changes are persisted. If the call object
+// doesn't specify a contract address the call data is deployed as a new contract.
+func (s *QueryServer) DebugTraceCall(
+	query eth.JsonTxCallObject, block eth.BlockHeight, config *eth.JsonTraceConfig,
+) (interface{}, error) {
+	if s.TxReplayer == nil {
+		return nil, errors.New("[debug_traceCall] tracing is not supported by this node")
+	}
+	snapshot := s.StateProvider.ReadOnlyState()
+	lastHeight := snapshot.Block().Height
+	snapshot.Release()
+
+	height, err := eth.DecBlockHeight(lastHeight, block)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceCall] invalid block height")
+	}
+	h := int64(height)
+	blockResult, err := s.BlockStore.GetBlockByHeight(&h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[debug_traceCall] failed to load block %d", h)
+	}
+	header := abciHeaderFromBlock(blockResult)
+	histState, err := s.TxReplayer.ReadOnlyStateAt(h, header)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceCall]")
+	}
+	defer histState.Release()
+
+	tracer, err := levm.NewTracer(decTraceConfig(config))
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceCall] failed to create tracer")
+	}
+	state := loomchain.NewStoreState(
+		levm.WithTracer(context.Background(), tracer),
+		store.NewOverlayStore(histState),
+		header,
+		blockResult.BlockMeta.BlockID.Hash,
+		nil,
+	)
+
+	var caller loom.Address
+	if len(query.From) > 0 {
+		caller, err = s.resolveEthAccountLoomAddress(state, query.From)
+		if err != nil {
+			return nil, errors.Wrap(err, "[debug_traceCall] invalid from address")
+		}
+	} else {
+		caller = loom.RootAddress(s.ChainID)
+	}
+	callerAddr, err := auth.ResolveAccountAddress(caller, state, s.AuthCfg, s.createAddressMapperCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceCall] failed to resolve account address")
+	}
+	data, err := eth.DecDataToBytes(query.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "[debug_traceCall] invalid data")
+	}
+	var value *loom.BigUInt
+	if len(query.Value) > 0 {
+		v, err := hexutil.DecodeBig(string(query.Value))
+		if err != nil {
+			return nil, errors.Wrap(err, "[debug_traceCall] invalid value")
+		}
+		value = loom.NewBigUInt(v)
+	}
+
+	var createABM levm.AccountBalanceManagerFactoryFunc
+	if s.NewABMFactory != nil {
+		pvm := lcp.NewPluginVM(
+			s.Loader,
+			state,
+			s.CreateRegistry(state),
+			nil,
+			log.Default,
+			s.NewABMFactory,
+			nil,
+			nil,
+		)
+		createABM, err = s.NewABMFactory(pvm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	vm := levm.NewLoomVm(state, nil, nil, createABM, false)
+	if len(query.To) == 0 {
+		_, _, err = vm.Create(callerAddr, data, value)
+	} else {
+		var contract loom.Address
+		contract, err = eth.DecDataToAddress(s.ChainID, query.To)
+		if err != nil {
+			return nil, errors.Wrap(err, "[debug_traceCall] invalid to address")
+		}
+		_, err = vm.Call(callerAddr, contract, data, value)
+	}
+	if err != nil {
+		log.Debug("[debug_traceCall] traced call failed", "err", err)
+	}
+	return tracer.Result()
+}
+
+func decTraceConfig(config *eth.JsonTraceConfig) levm.TraceConfig {
+	if config == nil {
+		return levm.TraceConfig{}
+	}
+	return levm.TraceConfig{
+		DisableStorage: config.DisableStorage,
+		DisableMemory:  config.DisableMemory,
+		DisableStack:   config.DisableStack,
+		Limit:          config.Limit,
+		Tracer:         config.Tracer,
+		Timeout:        config.Timeout,
+	}
+}
+
+func blockTxs(blockResult *ctypes.ResultBlock) [][]byte {
+	txs := make([][]byte, len(blockResult.Block.Data.Txs))
+	for i, tx := range blockResult.Block.Data.Txs {
+		txs[i] = tx
+	}
+	return txs
+}
+
+func abciHeaderFromBlock(blockResult *ctypes.ResultBlock) abci.Header {
+	header := blockResult.Block.Header
+	return abci.Header{
+		ChainID: header.ChainID,
+		Height:  header.Height,
+		Time:    header.Time,
+		NumTxs:  header.NumTxs,
+		LastBlockId: abci.BlockID{
+			Hash: header.LastBlockID.Hash,
+		},
+		ValidatorsHash: header.ValidatorsHash,
+		AppHash:        header.AppHash,
+	}
+}
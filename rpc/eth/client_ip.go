@@ -0,0 +1,92 @@
+package eth
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ClientIPResolver determines the IP of the client that sent an HTTP request. Requests relayed by a
+// trusted reverse proxy are attributed to the client IP recorded by the proxy in the X-Forwarded-For
+// or X-Real-IP header. The headers are ignored on requests from any other peer, since clients can
+// set them to anything. A nil ClientIPResolver doesn't trust any proxies.
+type ClientIPResolver struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewClientIPResolver creates a ClientIPResolver that trusts the given proxies, each entry can be
+// either an IP or a CIDR range.
+func NewClientIPResolver(trustedProxies []string) (*ClientIPResolver, error) {
+	r := &ClientIPResolver{}
+	for _, entry := range trustedProxies {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, errors.Errorf("invalid trusted proxy IP %s", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			r.trustedProxies = append(r.trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy range %s", entry)
+		}
+		r.trustedProxies = append(r.trustedProxies, ipNet)
+	}
+	return r, nil
+}
+
+// ClientIP returns the IP of the client that sent the given request.
+func (r *ClientIPResolver) ClientIP(req *http.Request) string {
+	peerIP := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		peerIP = host
+	}
+	if r == nil || !r.isTrusted(peerIP) {
+		return peerIP
+	}
+
+	// Each proxy appends the address of the peer it received the request from, so the client is
+	// the right-most address that wasn't added by one of the trusted proxies.
+	if header := req.Header.Get("X-Forwarded-For"); header != "" {
+		addrs := strings.Split(header, ",")
+		clientIP := ""
+		for i := len(addrs) - 1; i >= 0; i-- {
+			addr := strings.TrimSpace(addrs[i])
+			if net.ParseIP(addr) == nil {
+				break
+			}
+			clientIP = addr
+			if !r.isTrusted(addr) {
+				break
+			}
+		}
+		if clientIP != "" {
+			return clientIP
+		}
+	}
+	if realIP := strings.TrimSpace(req.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return peerIP
+}
+
+func (r *ClientIPResolver) isTrusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range r.trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package eth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPResolver(t *testing.T) {
+	r, err := NewClientIPResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/eth", nil)
+	req.RemoteAddr = "1.2.3.4:5678"
+	require.Equal(t, "1.2.3.4", r.ClientIP(req))
+
+	// proxy headers from untrusted peers are ignored
+	req.Header.Set("X-Forwarded-For", "5.6.7.8")
+	req.Header.Set("X-Real-IP", "5.6.7.8")
+	require.Equal(t, "1.2.3.4", r.ClientIP(req))
+	var nilResolver *ClientIPResolver
+	require.Equal(t, "1.2.3.4", nilResolver.ClientIP(req))
+
+	// the client is the right-most address not added by a trusted proxy
+	req.RemoteAddr = "10.1.2.3:5678"
+	req.Header.Set("X-Forwarded-For", "9.9.9.9, 5.6.7.8, 192.168.1.1")
+	require.Equal(t, "5.6.7.8", r.ClientIP(req))
+
+	// if every address belongs to a trusted proxy the left-most one is used
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 192.168.1.1")
+	require.Equal(t, "10.0.0.1", r.ClientIP(req))
+
+	// X-Real-IP is only used when X-Forwarded-For isn't set
+	req.Header.Del("X-Forwarded-For")
+	require.Equal(t, "5.6.7.8", r.ClientIP(req))
+
+	// malformed headers fall back to the peer address
+	req.Header.Set("X-Real-IP", "not-an-ip")
+	require.Equal(t, "10.1.2.3", r.ClientIP(req))
+
+	_, err = NewClientIPResolver([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+	_, err = NewClientIPResolver([]string{"proxy.local"})
+	require.Error(t, err)
+}
@@ -5,10 +5,40 @@ package eth
 type Web3Config struct {
 	// GetLogsMaxBlockRange specifies the maximum number of blocks eth_getLogs will query per request
 	GetLogsMaxBlockRange uint64
+	// GetLogsMaxIndexedBlockRange specifies the maximum number of blocks eth_getLogs will query per
+	// request when the blocks are covered by the bloom-bits index, only the blocks that aren't
+	// covered by the index count towards GetLogsMaxBlockRange.
+	GetLogsMaxIndexedBlockRange uint64
+	// MaxBatchRequestSize specifies the maximum number of requests that can be sent in a single
+	// JSON-RPC batch, zero means there's no limit.
+	MaxBatchRequestSize int
+	// MethodRateLimits specifies how often each client IP is allowed to call a method, keyed by
+	// method name (case insensitive), methods that aren't listed aren't rate limited.
+	MethodRateLimits map[string]*MethodRateLimit
+	// TrustedProxies lists the IPs (or CIDR ranges) of the reverse proxies in front of the node, the
+	// client IP used for rate limiting & logging is taken from the X-Forwarded-For or X-Real-IP
+	// header of requests relayed by these proxies.
+	TrustedProxies []string
+	// DebugRPCEnabled enables the debug_trace* methods, these methods replay txs & blocks so they're
+	// a lot more expensive than the rest of the methods, and should only be enabled on nodes that
+	// aren't open to the public.
+	DebugRPCEnabled bool
+}
+
+// MethodRateLimit specifies the token bucket used to rate limit calls to a single method.
+type MethodRateLimit struct {
+	// Number of tokens added to the bucket every second.
+	RequestsPerSecond float64
+	// Max number of tokens the bucket can hold, i.e. the max number of calls that can be made in
+	// quick succession after a period of inactivity.
+	Burst int
 }
 
 func DefaultWeb3Config() *Web3Config {
 	return &Web3Config{
-		GetLogsMaxBlockRange: 20,
+		GetLogsMaxBlockRange:        20,
+		GetLogsMaxIndexedBlockRange: 100000,
+		MaxBatchRequestSize:         100,
+		MethodRateLimits:            map[string]*MethodRateLimit{},
 	}
 }
@@ -48,4 +48,22 @@ func TestEncBigInt(t *testing.T) {
 	var bigLots big.Int
 	bigLots.Mul(bigMaxInt, bigMaxInt)
 	require.Equal(t, Quantity("0x3fffffffffffffff0000000000000001"), EncBigInt(bigLots))
-}
\ No newline at end of file
+}
+
+func TestLogCursor(t *testing.T) {
+	cursor := LogCursor{Height: 1234, TxIndex: 3, LogIndex: 7}
+	decoded, err := DecLogCursor(EncLogCursor(cursor))
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+
+	decoded, err = DecLogCursor("")
+	require.NoError(t, err)
+	require.Equal(t, LogCursor{}, decoded)
+
+	_, err = DecLogCursor("invalid")
+	require.Error(t, err)
+
+	require.True(t, LogCursor{Height: 1, TxIndex: 5}.Less(LogCursor{Height: 2}))
+	require.True(t, LogCursor{Height: 2, LogIndex: 5}.Less(LogCursor{Height: 2, TxIndex: 1}))
+	require.False(t, cursor.Less(cursor))
+}
@@ -0,0 +1,55 @@
+package eth
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const logCursorLength = 16
+
+// LogCursor identifies the position of a log on the chain, logs are ordered by block height, then by
+// the index of the tx within the block, and then by the index of the log within the tx. The paged
+// query methods accept & return cursors encoded as opaque strings.
+type LogCursor struct {
+	Height   uint64
+	TxIndex  uint32
+	LogIndex uint32
+}
+
+// Less checks if the position identified by this cursor comes before the given one.
+func (c LogCursor) Less(other LogCursor) bool {
+	if c.Height != other.Height {
+		return c.Height < other.Height
+	}
+	if c.TxIndex != other.TxIndex {
+		return c.TxIndex < other.TxIndex
+	}
+	return c.LogIndex < other.LogIndex
+}
+
+func EncLogCursor(cursor LogCursor) string {
+	b := make([]byte, logCursorLength)
+	binary.BigEndian.PutUint64(b, cursor.Height)
+	binary.BigEndian.PutUint32(b[8:], cursor.TxIndex)
+	binary.BigEndian.PutUint32(b[12:], cursor.LogIndex)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecLogCursor decodes a cursor previously encoded by EncLogCursor, an empty string decodes to a
+// cursor pointing at the start of the chain.
+func DecLogCursor(cursor string) (LogCursor, error) {
+	if len(cursor) == 0 {
+		return LogCursor{}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) != logCursorLength {
+		return LogCursor{}, errors.Errorf("invalid cursor %s", cursor)
+	}
+	return LogCursor{
+		Height:   binary.BigEndian.Uint64(b),
+		TxIndex:  binary.BigEndian.Uint32(b[8:]),
+		LogIndex: binary.BigEndian.Uint32(b[12:]),
+	}, nil
+}
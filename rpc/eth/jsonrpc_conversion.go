@@ -29,6 +29,8 @@ const (
 	ZeroedData32Bytes  Data     = "0x0000000000000000000000000000000000000000000000000000000000000000"
 	ZeroedData64bytes  Data     = "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"
 	ZeroedData256Bytes Data     = "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"
+	// Root hash of an empty Merkle-Patricia trie
+	EmptyRootHash Data = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
 
 	StatusTxSuccess = "0x1"
 )
@@ -107,6 +109,21 @@ type JsonTxCallObject struct {
 	Nonce    Quantity `json:"nonce,omitempty"`
 }
 
+type JsonTraceConfig struct {
+	DisableStorage bool   `json:"disableStorage,omitempty"`
+	DisableMemory  bool   `json:"disableMemory,omitempty"`
+	DisableStack   bool   `json:"disableStack,omitempty"`
+	Limit          int    `json:"limit,omitempty"`
+	Tracer         string `json:"tracer,omitempty"`
+	Timeout        string `json:"timeout,omitempty"`
+}
+
+type JsonTxTraceResult struct {
+	TxHash Data        `json:"txHash,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
 type JsonFilter struct {
 	FromBlock BlockHeight   `json:"fromBlock,omitempty"`
 	ToBlock   BlockHeight   `json:"toBlock,omitempty"`
@@ -115,6 +132,23 @@ type JsonFilter struct {
 	BlockHash Data          `json:"blockhash,omitempty"`
 }
 
+// JsonAccountProof is the result of eth_getProof (EIP-1186).
+type JsonAccountProof struct {
+	Address      Data               `json:"address"`
+	AccountProof []Data             `json:"accountProof"`
+	Balance      Quantity           `json:"balance"`
+	CodeHash     Data               `json:"codeHash"`
+	Nonce        Quantity           `json:"nonce"`
+	StorageHash  Data               `json:"storageHash"`
+	StorageProof []JsonStorageProof `json:"storageProof"`
+}
+
+type JsonStorageProof struct {
+	Key   Data     `json:"key"`
+	Value Quantity `json:"value"`
+	Proof []Data   `json:"proof"`
+}
+
 func EncTxReceipt(receipt types.EvmTxReceipt) JsonTxReceipt {
 	return JsonTxReceipt{
 		TransactionIndex:  EncInt(int64(receipt.TransactionIndex)),
@@ -501,14 +535,14 @@ func GetBlockZero() JsonBlockObject {
 		Nonce:            ZeroedData8Bytes,
 		Sha3Uncles:       ZeroedData32Bytes,
 		TransactionsRoot: ZeroedData32Bytes,
-		StateRoot:        ZeroedData32Bytes,
-		ReceiptsRoot:     ZeroedData32Bytes,
+		StateRoot:        EmptyRootHash,
+		ReceiptsRoot:     EmptyRootHash,
 		Miner:            ZeroedData20Bytes,
 		Difficulty:       ZeroedQuantity,
 		TotalDifficulty:  ZeroedQuantity,
 		ExtraData:        ZeroedData,
 		Uncles:           []Data{},
-		LogsBloom:        ZeroedData,
+		LogsBloom:        ZeroedData256Bytes,
 	}
 
 	blockInfo.Transactions = make([]interface{}, 0)
@@ -45,6 +45,7 @@ const (
 	EcInvalidParams  ErrorCode = -32602 // Invalid method parameter(s).
 	EcInternal       ErrorCode = -32603 // Internal JSON-RPC error.
 	EcServer         ErrorCode = -32000 // Reserved for implementation-defined server-errors.
+	EcLimitExceeded  ErrorCode = -32005 // Request exceeds a rate limit, as defined by EIP-1474.
 )
 
 type Error struct {
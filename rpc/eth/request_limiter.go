@@ -0,0 +1,127 @@
+package eth
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// How often idle token buckets are discarded.
+const requestLimiterPruneInterval = time.Minute
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+type bucketKey struct {
+	ip     string
+	method string
+}
+
+// RequestLimiter enforces the batch size & per-IP, per-method rate limits specified in Web3Config.
+// A nil RequestLimiter doesn't enforce any limits.
+type RequestLimiter struct {
+	maxBatchSize int
+	limits       map[string]MethodRateLimit
+
+	mutex     sync.Mutex
+	buckets   map[bucketKey]*tokenBucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewRequestLimiter creates a RequestLimiter that enforces the limits specified in the given config,
+// returns nil if the config doesn't specify any limits.
+func NewRequestLimiter(cfg *Web3Config) *RequestLimiter {
+	if cfg == nil || (cfg.MaxBatchRequestSize <= 0 && len(cfg.MethodRateLimits) == 0) {
+		return nil
+	}
+	limits := map[string]MethodRateLimit{}
+	for method, limit := range cfg.MethodRateLimits {
+		if limit == nil {
+			continue
+		}
+		// Viper lower-cases map keys when it loads the config, so normalize the method names.
+		limits[strings.ToLower(method)] = *limit
+	}
+	return &RequestLimiter{
+		maxBatchSize: cfg.MaxBatchRequestSize,
+		limits:       limits,
+		buckets:      map[bucketKey]*tokenBucket{},
+		now:          time.Now,
+	}
+}
+
+// CheckBatchSize returns an error if a batch with the given number of requests exceeds the max
+// batch size.
+func (l *RequestLimiter) CheckBatchSize(size int) *Error {
+	if l == nil || l.maxBatchSize <= 0 || size <= l.maxBatchSize {
+		return nil
+	}
+	return NewErrorf(
+		EcInvalidRequest, "Batch too large", "batch contains %d requests, max is %d", size, l.maxBatchSize,
+	)
+}
+
+// Allow consumes a token from the bucket of the given client IP & method, and returns an error if
+// the bucket is empty.
+func (l *RequestLimiter) Allow(ip, method string) *Error {
+	if l == nil {
+		return nil
+	}
+	method = strings.ToLower(method)
+	limit, ok := l.limits[method]
+	if !ok {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.pruneBuckets(now)
+
+	key := bucketKey{ip: ip, method: method}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), lastRefill: now}
+		l.buckets[key] = bucket
+	} else {
+		bucket.refill(limit, now)
+	}
+
+	if bucket.tokens < 1 {
+		return NewErrorf(EcLimitExceeded, "Rate limit exceeded", "too many %s requests from %s", method, ip)
+	}
+	bucket.tokens--
+	return nil
+}
+
+// pruneBuckets discards the buckets that have been refilled to capacity, since a full bucket is
+// indistinguishable from a new one there's no point keeping them around.
+func (l *RequestLimiter) pruneBuckets(now time.Time) {
+	if now.Sub(l.lastPrune) < requestLimiterPruneInterval {
+		return
+	}
+	l.lastPrune = now
+	for key, bucket := range l.buckets {
+		limit := l.limits[key.method]
+		bucket.refill(limit, now)
+		if bucket.tokens >= float64(limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *tokenBucket) refill(limit MethodRateLimit, now time.Time) {
+	elapsed := now.Sub(b.lastRefill)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens += elapsed.Seconds() * limit.RequestsPerSecond
+	if b.tokens > float64(limit.Burst) {
+		b.tokens = float64(limit.Burst)
+	}
+	b.lastRefill = now
+}
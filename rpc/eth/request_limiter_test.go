@@ -0,0 +1,47 @@
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLimiter(t *testing.T) {
+	require.Nil(t, NewRequestLimiter(nil))
+	require.Nil(t, NewRequestLimiter(&Web3Config{}))
+
+	cfg := DefaultWeb3Config()
+	cfg.MaxBatchRequestSize = 2
+	// viper lower-cases the keys, so the limiter should treat method names as case insensitive
+	cfg.MethodRateLimits["eth_getlogs"] = &MethodRateLimit{RequestsPerSecond: 1, Burst: 2}
+	limiter := NewRequestLimiter(cfg)
+	now := time.Unix(1000, 0)
+	limiter.now = func() time.Time { return now }
+
+	require.Nil(t, limiter.CheckBatchSize(2))
+	require.NotNil(t, limiter.CheckBatchSize(3))
+
+	require.Nil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+	require.Nil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+	require.Equal(t, EcLimitExceeded, limiter.Allow("1.1.1.1", "eth_getLogs").Code)
+	// other IPs & methods have their own buckets
+	require.Nil(t, limiter.Allow("2.2.2.2", "eth_getLogs"))
+	for i := 0; i < 10; i++ {
+		require.Nil(t, limiter.Allow("1.1.1.1", "eth_blockNumber"))
+	}
+
+	// one token is added per second, up to the burst size
+	now = now.Add(1500 * time.Millisecond)
+	require.Nil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+	require.NotNil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+	now = now.Add(time.Hour)
+	require.Nil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+	require.Nil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+	require.NotNil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+
+	// idle buckets should be discarded
+	now = now.Add(time.Hour)
+	require.Nil(t, limiter.Allow("1.1.1.1", "eth_getLogs"))
+	require.Len(t, limiter.buckets, 1)
+}
@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"github.com/loomnetwork/loomchain/log"
+	"github.com/loomnetwork/loomchain/store"
+)
+
+const (
+	// Max number of events returned by a single HTTP request.
+	maxEventPageSize = 1000
+	// Max number of events read from the event store at a time while streaming events.
+	eventStreamBatchSize = 100
+)
+
+// EventPage is returned by the HTTP event stream endpoint.
+type EventPage struct {
+	Events []*store.CursorEvent `json:"events"`
+	// Cursor that should be used to fetch the next page.
+	Next store.EventCursor `json:"next"`
+}
+
+// MakeEventStreamHandler returns an http handler that serves events from the event store.
+//
+// GET /events?height=H&index=I&contract=C&topic=T1&topic=T2&limit=N returns a page of events
+// starting at cursor (H, I), along with the cursor of the next page.
+//
+// GET /eventsws with the same parameters (except limit) upgrades the connection to a WebSocket,
+// sends all the events starting at cursor (H, I), and then sends new events as they're stored.
+// Each WebSocket message is a single JSON-encoded store.CursorEvent, if the connection drops the
+// client should reconnect from the cursor following the last event it received.
+func MakeEventStreamHandler(eventStore store.EventStore, logger log.TMLogger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", func(w http.ResponseWriter, req *http.Request) {
+		from, filter, err := parseEventStreamParams(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limit := maxEventPageSize
+		if l := req.URL.Query().Get("limit"); l != "" {
+			if limit, err = strconv.Atoi(l); err != nil || limit <= 0 || limit > maxEventPageSize {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+		events, err := eventStore.ReadEvents(from, filter, limit)
+		if err != nil {
+			logger.Error("Failed to read events", "err", err)
+			http.Error(w, "failed to read events", http.StatusInternalServerError)
+			return
+		}
+		page := EventPage{
+			Events: events,
+			Next:   from,
+		}
+		if page.Events == nil {
+			page.Events = []*store.CursorEvent{}
+		}
+		if len(events) > 0 {
+			page.Next = events[len(events)-1].Cursor.Next()
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		WriteResponse(w, page)
+	})
+	mux.HandleFunc("/eventsws", func(w http.ResponseWriter, req *http.Request) {
+		from, filter, err := parseEventStreamParams(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			logger.Error("Failed to upgrade event stream connection", "err", err)
+			return
+		}
+		streamEvents(conn, eventStore, from, filter, logger)
+	})
+	return mux
+}
+
+// streamEvents sends all the events from the given cursor onwards to the client until the client
+// disconnects.
+func streamEvents(
+	conn *websocket.Conn, eventStore store.EventStore, from store.EventCursor, filter store.EventFilter,
+	logger log.TMLogger,
+) {
+	defer conn.Close()
+
+	// The client isn't expected to send anything, but the connection must be read from to detect
+	// when the client goes away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(maxMessageSize)
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error { _ = conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	cursor := from
+	for {
+		// Grab the notification channel before reading the events so that events stored after the
+		// read aren't missed.
+		saved := eventStore.EventsSaved()
+		for {
+			events, err := eventStore.ReadEvents(cursor, filter, eventStreamBatchSize)
+			if err != nil {
+				logger.Error("Failed to read events", "err", err)
+				return
+			}
+			for _, event := range events {
+				_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := conn.WriteJSON(event); err != nil {
+					logger.Debug("Failed to write to event stream", "err", err)
+					return
+				}
+				cursor = event.Cursor.Next()
+			}
+			if len(events) < eventStreamBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-saved:
+		case <-ticker.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func parseEventStreamParams(req *http.Request) (store.EventCursor, store.EventFilter, error) {
+	var cursor store.EventCursor
+	var filter store.EventFilter
+	query := req.URL.Query()
+	if h := query.Get("height"); h != "" {
+		height, err := strconv.ParseUint(h, 10, 64)
+		if err != nil {
+			return cursor, filter, errors.Wrap(err, "invalid height")
+		}
+		cursor.BlockHeight = height
+	}
+	if i := query.Get("index"); i != "" {
+		index, err := strconv.ParseUint(i, 10, 16)
+		if err != nil {
+			return cursor, filter, errors.Wrap(err, "invalid index")
+		}
+		cursor.EventIndex = uint16(index)
+	}
+	filter.Contract = strings.TrimSpace(query.Get("contract"))
+	for _, topic := range query["topic"] {
+		if topic != "" {
+			filter.Topics = append(filter.Topics, topic)
+		}
+	}
+	return cursor, filter, nil
+}
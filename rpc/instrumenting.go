@@ -8,6 +8,8 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/loomnetwork/go-loom/plugin/types"
 	"github.com/loomnetwork/loomchain/config"
+	"github.com/loomnetwork/loomchain/fnConsensus"
+	"github.com/loomnetwork/loomchain/registry"
 	"github.com/loomnetwork/loomchain/rpc/eth"
 	"github.com/loomnetwork/loomchain/vm"
 	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
@@ -45,6 +47,20 @@ func (m InstrumentingMiddleware) Query(
 	return
 }
 
+// QueryAt calls service QueryAt and captures metrics
+func (m InstrumentingMiddleware) QueryAt(
+	caller, contract string, query []byte, height int64, prove bool,
+) (resp *QueryResult, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "QueryAt", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.QueryAt(caller, contract, query, height, prove)
+	return
+}
+
 func (m InstrumentingMiddleware) QueryEnv() (resp *config.EnvInfo, err error) {
 	defer func(begin time.Time) {
 		lvs := []string{"method", "QueryEnv", "error", fmt.Sprint(err != nil)}
@@ -58,14 +74,14 @@ func (m InstrumentingMiddleware) QueryEnv() (resp *config.EnvInfo, err error) {
 }
 
 // Nonce call service Nonce method and captures metrics
-func (m InstrumentingMiddleware) Nonce(key, account string) (resp uint64, err error) {
+func (m InstrumentingMiddleware) Nonce(key, account string, lane uint32) (resp uint64, err error) {
 	defer func(begin time.Time) {
 		lvs := []string{"method", "Nonce", "error", fmt.Sprint(err != nil)}
 		m.requestCount.With(lvs...).Add(1)
 		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	resp, err = m.next.Nonce(key, account)
+	resp, err = m.next.Nonce(key, account, lane)
 	return
 }
 
@@ -111,6 +127,18 @@ func (m InstrumentingMiddleware) ContractEvents(
 	return
 }
 
+func (m InstrumentingMiddleware) ContractEventsPaged(
+	fromBlock, toBlock uint64, contract string, limit int, cursor string,
+) (result *ContractEventsPage, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "ContractEventsPaged", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	result, err = m.next.ContractEventsPaged(fromBlock, toBlock, contract, limit, cursor)
+	return
+}
+
 func (m InstrumentingMiddleware) GetContractRecord(contractAddr string) (resp *types.ContractRecordResponse, err error) {
 	defer func(begin time.Time) {
 		lvs := []string{"method", "GetContractRecord", "error", fmt.Sprint(err != nil)}
@@ -125,6 +153,17 @@ func (m InstrumentingMiddleware) GetContractRecord(contractAddr string) (resp *t
 	return
 }
 
+func (m InstrumentingMiddleware) GetContractVersions(name string) (resp *registry.NameRecord, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "GetContractVersions", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.GetContractVersions(name)
+	return
+}
+
 func (m InstrumentingMiddleware) DPOSTotalStaked() (resp *DPOSTotalStakedResponse, err error) {
 	defer func(begin time.Time) {
 		lvs := []string{"method", "DposTotalStaked", "error", fmt.Sprint(err != nil)}
@@ -150,6 +189,28 @@ func (m InstrumentingMiddleware) GetCanonicalTxHash(block, txIndex uint64, evmTx
 	return resp, err
 }
 
+func (m InstrumentingMiddleware) FnConsensusStatus() (resp *fnConsensus.ReactorStatus, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "FnConsensusStatus", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.FnConsensusStatus()
+	return resp, err
+}
+
+func (m InstrumentingMiddleware) SimulateTx(tx []byte, caller string) (resp *SimulateTxResponse, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "SimulateTx", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.SimulateTx(tx, caller)
+	return resp, err
+}
+
 func (m InstrumentingMiddleware) GetEvmCode(contract string) (resp []byte, err error) {
 	defer func(begin time.Time) {
 		lvs := []string{"method", "GetEvmCode", "error", fmt.Sprint(err != nil)}
@@ -426,6 +487,19 @@ func (m InstrumentingMiddleware) EthGetLogs(filter eth.JsonFilter) (resp []eth.J
 	return
 }
 
+func (m InstrumentingMiddleware) EthGetLogsPaged(
+	filter eth.JsonFilter, limit eth.Quantity, cursor string,
+) (resp *LogsPage, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "EthGetLogsPaged", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.EthGetLogsPaged(filter, limit, cursor)
+	return
+}
+
 func (m InstrumentingMiddleware) EthNewBlockFilter() (resp eth.Quantity, err error) {
 	defer func(begin time.Time) {
 		lvs := []string{"method", "EthNewBlockFilter", "error", fmt.Sprint(err != nil)}
@@ -538,6 +612,19 @@ func (m InstrumentingMiddleware) EthGetStorageAt(address eth.Data, position stri
 	return
 }
 
+func (m InstrumentingMiddleware) EthGetProof(
+	address eth.Data, storageKeys []eth.Data, block eth.BlockHeight,
+) (resp *eth.JsonAccountProof, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "EthGetProof", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.EthGetProof(address, storageKeys, block)
+	return
+}
+
 func (m InstrumentingMiddleware) EthEstimateGas(
 	query eth.JsonTxCallObject, block eth.BlockHeight,
 ) (resp eth.Quantity, err error) {
@@ -596,3 +683,42 @@ func (m InstrumentingMiddleware) EthGetTransactionCount(
 	resp, err = m.next.EthGetTransactionCount(local, block)
 	return
 }
+
+func (m InstrumentingMiddleware) DebugTraceTransaction(
+	hash eth.Data, config *eth.JsonTraceConfig,
+) (resp interface{}, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "DebugTraceTransaction", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.DebugTraceTransaction(hash, config)
+	return
+}
+
+func (m InstrumentingMiddleware) DebugTraceCall(
+	query eth.JsonTxCallObject, block eth.BlockHeight, config *eth.JsonTraceConfig,
+) (resp interface{}, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "DebugTraceCall", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.DebugTraceCall(query, block, config)
+	return
+}
+
+func (m InstrumentingMiddleware) DebugTraceBlockByNumber(
+	block eth.BlockHeight, config *eth.JsonTraceConfig,
+) (resp []eth.JsonTxTraceResult, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "DebugTraceBlockByNumber", "error", fmt.Sprint(err != nil)}
+		m.requestCount.With(lvs...).Add(1)
+		m.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	resp, err = m.next.DebugTraceBlockByNumber(block, config)
+	return
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,7 +14,10 @@ import (
 	"github.com/loomnetwork/loomchain/rpc/eth"
 )
 
-func RegisterRPCFuncs(mux *http.ServeMux, funcMap map[string]eth.RPCFunc, logger log.TMLogger, hub *Hub) {
+func RegisterRPCFuncs(
+	mux *http.ServeMux, funcMap map[string]eth.RPCFunc, logger log.TMLogger, hub *Hub, limiter *eth.RequestLimiter,
+	ipResolver *eth.ClientIPResolver,
+) {
 	mux.HandleFunc("/", func(writer http.ResponseWriter, reader *http.Request) {
 		if isWebSocketConnection(reader) {
 			conn, err := upgrader.Upgrade(writer, reader, nil)
@@ -21,7 +25,13 @@ func RegisterRPCFuncs(mux *http.ServeMux, funcMap map[string]eth.RPCFunc, logger
 				logger.Error("JSON-RPC2 http request, message with no body received")
 				return
 			}
-			client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256)}
+			client := &Client{
+				hub:     hub,
+				conn:    conn,
+				send:    make(chan []byte, 256),
+				limiter: limiter,
+				ip:      ipResolver.ClientIP(reader),
+			}
 			client.hub.register <- client
 
 			go client.readPump(funcMap, logger)
@@ -38,7 +48,7 @@ func RegisterRPCFuncs(mux *http.ServeMux, funcMap map[string]eth.RPCFunc, logger
 			return
 		}
 
-		outBytes, ethError := handleMessage(body, funcMap, nil)
+		outBytes, ethError := handleMessage(body, funcMap, nil, limiter, ipResolver.ClientIP(reader))
 
 		if ethError != nil {
 			WriteResponse(writer, eth.JsonRpcErrorResponse{
@@ -57,17 +67,38 @@ func RegisterRPCFuncs(mux *http.ServeMux, funcMap map[string]eth.RPCFunc, logger
 	})
 }
 
-func handleMessage(body []byte, funcMap map[string]eth.RPCFunc, conn *websocket.Conn) ([]byte, *eth.Error) {
+// handleMessage processes a single JSON-RPC request, or a batch of requests, received from the
+// client with the given IP.
+func handleMessage(
+	body []byte, funcMap map[string]eth.RPCFunc, conn *websocket.Conn, limiter *eth.RequestLimiter, ip string,
+) ([]byte, *eth.Error) {
 	requestList, isBatch, reqListErr := getRequests(body)
 
 	if reqListErr != nil {
 		return nil, reqListErr
 	}
 
+	if isBatch {
+		if jsonErr := limiter.CheckBatchSize(len(requestList)); jsonErr != nil {
+			return nil, jsonErr
+		}
+	}
+
 	outputList := []interface{}{}
 
 	for _, jsonRequest := range requestList {
-		method, jsonErr := getRequest(jsonRequest, funcMap)
+		if jsonRequest.parseErr != nil {
+			outputList = append(outputList, eth.JsonRpcErrorResponse{
+				Version: "2.0",
+				Error:   *jsonRequest.parseErr,
+			})
+			continue
+		}
+
+		method, jsonErr := getRequest(jsonRequest.JsonRpcRequest, funcMap)
+		if jsonErr == nil {
+			jsonErr = limiter.Allow(ip, jsonRequest.Method)
+		}
 		if jsonErr != nil {
 			outputList = append(outputList, eth.JsonRpcErrorResponse{
 				Version: "2.0",
@@ -77,7 +108,7 @@ func handleMessage(body []byte, funcMap map[string]eth.RPCFunc, conn *websocket.
 			continue
 		}
 
-		rawResult, jsonErr := method.UnmarshalParamsAndCall(jsonRequest, conn)
+		rawResult, jsonErr := method.UnmarshalParamsAndCall(jsonRequest.JsonRpcRequest, conn)
 
 		if jsonErr != nil {
 			outputList = append(outputList, eth.JsonRpcErrorResponse{
@@ -115,10 +146,16 @@ func handleMessage(body []byte, funcMap map[string]eth.RPCFunc, conn *websocket.
 	return outBytes, nil
 }
 
-func getRequests(message []byte) ([]eth.JsonRpcRequest, bool, *eth.Error) {
-	var isBatchRequest bool = true
-	var inputList []eth.JsonRpcRequest
-	if err := json.Unmarshal(message, &inputList); err != nil {
+// batchRequest is a single request from a JSON-RPC batch, parseErr will be set if the request
+// couldn't be unmarshalled, in which case the rest of the batch should still be processed.
+type batchRequest struct {
+	eth.JsonRpcRequest
+	parseErr *eth.Error
+}
+
+func getRequests(message []byte) ([]batchRequest, bool, *eth.Error) {
+	trimmed := bytes.TrimLeft(message, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '[' {
 		var singleInput eth.JsonRpcRequest
 		if err := json.Unmarshal(message, &singleInput); err != nil {
 			return nil, false, eth.NewErrorf(
@@ -126,13 +163,34 @@ func getRequests(message []byte) ([]eth.JsonRpcRequest, bool, *eth.Error) {
 				"Invalid request",
 				"error  unmarshalling message body %v", err,
 			)
-		} else {
-			isBatchRequest = false
-			inputList = append(inputList, singleInput)
 		}
+		return []batchRequest{{JsonRpcRequest: singleInput}}, false, nil
+	}
+
+	var rawList []json.RawMessage
+	if err := json.Unmarshal(message, &rawList); err != nil {
+		return nil, true, eth.NewErrorf(
+			eth.EcParseError,
+			"Parse error",
+			"error unmarshalling batch %v", err,
+		)
+	}
+	// The JSON-RPC 2.0 spec requires an empty batch to be rejected with a single error response.
+	if len(rawList) == 0 {
+		return nil, true, eth.NewError(eth.EcInvalidRequest, "Invalid request", "empty batch")
 	}
 
-	return inputList, isBatchRequest, nil
+	inputList := make([]batchRequest, len(rawList))
+	for i, rawInput := range rawList {
+		if err := json.Unmarshal(rawInput, &inputList[i].JsonRpcRequest); err != nil {
+			inputList[i].parseErr = eth.NewErrorf(
+				eth.EcInvalidRequest,
+				"Invalid request",
+				"error unmarshalling batch request %d: %v", i, err,
+			)
+		}
+	}
+	return inputList, true, nil
 }
 
 func isWebSocketConnection(req *http.Request) bool {
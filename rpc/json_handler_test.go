@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"encoding/json"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
@@ -34,6 +36,7 @@ var (
 		{"eth_getCode", "EthGetCode", ``},
 		{"eth_call", "EthCall", ``},
 		{"eth_getLogs", "EthGetLogs", ``},
+		{"loom_getLogsPaged", "EthGetLogsPaged", ``},
 		{"eth_getBlockTransactionCountByNumber", "EthGetBlockTransactionCountByNumber", ``},
 		{"eth_getBlockTransactionCountByHash", "EthGetBlockTransactionCountByHash", ``},
 		{"eth_getTransactionByBlockHashAndIndex", "EthGetTransactionByBlockHashAndIndex", ``},
@@ -61,6 +64,8 @@ func TestJsonRpcHandler(t *testing.T) {
 
 	t.Run("Http JSON-RPC", testHttpJsonHandler)
 	t.Run("Http JSON-RPC batch", testBatchHttpJsonHandler)
+	t.Run("Http JSON-RPC batch limits", testBatchHttpJsonHandlerLimits)
+	t.Run("Http JSON-RPC debug methods", testHttpJsonHandlerDebugMethods)
 	t.Run("Multi Websocket JSON-RPC", testMultipleWebsocketConnections)
 	t.Run("Single Websocket JSON-RPC", testSingleWebsocketConnections)
 	t.Run("test eth_subscribe and eth_unsubscribe", testEthSubscribeEthUnSubscribe)
@@ -68,7 +73,7 @@ func TestJsonRpcHandler(t *testing.T) {
 
 func testHttpJsonHandler(t *testing.T) {
 	qs := &MockQueryService{}
-	handler := MakeEthQueryServiceHandler(testlog, nil, createDefaultEthRoutes(qs, "default"))
+	handler := MakeEthQueryServiceHandler(testlog, nil, createDefaultEthRoutes(qs, "default", false), nil)
 
 	for _, test := range tests {
 		payload := `{"jsonrpc":"2.0","method":"` + test.method + `","params":[` + test.params + `],"id":99}`
@@ -82,7 +87,7 @@ func testHttpJsonHandler(t *testing.T) {
 
 func testBatchHttpJsonHandler(t *testing.T) {
 	qs := &MockQueryService{}
-	handler := MakeEthQueryServiceHandler(testlog, nil, createDefaultEthRoutes(qs, "default"))
+	handler := MakeEthQueryServiceHandler(testlog, nil, createDefaultEthRoutes(qs, "default", false), nil)
 
 	blockPayload := "["
 	first := true
@@ -103,6 +108,70 @@ func testBatchHttpJsonHandler(t *testing.T) {
 	}
 }
 
+func testBatchHttpJsonHandlerLimits(t *testing.T) {
+	qs := &MockQueryService{}
+	cfg := eth.DefaultWeb3Config()
+	cfg.MaxBatchRequestSize = 3
+	cfg.MethodRateLimits["eth_getLogs"] = &eth.MethodRateLimit{RequestsPerSecond: 0.001, Burst: 1}
+	handler := MakeEthQueryServiceHandler(testlog, nil, createDefaultEthRoutes(qs, "default", false), cfg)
+
+	send := func(payload string) []byte {
+		req := httptest.NewRequest("POST", "http://localhost/eth", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, 200, rec.Result().StatusCode)
+		return rec.Body.Bytes()
+	}
+
+	// The second eth_getLogs call should be rejected, the invalid request should get an error
+	// response without affecting the rest of the batch.
+	var batchResp []eth.JsonRpcErrorResponse
+	require.NoError(t, json.Unmarshal(send(`[
+		{"jsonrpc":"2.0","method":"eth_getLogs","params":[],"id":1},
+		{"jsonrpc":"2.0","method":"eth_getLogs","params":[],"id":2},
+		5
+	]`), &batchResp))
+	require.Len(t, batchResp, 3)
+	require.Equal(t, eth.ErrorCode(0), batchResp[0].Error.Code)
+	require.Equal(t, eth.EcLimitExceeded, batchResp[1].Error.Code)
+	require.Equal(t, eth.EcInvalidRequest, batchResp[2].Error.Code)
+
+	var resp eth.JsonRpcErrorResponse
+	require.NoError(t, json.Unmarshal(send(`[
+		{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1},
+		{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":2},
+		{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":3},
+		{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":4}
+	]`), &resp))
+	require.Equal(t, eth.EcInvalidRequest, resp.Error.Code)
+
+	require.NoError(t, json.Unmarshal(send(`[]`), &resp))
+	require.Equal(t, eth.EcInvalidRequest, resp.Error.Code)
+}
+
+func testHttpJsonHandlerDebugMethods(t *testing.T) {
+	payload := `{"jsonrpc":"2.0","method":"debug_traceTransaction","params":["0x01"],"id":1}`
+	send := func(handler http.Handler) eth.JsonRpcErrorResponse {
+		req := httptest.NewRequest("POST", "http://localhost/eth", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, 200, rec.Result().StatusCode)
+		var resp eth.JsonRpcErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+
+	// the debug methods aren't served unless they're explicitly enabled
+	qs := &MockQueryService{}
+	resp := send(MakeEthQueryServiceHandler(testlog, nil, createDefaultEthRoutes(qs, "default", false), nil))
+	require.Equal(t, eth.EcMethodNotFound, resp.Error.Code)
+	require.Empty(t, qs.MethodsCalled)
+
+	qs = &MockQueryService{}
+	send(MakeEthQueryServiceHandler(testlog, nil, createDefaultEthRoutes(qs, "default", true), nil))
+	require.Equal(t, []string{"DebugTraceTransaction"}, qs.MethodsCalled)
+}
+
 func testEthSubscribeEthUnSubscribe(t *testing.T) {
 	hub := newHub()
 	go hub.run()
@@ -119,7 +188,7 @@ func testEthSubscribeEthUnSubscribe(t *testing.T) {
 		AuthCfg:          auth.DefaultConfig(),
 		EthSubscriptions: eventHandler.EthSubscriptionSet(),
 	}
-	handler := MakeEthQueryServiceHandler(testlog, hub, createDefaultEthRoutes(qs, "default"))
+	handler := MakeEthQueryServiceHandler(testlog, hub, createDefaultEthRoutes(qs, "default", false), nil)
 
 	dialer := wstest.NewDialer(handler)
 	conn, _, err := dialer.Dial("ws://localhost/eth", nil)
@@ -146,7 +215,7 @@ func testMultipleWebsocketConnections(t *testing.T) {
 	hub := newHub()
 	go hub.run()
 	qs := &MockQueryService{}
-	handler := MakeEthQueryServiceHandler(testlog, hub, createDefaultEthRoutes(qs, "default"))
+	handler := MakeEthQueryServiceHandler(testlog, hub, createDefaultEthRoutes(qs, "default", false), nil)
 
 	conns := []*websocket.Conn{}
 	for _, test := range tests {
@@ -183,7 +252,7 @@ func testSingleWebsocketConnections(t *testing.T) {
 	hub := newHub()
 	go hub.run()
 	qs := &MockQueryService{}
-	handler := MakeEthQueryServiceHandler(testlog, hub, createDefaultEthRoutes(qs, "default"))
+	handler := MakeEthQueryServiceHandler(testlog, hub, createDefaultEthRoutes(qs, "default", false), nil)
 	dialer := wstest.NewDialer(handler)
 	conn, _, err := dialer.Dial("ws://localhost/eth", nil)
 	writeMutex := &sync.Mutex{}
@@ -9,6 +9,8 @@ import (
 	"github.com/loomnetwork/go-loom/plugin/types"
 
 	"github.com/loomnetwork/loomchain/config"
+	"github.com/loomnetwork/loomchain/fnConsensus"
+	"github.com/loomnetwork/loomchain/registry"
 	"github.com/loomnetwork/loomchain/rpc/eth"
 	"github.com/loomnetwork/loomchain/vm"
 )
@@ -25,6 +27,15 @@ func (m *MockQueryService) Query(caller, contract string, query []byte, vmType v
 	return nil, nil
 }
 
+func (m *MockQueryService) QueryAt(
+	caller, contract string, query []byte, height int64, prove bool,
+) (*QueryResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"QueryAt"}, m.MethodsCalled...)
+	return nil, nil
+}
+
 func (m *MockQueryService) Resolve(name string) (string, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -32,7 +43,7 @@ func (m *MockQueryService) Resolve(name string) (string, error) {
 	return "", nil
 }
 
-func (m *MockQueryService) Nonce(key, account string) (uint64, error) {
+func (m *MockQueryService) Nonce(key, account string, lane uint32) (uint64, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	m.MethodsCalled = append([]string{"Nonce"}, m.MethodsCalled...)
@@ -110,6 +121,15 @@ func (m *MockQueryService) EthGetStorageAt(address eth.Data, position string, bl
 	return "", nil
 }
 
+func (m *MockQueryService) EthGetProof(
+	address eth.Data, storageKeys []eth.Data, block eth.BlockHeight,
+) (*eth.JsonAccountProof, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"EthGetProof"}, m.MethodsCalled...)
+	return nil, nil
+}
+
 func (m *MockQueryService) EthCall(query eth.JsonTxCallObject, block eth.BlockHeight) (eth.Data, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -124,6 +144,15 @@ func (m *MockQueryService) EthGetLogs(filter eth.JsonFilter) ([]eth.JsonLog, err
 	return nil, nil
 }
 
+func (m *MockQueryService) EthGetLogsPaged(
+	filter eth.JsonFilter, limit eth.Quantity, cursor string,
+) (*LogsPage, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"EthGetLogsPaged"}, m.MethodsCalled...)
+	return nil, nil
+}
+
 func (m *MockQueryService) EthGetBlockTransactionCountByHash(hash eth.Data) (eth.Quantity, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -265,11 +294,27 @@ func (m *MockQueryService) ContractEvents(
 	return nil, nil
 }
 
+func (m *MockQueryService) ContractEventsPaged(
+	fromBlock, toBlock uint64, contract string, limit int, cursor string,
+) (*ContractEventsPage, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"ContractEventsPaged"}, m.MethodsCalled...)
+	return nil, nil
+}
+
 func (m *MockQueryService) GetContractRecord(addr string) (*types.ContractRecordResponse, error) {
 	m.MethodsCalled = append([]string{"GetcontractRecord"}, m.MethodsCalled...)
 	return nil, nil
 }
 
+func (m *MockQueryService) GetContractVersions(name string) (*registry.NameRecord, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"GetContractVersions"}, m.MethodsCalled...)
+	return nil, nil
+}
+
 func (m *MockQueryService) DPOSTotalStaked() (*DPOSTotalStakedResponse, error) {
 	m.MethodsCalled = append([]string{"DposTotalStaked"}, m.MethodsCalled...)
 	return nil, nil
@@ -280,6 +325,20 @@ func (m *MockQueryService) GetCanonicalTxHash(block, txIndex uint64, evmTxHash e
 	return "", nil
 }
 
+func (m *MockQueryService) FnConsensusStatus() (*fnConsensus.ReactorStatus, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"FnConsensusStatus"}, m.MethodsCalled...)
+	return nil, nil
+}
+
+func (m *MockQueryService) SimulateTx(tx []byte, caller string) (*SimulateTxResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"SimulateTx"}, m.MethodsCalled...)
+	return nil, nil
+}
+
 // deprecated function
 func (m *MockQueryService) EvmTxReceipt(txHash []byte) ([]byte, error) {
 	m.mutex.Lock()
@@ -378,3 +437,28 @@ func (m *MockQueryService) EvmUnSubscribe(id string) (bool, error) {
 	m.MethodsCalled = append([]string{"EvmUnSubscribe"}, m.MethodsCalled...)
 	return true, nil
 }
+
+func (m *MockQueryService) DebugTraceTransaction(hash eth.Data, config *eth.JsonTraceConfig) (interface{}, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"DebugTraceTransaction"}, m.MethodsCalled...)
+	return nil, nil
+}
+
+func (m *MockQueryService) DebugTraceCall(
+	query eth.JsonTxCallObject, block eth.BlockHeight, config *eth.JsonTraceConfig,
+) (interface{}, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"DebugTraceCall"}, m.MethodsCalled...)
+	return nil, nil
+}
+
+func (m *MockQueryService) DebugTraceBlockByNumber(
+	block eth.BlockHeight, config *eth.JsonTraceConfig,
+) ([]eth.JsonTxTraceResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.MethodsCalled = append([]string{"DebugTraceBlockByNumber"}, m.MethodsCalled...)
+	return nil, nil
+}
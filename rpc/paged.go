@@ -0,0 +1,139 @@
+package rpc
+
+import (
+	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain/eth/query"
+	"github.com/loomnetwork/loomchain/rpc/eth"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+)
+
+// maxPageSize is the maximum number of events or logs that can be returned by the paged query methods.
+const maxPageSize = 1000
+
+// ContractEventsPage is returned by the contractevents_paged method.
+type ContractEventsPage struct {
+	Events    []*types.EventData `json:"events"`
+	FromBlock uint64             `json:"fromBlock"`
+	ToBlock   uint64             `json:"toBlock"`
+	// Opaque cursor that should be passed in to fetch the next page, empty if there are no more events.
+	Cursor string `json:"cursor"`
+}
+
+// LogsPage is returned by the loom_getLogsPaged method.
+type LogsPage struct {
+	Logs []eth.JsonLog `json:"logs"`
+	// Opaque cursor that should be passed in to fetch the next page, empty if there are no more logs.
+	Cursor string `json:"cursor"`
+}
+
+// ContractEventsPaged returns up to limit events emitted by the given contract (or by any contract if
+// none is specified) in the given block range, starting from the given cursor. If toBlock is zero the
+// range extends to the latest block.
+func (s *QueryServer) ContractEventsPaged(
+	fromBlock, toBlock uint64, contract string, limit int, cursor string,
+) (*ContractEventsPage, error) {
+	if s.EventStore == nil {
+		return nil, errors.New("event store is not available")
+	}
+	if fromBlock == 0 {
+		return nil, errors.New("fromBlock not specified")
+	}
+	if toBlock == 0 {
+		snapshot := s.StateProvider.ReadOnlyState()
+		toBlock = uint64(snapshot.Block().Height)
+		snapshot.Release()
+	}
+	if toBlock < fromBlock {
+		return nil, errors.New("toBlock must be equal or greater than fromBlock")
+	}
+	limit, err := checkPageLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	from, err := eth.DecLogCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := store.EventFilter{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Contract:  contract,
+	}
+	// Events are keyed by their index within the block, so the tx index isn't needed to resume, and
+	// is left out of the cursors returned by this method.
+	eventCursor := store.EventCursor{BlockHeight: from.Height, EventIndex: uint16(from.LogIndex)}
+	// Fetch an extra event to figure out where the next page starts.
+	events, err := s.EventStore.FilterEventsPaged(filter, eventCursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ContractEventsPage{
+		Events:    make([]*types.EventData, 0, len(events)),
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+	}
+	if len(events) > limit {
+		next := events[limit]
+		page.Cursor = eth.EncLogCursor(eth.LogCursor{
+			Height:   next.Cursor.BlockHeight,
+			LogIndex: uint32(next.Cursor.EventIndex),
+		})
+		events = events[:limit]
+	}
+	for _, event := range events {
+		page.Events = append(page.Events, event.Event)
+	}
+	return page, nil
+}
+
+// EthGetLogsPaged is a paged variant of eth_getLogs, it returns up to limit logs matching the filter
+// starting from the given cursor, along with the cursor that should be used to fetch the next page.
+func (s *QueryServer) EthGetLogsPaged(filter eth.JsonFilter, limit eth.Quantity, cursor string) (*LogsPage, error) {
+	ethFilter, err := eth.DecLogFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	n, err := eth.DecQuantityToUint(limit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid limit %v", limit)
+	}
+	pageLimit, err := checkPageLimit(int(n))
+	if err != nil {
+		return nil, err
+	}
+	from, err := eth.DecLogCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := s.StateProvider.ReadOnlyState()
+	defer snapshot.Release()
+
+	logs, next, err := query.QueryChainPaged(
+		s.BlockStore, snapshot, ethFilter, s.ReceiptHandlerProvider.Reader(), s.EvmAuxStore,
+		s.Web3Cfg.GetLogsMaxBlockRange, s.Web3Cfg.GetLogsMaxIndexedBlockRange, from, pageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	page := &LogsPage{Logs: eth.EncLogs(logs)}
+	if next != nil {
+		page.Cursor = eth.EncLogCursor(*next)
+	}
+	return page, nil
+}
+
+// checkPageLimit returns the max page size if the given limit is zero, or an error if the limit
+// is out of range.
+func checkPageLimit(limit int) (int, error) {
+	if limit == 0 {
+		return maxPageSize, nil
+	}
+	if limit < 0 || limit > maxPageSize {
+		return 0, errors.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	return limit, nil
+}
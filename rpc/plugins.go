@@ -0,0 +1,33 @@
+package rpc
+
+import (
+	"github.com/pkg/errors"
+	rpcserver "github.com/tendermint/tendermint/rpc/lib/server"
+
+	"github.com/loomnetwork/loomchain/plugin"
+)
+
+// ReloadPluginsResult is returned by the unsafe_reload_plugins RPC method.
+type ReloadPluginsResult struct {
+	// Names of the plugins that were unloaded, these will be reloaded from the updated executables
+	// the next time they're used.
+	Unloaded []string
+}
+
+// addPluginRoutes adds the unsafe RPC routes used to manage external plugins at runtime.
+func addPluginRoutes(routes map[string]*rpcserver.RPCFunc, loader plugin.ReloadableLoader) {
+	routes["unsafe_list_plugins"] = rpcserver.NewRPCFunc(func() ([]*plugin.ExternalPluginInfo, error) {
+		plugins, err := loader.ListPlugins()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list plugins")
+		}
+		return plugins, nil
+	}, "")
+	routes["unsafe_reload_plugins"] = rpcserver.NewRPCFunc(func() (*ReloadPluginsResult, error) {
+		unloaded, err := loader.ReloadPlugins()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to reload plugins")
+		}
+		return &ReloadPluginsResult{Unloaded: unloaded}, nil
+	}, "")
+}
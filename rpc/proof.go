@@ -0,0 +1,161 @@
+package rpc
+
+import (
+	"math/big"
+
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/loomchain"
+	levm "github.com/loomnetwork/loomchain/evm"
+	"github.com/loomnetwork/loomchain/rpc/eth"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// ProvableStateProvider is implemented by state providers that can generate Merkle proofs of the
+// application state at previous block heights.
+type ProvableStateProvider interface {
+	// ProvableStateAt returns a snapshot of the app state at the end of the block at the given height
+	// that can generate proofs for all the keys read from it.
+	ProvableStateAt(height int64, header abci.Header) (*loomchain.ProvableState, error)
+}
+
+// QueryResult is returned by the query_at method.
+type QueryResult struct {
+	// Height of the block at the end of which the query was executed, the proofs can be verified
+	// against the app hash in the header of the block at Height + 1.
+	Height int64
+	Result []byte
+	// Proofs of all the keys that were read from the app state while the query was executed (including
+	// the contract registry & address mapper lookups), only set if a proof was requested.
+	Proofs []*store.KeyProof
+}
+
+// QueryAt queries a Go contract against the app state at the end of the block at the given height,
+// if the height is zero the latest state is queried. If prove is true the result will also contain
+// IAVL proofs for all the app state the query result is derived from.
+func (s *QueryServer) QueryAt(caller, contract string, query []byte, height int64, prove bool) (*QueryResult, error) {
+	callerAddr, contractAddr, err := s.parseQueryAddresses(caller, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	if height < 0 {
+		return nil, errors.Errorf("invalid block height %d", height)
+	}
+	if height == 0 {
+		snapshot := s.StateProvider.ReadOnlyState()
+		height = snapshot.Block().Height
+		snapshot.Release()
+	}
+
+	if !prove {
+		snapshot, err := s.snapshotAt(eth.BlockHeight(eth.EncInt(height)))
+		if err != nil {
+			return nil, err
+		}
+		defer snapshot.Release()
+		result, err := s.queryPlugin(snapshot, callerAddr, contractAddr, query)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryResult{Height: height, Result: result}, nil
+	}
+
+	psp, ok := s.StateProvider.(ProvableStateProvider)
+	if !ok {
+		return nil, errors.New("state proofs are not supported by this node")
+	}
+	snapshot, err := psp.ProvableStateAt(height, s.blockHeaderAt(height))
+	if err != nil {
+		if errors.Cause(err) == store.ErrVersionNotFound {
+			return nil, errors.Wrapf(ErrStatePruned, "height %d", height)
+		}
+		return nil, err
+	}
+	defer snapshot.Release()
+
+	result, err := s.queryPlugin(snapshot, callerAddr, contractAddr, query)
+	if err != nil {
+		return nil, err
+	}
+	proofs, err := snapshot.Prove()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate state proofs")
+	}
+	return &QueryResult{Height: height, Result: result, Proofs: proofs}, nil
+}
+
+// EthGetProof returns the account & storage values of the given EVM account, along with Merkle
+// proofs of those values against the EVM state root at the given block height.
+// https://github.com/ethereum/EIPs/blob/master/EIPS/eip-1186.md
+func (s *QueryServer) EthGetProof(
+	address eth.Data, storageKeys []eth.Data, block eth.BlockHeight,
+) (*eth.JsonAccountProof, error) {
+	addr, err := eth.DecDataToAddress(s.ChainID, address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[eth_getProof] invalid address %v", address)
+	}
+	keys := make([][]byte, 0, len(storageKeys))
+	for _, storageKey := range storageKeys {
+		key, err := eth.DecDataToBytes(storageKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[eth_getProof] invalid storage key %v", storageKey)
+		}
+		keys = append(keys, key)
+	}
+
+	snapshot, err := s.snapshotAt(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "[eth_getProof]")
+	}
+	defer snapshot.Release()
+
+	proof, err := levm.GetAccountProof(snapshot, addr, keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "[eth_getProof]")
+	}
+
+	resp := &eth.JsonAccountProof{
+		Address:      eth.EncBytes(proof.Address),
+		AccountProof: eth.EncBytesArray(proof.AccountProof),
+		Balance:      eth.EncBigInt(*proof.Balance),
+		CodeHash:     eth.EncBytes(proof.CodeHash),
+		Nonce:        eth.EncUint(proof.Nonce),
+		StorageHash:  eth.EncBytes(proof.StorageHash),
+		StorageProof: make([]eth.JsonStorageProof, 0, len(proof.StorageProof)),
+	}
+	for _, sp := range proof.StorageProof {
+		resp.StorageProof = append(resp.StorageProof, eth.JsonStorageProof{
+			Key:   eth.EncBytes(sp.Key),
+			Value: eth.EncBigInt(*new(big.Int).SetBytes(sp.Value)),
+			Proof: eth.EncBytesArray(sp.Proof),
+		})
+	}
+	return resp, nil
+}
+
+// parseQueryAddresses parses the caller & contract addresses passed into the query methods, the root
+// address is used if the caller is empty.
+func (s *QueryServer) parseQueryAddresses(caller, contract string) (loom.Address, loom.Address, error) {
+	var callerAddr loom.Address
+	var err error
+	if len(caller) == 0 {
+		callerAddr = loom.RootAddress(s.ChainID)
+	} else {
+		callerAddr, err = loom.ParseAddress(caller)
+		if err != nil {
+			return loom.Address{}, loom.Address{}, err
+		}
+	}
+
+	localContractAddr, err := decodeHexAddress(contract)
+	if err != nil {
+		return loom.Address{}, loom.Address{}, err
+	}
+	contractAddr := loom.Address{
+		ChainID: s.ChainID,
+		Local:   localContractAddr,
+	}
+	return callerAddr, contractAddr, nil
+}
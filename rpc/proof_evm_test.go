@@ -0,0 +1,55 @@
+// +build evm
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/loomchain"
+	levm "github.com/loomnetwork/loomchain/evm"
+	"github.com/loomnetwork/loomchain/rpc/eth"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestEthGetProof(t *testing.T) {
+	state := loomchain.NewStoreState(
+		nil, store.NewMemStore(), abci.Header{ChainID: "default", Height: 10}, nil, nil,
+	)
+	caller := loom.MustParseAddress("default:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+	// PUSH1 0x2a PUSH1 0 SSTORE PUSH1 1 PUSH1 0 RETURN, stores 42 in slot 0 & deploys a single STOP
+	initCode := common.FromHex("0x602a60005560016000f3")
+	evm := levm.NewLoomVm(state, nil, nil, nil, false)
+	_, contractAddr, err := evm.Create(caller, initCode, loom.NewBigUIntFromInt(0))
+	require.NoError(t, err)
+
+	qs := &QueryServer{
+		ChainID:       "default",
+		StateProvider: &fixedStateProvider{state: state},
+	}
+	slot0 := eth.EncBytes(common.BigToHash(common.Big0).Bytes())
+	slot1 := eth.EncBytes(common.BigToHash(common.Big1).Bytes())
+	proof, err := qs.EthGetProof(eth.EncBytes(contractAddr.Local), []eth.Data{slot0, slot1}, "latest")
+	require.NoError(t, err)
+	require.Equal(t, eth.EncBytes(contractAddr.Local), proof.Address)
+	require.Equal(t, eth.EncBytes(crypto.Keccak256([]byte{0})), proof.CodeHash)
+	require.Equal(t, eth.Quantity("0x0"), proof.Balance)
+	require.NotEmpty(t, proof.AccountProof)
+	require.Len(t, proof.StorageProof, 2)
+	require.Equal(t, slot0, proof.StorageProof[0].Key)
+	require.Equal(t, eth.Quantity("0x2a"), proof.StorageProof[0].Value)
+	require.NotEmpty(t, proof.StorageProof[0].Proof)
+	require.Equal(t, eth.Quantity("0x0"), proof.StorageProof[1].Value)
+
+	_, err = qs.EthGetProof("0x1234", nil, "latest")
+	require.Error(t, err)
+	_, err = qs.EthGetProof(eth.EncBytes(contractAddr.Local), []eth.Data{"1234"}, "latest")
+	require.Error(t, err)
+	// the state of blocks that haven't been committed yet isn't available
+	_, err = qs.EthGetProof(eth.EncBytes(contractAddr.Local), nil, "0xb")
+	require.Error(t, err)
+}
@@ -35,9 +35,11 @@ import (
 	"github.com/loomnetwork/loomchain/eth/subs"
 	"github.com/loomnetwork/loomchain/eth/utils"
 	levm "github.com/loomnetwork/loomchain/evm"
+	"github.com/loomnetwork/loomchain/fnConsensus"
 	"github.com/loomnetwork/loomchain/log"
 	lcp "github.com/loomnetwork/loomchain/plugin"
 	hsmpv "github.com/loomnetwork/loomchain/privval/hsm"
+	remotepv "github.com/loomnetwork/loomchain/privval/remote"
 	"github.com/loomnetwork/loomchain/receipts/common"
 	"github.com/loomnetwork/loomchain/registry"
 	registryFac "github.com/loomnetwork/loomchain/registry/factory"
@@ -64,6 +66,17 @@ type StateProvider interface {
 	ReadOnlyState() loomchain.State
 }
 
+// HistoricalStateProvider is implemented by state providers that retain the application state at
+// previous block heights.
+type HistoricalStateProvider interface {
+	// ReadOnlyStateAt returns a snapshot of the app state at the end of the block at the given height.
+	ReadOnlyStateAt(height int64, header abci.Header) (loomchain.State, error)
+}
+
+// ErrStatePruned is returned when a query targets a block height for which the app state is no
+// longer retained by the node.
+var ErrStatePruned = errors.New("state at the requested block height has been pruned")
+
 // QueryServer provides the ability to query the current state of the DAppChain via RPC.
 //
 // Contract state can be queried via:
@@ -115,6 +128,8 @@ type StateProvider interface {
 //     "id": "123456789"
 //   }
 // - POST request to "/nonce" endpoint with form-encoded key param.
+// The optional lane param can be used to obtain the nonce of a signer in a particular nonce lane,
+// if it's omitted the nonce in the default lane is returned.
 type QueryServer struct {
 	StateProvider
 	ChainID                string
@@ -136,6 +151,12 @@ type QueryServer struct {
 	Web3Cfg           *eth.Web3Config
 	totalStakedAmount *totalStakedAmount
 	DPOSCfg           *config.DPOSConfig
+	// If this is nil the debug_trace* methods won't be available.
+	TxReplayer TxReplayer
+	// If this is nil the simulate method won't be available.
+	TxSimulator TxSimulator
+	// If this is nil the fnConsensus reactor is assumed to be disabled.
+	FnConsensusReactor *fnConsensus.FnConsensusReactor
 }
 
 type totalStakedAmount struct {
@@ -148,25 +169,10 @@ var _ QueryService = &QueryServer{}
 // Query returns data of given contract from the application states
 // The contract parameter should be a hex-encoded local address prefixed by 0x
 func (s *QueryServer) Query(caller, contract string, query []byte, vmType vm.VMType) ([]byte, error) {
-	var callerAddr loom.Address
-	var err error
-	if len(caller) == 0 {
-		callerAddr = loom.RootAddress(s.ChainID)
-	} else {
-		callerAddr, err = loom.ParseAddress(caller)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	localContractAddr, err := decodeHexAddress(contract)
+	callerAddr, contractAddr, err := s.parseQueryAddresses(caller, contract)
 	if err != nil {
 		return nil, err
 	}
-	contractAddr := loom.Address{
-		ChainID: s.ChainID,
-		Local:   localContractAddr,
-	}
 
 	snapshot := s.StateProvider.ReadOnlyState()
 	defer snapshot.Release()
@@ -207,6 +213,11 @@ func (s *QueryServer) QueryEnv() (*config.EnvInfo, error) {
 		HsmEnabled: cfg.HsmConfig.HsmEnabled,
 		HsmDevType: cfg.HsmConfig.HsmDevType,
 	}
+	if cfg.RemoteSigner != nil {
+		cfg.RemoteSigner = &remotepv.RemoteSignerConfig{
+			Enabled: cfg.RemoteSigner.Enabled,
+		}
+	}
 
 	envInfo := config.EnvInfo{
 		Env:         envir,
@@ -284,7 +295,10 @@ func (s *QueryServer) queryEvm(state loomchain.State, caller, contract loom.Addr
 
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_call
 func (s *QueryServer) EthCall(query eth.JsonTxCallObject, block eth.BlockHeight) (resp eth.Data, err error) {
-	snapshot := s.StateProvider.ReadOnlyState()
+	snapshot, err := s.snapshotAt(block)
+	if err != nil {
+		return resp, errors.Wrap(err, "[eth_call]")
+	}
 	defer snapshot.Release()
 
 	var caller loom.Address
@@ -309,6 +323,57 @@ func (s *QueryServer) EthCall(query eth.JsonTxCallObject, block eth.BlockHeight)
 	return eth.EncBytes(bytes), err
 }
 
+// snapshotAt returns a read-only snapshot of the app state at the end of the block at the given
+// height, the latest state is returned if the block height is empty, "latest", or "pending".
+// The caller is responsible for releasing the snapshot.
+func (s *QueryServer) snapshotAt(block eth.BlockHeight) (loomchain.State, error) {
+	snapshot := s.StateProvider.ReadOnlyState()
+	if block == "" || block == "latest" || block == "pending" {
+		return snapshot, nil
+	}
+
+	latestHeight := snapshot.Block().Height
+	height, err := eth.DecBlockHeight(latestHeight, block)
+	if err != nil {
+		snapshot.Release()
+		return nil, errors.Wrapf(err, "invalid block height %s", block)
+	}
+	if int64(height) == latestHeight {
+		return snapshot, nil
+	}
+	snapshot.Release()
+	if int64(height) > latestHeight {
+		return nil, errors.Errorf("block height %d exceeds latest block height %d", height, latestHeight)
+	}
+
+	hsp, ok := s.StateProvider.(HistoricalStateProvider)
+	if !ok {
+		return nil, errors.Errorf("state at height %d is not retained by this node", height)
+	}
+
+	h := int64(height)
+	state, err := hsp.ReadOnlyStateAt(h, s.blockHeaderAt(h))
+	if err != nil {
+		if errors.Cause(err) == store.ErrVersionNotFound {
+			return nil, errors.Wrapf(ErrStatePruned, "height %d", h)
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+// blockHeaderAt returns the header of the block at the given height, or a minimal header if the block
+// can't be loaded from the block store.
+func (s *QueryServer) blockHeaderAt(height int64) abci.Header {
+	header := abci.Header{ChainID: s.ChainID, Height: height}
+	if s.BlockStore != nil {
+		if blockResult, err := s.BlockStore.GetBlockByHeight(&height); err == nil {
+			header = abciHeaderFromBlock(blockResult)
+		}
+	}
+	return header
+}
+
 // GetCode returns the runtime byte-code of a contract running on a DAppChain's EVM.
 // Gives an error for non-EVM contracts.
 // contract - address of the contract in the form of a string. (Use loom.Address.String() to convert)
@@ -379,10 +444,11 @@ func (s *QueryServer) createStaticContractCtx(state loomchain.State, name string
 	return ctx, nil
 }
 
-// Nonce returns the nonce of the last committed tx sent by the given account.
+// Nonce returns the nonce of the last committed tx sent by the given account in the given nonce lane,
+// lane zero is the default lane used by clients that don't specify a lane.
 // NOTE: Either the key or the account must be provided. The account (if not empty) is used in
 //       preference to the key.
-func (s *QueryServer) Nonce(key, account string) (uint64, error) {
+func (s *QueryServer) Nonce(key, account string, lane uint32) (uint64, error) {
 	var addr loom.Address
 
 	if key != "" && account == "" {
@@ -412,7 +478,7 @@ func (s *QueryServer) Nonce(key, account string) (uint64, error) {
 		return 0, errors.Wrap(err, "failed to resolve account address")
 	}
 
-	return auth.Nonce(snapshot, resolvedAddr), nil
+	return auth.NonceOnLane(snapshot, resolvedAddr, lane), nil
 }
 
 func (s *QueryServer) Resolve(name string) (string, error) {
@@ -609,6 +675,18 @@ func (s *QueryServer) GetContractRecord(contractAddrStr string) (*types.Contract
 	return k, nil
 }
 
+// GetContractVersions returns the owner & version history of the named contract.
+func (s *QueryServer) GetContractVersions(name string) (*registry.NameRecord, error) {
+	snapshot := s.StateProvider.ReadOnlyState()
+	defer snapshot.Release()
+
+	reg, ok := s.CreateRegistry(snapshot).(registry.VersionedRegistry)
+	if !ok {
+		return nil, errors.New("contract registry doesn't support versioning")
+	}
+	return reg.GetNameRecord(name)
+}
+
 type DPOSTotalStakedResponse struct {
 	TotalStaked *gtypes.BigUInt
 }
@@ -640,6 +718,14 @@ func (s *QueryServer) DPOSTotalStaked() (*DPOSTotalStakedResponse, error) {
 	}, nil
 }
 
+// FnConsensusStatus returns the current state of the fnConsensus reactor running on this node.
+func (s *QueryServer) FnConsensusStatus() (*fnConsensus.ReactorStatus, error) {
+	if s.FnConsensusReactor == nil {
+		return nil, errors.New("fnConsensus reactor is not enabled on this node")
+	}
+	return s.FnConsensusReactor.Status(), nil
+}
+
 // GetCanonicalTxHash returns the hash of the Tendermint tx payload within a block.
 // If the block number is specified (non-zero) then the tx payload will be found by block number & tx
 // index. Otherwise the EVM tx hash will be used to lookup the receipt for the tx and the block
@@ -1024,7 +1110,7 @@ func (s *QueryServer) EthGetLogs(filter eth.JsonFilter) (resp []eth.JsonLog, err
 	//       block store.
 	logs, err := query.QueryChain(
 		s.BlockStore, snapshot, ethFilter, s.ReceiptHandlerProvider.Reader(), s.EvmAuxStore,
-		s.Web3Cfg.GetLogsMaxBlockRange,
+		s.Web3Cfg.GetLogsMaxBlockRange, s.Web3Cfg.GetLogsMaxIndexedBlockRange,
 	)
 	if err != nil {
 		return resp, err
@@ -1128,6 +1214,7 @@ func (s *QueryServer) EthGetTransactionCount(address eth.Data, block eth.BlockHe
 		return eth.ZeroedQuantity, errors.New("transaction count only available for the latest block")
 	}
 
+	// Eth txs don't specify a nonce lane, so only the nonce in the default lane is relevant to web3 clients.
 	return eth.EncUint(auth.Nonce(snapshot, resolvedAddr)), nil
 }
 
@@ -1139,15 +1226,11 @@ func (s *QueryServer) EthGetBalance(address eth.Data, block eth.BlockHeight) (et
 		return "", errors.Wrapf(err, "decoding input address parameter %v", address)
 	}
 
-	snapshot := s.StateProvider.ReadOnlyState()
-	defer snapshot.Release()
-	height, err := eth.DecBlockHeight(snapshot.Block().Height, block)
+	snapshot, err := s.snapshotAt(block)
 	if err != nil {
-		return "", errors.Wrapf(err, "invalid block height %s", block)
-	}
-	if int64(height) != snapshot.Block().Height {
-		return "", errors.Errorf("height %s not latest", block)
+		return "", err
 	}
+	defer snapshot.Release()
 
 	ctx, err := s.createStaticContractCtx(snapshot, "ethcoin")
 	if err != nil {
@@ -1174,20 +1257,11 @@ func (s *QueryServer) EthGetStorageAt(local eth.Data, position string, block eth
 		return "", errors.Wrapf(err, "failed to decode address parameter %v", local)
 	}
 
-	snapshot := s.StateProvider.ReadOnlyState()
-	defer snapshot.Release()
-
-	if block == "" {
-		block = "latest"
-	}
-
-	height, err := eth.DecBlockHeight(snapshot.Block().Height, block)
+	snapshot, err := s.snapshotAt(block)
 	if err != nil {
-		return "", errors.Wrapf(err, "invalid block height %s", block)
-	}
-	if int64(height) != snapshot.Block().Height {
-		return "", errors.Wrapf(err, "unable to get storage at height %v", block)
+		return "", err
 	}
+	defer snapshot.Release()
 
 	evm := levm.NewLoomVm(snapshot, nil, nil, nil, false)
 	storage, err := evm.GetStorageAt(address, ethcommon.HexToHash(position).Bytes())
@@ -11,8 +11,10 @@ import (
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	proto "github.com/gogo/protobuf/proto"
+	"github.com/loomnetwork/go-loom"
 	lp "github.com/loomnetwork/go-loom/plugin"
 	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/go-loom/util"
 	"github.com/loomnetwork/loomchain"
 	"github.com/loomnetwork/loomchain/auth"
 	"github.com/loomnetwork/loomchain/eth/subs"
@@ -101,8 +103,80 @@ func (s *stateProvider) ReadOnlyState() loomchain.State {
 	)
 }
 
+// Always returns the same state.
+type fixedStateProvider struct {
+	state loomchain.State
+}
+
+func (s *fixedStateProvider) ReadOnlyState() loomchain.State {
+	return s.state
+}
+
 var testlog llog.TMLogger
 
+// Retains the state at a subset of block heights.
+type historicalStateProvider struct {
+	ChainID        string
+	Height         int64
+	RetainedHeight map[int64]bool
+}
+
+func (s *historicalStateProvider) ReadOnlyState() loomchain.State {
+	return loomchain.NewStoreState(
+		nil,
+		store.NewMemStore(),
+		abci.Header{
+			ChainID: s.ChainID,
+			Height:  s.Height,
+		},
+		nil,
+		nil,
+	)
+}
+
+func (s *historicalStateProvider) ReadOnlyStateAt(height int64, header abci.Header) (loomchain.State, error) {
+	if !s.RetainedHeight[height] {
+		return nil, store.ErrVersionNotFound
+	}
+	return loomchain.NewStoreState(nil, store.NewMemStore(), header, nil, nil), nil
+}
+
+func TestQueryServerSnapshotAt(t *testing.T) {
+	qs := &QueryServer{
+		ChainID: "default",
+		StateProvider: &historicalStateProvider{
+			ChainID:        "default",
+			Height:         10,
+			RetainedHeight: map[int64]bool{5: true},
+		},
+	}
+
+	snapshot, err := qs.snapshotAt("latest")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), snapshot.Block().Height)
+
+	snapshot, err = qs.snapshotAt("0xa")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), snapshot.Block().Height)
+
+	snapshot, err = qs.snapshotAt("0x5")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), snapshot.Block().Height)
+
+	// the state of blocks that haven't been committed yet isn't available
+	_, err = qs.snapshotAt("0xb")
+	require.Error(t, err)
+
+	_, err = qs.snapshotAt("0x3")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrStatePruned.Error())
+
+	// only the latest state is available if the state provider doesn't retain historical state
+	qs.StateProvider = &stateProvider{ChainID: "default"}
+	_, err = qs.snapshotAt("0x5")
+	require.Error(t, err)
+}
+
 func TestQueryServer(t *testing.T) {
 	llog.Setup("debug", "file://-")
 	testlog = llog.Root.With("module", "query-server")
@@ -172,13 +246,22 @@ func testQueryServerContractQuery(t *testing.T) {
 }
 
 func testQueryServerNonce(t *testing.T) {
+	pubKey := "441B9DCC47A734695A508EDF174F7AAF76DD7209DEA2D51D3582DA77CE2756BE"
+	account := "default:0xb16a379ec18d4093666f8f38b11a3071c920207d"
+
+	// the account has sent one tx on the default lane, and two txs on lane 7
+	state := loomchain.NewStoreState(nil, store.NewMemStore(), abci.Header{ChainID: "default"}, nil, nil)
+	addr := loom.MustParseAddress(account)
+	loomchain.NewSequence(util.PrefixKey([]byte("nonce"), addr.Bytes())).Next(state)
+	laneKey := util.PrefixKey([]byte("lane_nonce"), addr.Bytes(), []byte{0, 0, 0, 7})
+	loomchain.NewSequence(laneKey).Next(state)
+	loomchain.NewSequence(laneKey).Next(state)
+
 	var qs QueryService = &QueryServer{
-		ChainID: "default",
-		StateProvider: &stateProvider{
-			ChainID: "default",
-		},
-		BlockStore: store.NewMockBlockStore(),
-		AuthCfg:    auth.DefaultConfig(),
+		ChainID:       "default",
+		StateProvider: &fixedStateProvider{state: state},
+		BlockStore:    store.NewMockBlockStore(),
+		AuthCfg:       auth.DefaultConfig(),
 	}
 	bus := &QueryEventBus{
 		Subs:    *loomchain.NewSubscriptionSet(),
@@ -190,9 +273,6 @@ func testQueryServerNonce(t *testing.T) {
 	// give the server some time to spin up
 	time.Sleep(100 * time.Millisecond)
 
-	pubKey := "441B9DCC47A734695A508EDF174F7AAF76DD7209DEA2D51D3582DA77CE2756BE"
-	account := "default:0xb16a379ec18d4093666f8f38b11a3071c920207d"
-
 	// Query for nonce using public key
 	_, err := http.Get(fmt.Sprintf("%s/nonce?key=\"%s\"", ts.URL, pubKey))
 	require.NoError(t, err)
@@ -216,6 +296,23 @@ func testQueryServerNonce(t *testing.T) {
 
 	_, err = rpcClient.Call("nonce", map[string]interface{}{"key": pubKey, "account": account}, &result)
 	require.NoError(t, err)
+	require.Equal(t, uint64(1), result)
+
+	// Query for nonce in a particular nonce lane
+	_, err = http.Get(fmt.Sprintf("%s/nonce?account=\"%s\"&lane=7", ts.URL, account))
+	require.NoError(t, err)
+
+	_, err = rpcClient.Call("nonce", map[string]interface{}{"account": account, "lane": 7}, &result)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result)
+
+	_, err = rpcClient.Call("nonce", map[string]interface{}{"account": account, "lane": 0}, &result)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), result)
+
+	_, err = rpcClient.Call("nonce", map[string]interface{}{"account": account, "lane": 8}, &result)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), result)
 }
 
 func testQueryMetric(t *testing.T) {
@@ -16,7 +16,10 @@ import (
 	"github.com/loomnetwork/loomchain"
 	"github.com/loomnetwork/loomchain/config"
 	"github.com/loomnetwork/loomchain/eth/subs"
+	"github.com/loomnetwork/loomchain/fnConsensus"
 	"github.com/loomnetwork/loomchain/log"
+	"github.com/loomnetwork/loomchain/plugin"
+	"github.com/loomnetwork/loomchain/registry"
 	"github.com/loomnetwork/loomchain/rpc/eth"
 	"github.com/loomnetwork/loomchain/vm"
 )
@@ -24,8 +27,9 @@ import (
 // QueryService provides necessary methods for the client to query application states
 type QueryService interface {
 	Query(caller, contract string, query []byte, vmType vm.VMType) ([]byte, error)
+	QueryAt(caller, contract string, query []byte, height int64, prove bool) (*QueryResult, error)
 	Resolve(name string) (string, error)
-	Nonce(key, account string) (uint64, error)
+	Nonce(key, account string, lane uint32) (uint64, error)
 	Subscribe(wsCtx rpctypes.WSRPCContext, topics []string) (*WSEmptyResult, error)
 	UnSubscribe(wsCtx rpctypes.WSRPCContext, topics string) (*WSEmptyResult, error)
 	QueryEnv() (*config.EnvInfo, error)
@@ -37,8 +41,10 @@ type QueryService interface {
 	EthGetTransactionByHash(hash eth.Data) (eth.JsonTxObject, error)
 	EthGetCode(address eth.Data, block eth.BlockHeight) (eth.Data, error)
 	EthGetStorageAt(address eth.Data, position string, block eth.BlockHeight) (eth.Data, error)
+	EthGetProof(address eth.Data, storageKeys []eth.Data, block eth.BlockHeight) (*eth.JsonAccountProof, error)
 	EthCall(query eth.JsonTxCallObject, block eth.BlockHeight) (eth.Data, error)
 	EthGetLogs(filter eth.JsonFilter) ([]eth.JsonLog, error)
+	EthGetLogsPaged(filter eth.JsonFilter, limit eth.Quantity, cursor string) (*LogsPage, error)
 	EthGetBlockTransactionCountByHash(hash eth.Data) (eth.Quantity, error)
 	EthGetBlockTransactionCountByNumber(block eth.BlockHeight) (eth.Quantity, error)
 	EthGetTransactionByBlockHashAndIndex(hash eth.Data, index eth.Quantity) (eth.JsonTxObject, error)
@@ -61,10 +67,20 @@ type QueryService interface {
 	EthGetTransactionCount(local eth.Data, block eth.BlockHeight) (eth.Quantity, error)
 	EthAccounts() ([]eth.Data, error)
 
+	DebugTraceTransaction(hash eth.Data, config *eth.JsonTraceConfig) (interface{}, error)
+	DebugTraceCall(query eth.JsonTxCallObject, block eth.BlockHeight, config *eth.JsonTraceConfig) (interface{}, error)
+	DebugTraceBlockByNumber(block eth.BlockHeight, config *eth.JsonTraceConfig) ([]eth.JsonTxTraceResult, error)
+
 	ContractEvents(fromBlock uint64, toBlock uint64, contract string) (*types.ContractEventsResult, error)
+	ContractEventsPaged(
+		fromBlock, toBlock uint64, contract string, limit int, cursor string,
+	) (*ContractEventsPage, error)
 	GetContractRecord(contractAddr string) (*types.ContractRecordResponse, error)
+	GetContractVersions(name string) (*registry.NameRecord, error)
 	DPOSTotalStaked() (*DPOSTotalStakedResponse, error)
 	GetCanonicalTxHash(block, txIndex uint64, evmTxHash eth.Data) (eth.Data, error)
+	FnConsensusStatus() (*fnConsensus.ReactorStatus, error)
+	SimulateTx(tx []byte, caller string) (*SimulateTxResponse, error)
 
 	// deprecated function
 	EvmTxReceipt(txHash []byte) ([]byte, error)
@@ -111,8 +127,9 @@ func MakeQueryServiceHandler(svc QueryService, logger log.TMLogger, bus *QueryEv
 	wsmux := http.NewServeMux()
 	routes := map[string]*rpcserver.RPCFunc{}
 	routes["query"] = rpcserver.NewRPCFunc(svc.Query, "caller,contract,query,vmType")
+	routes["query_at"] = rpcserver.NewRPCFunc(svc.QueryAt, "caller,contract,query,height,prove")
 	routes["env"] = rpcserver.NewRPCFunc(svc.QueryEnv, "")
-	routes["nonce"] = rpcserver.NewRPCFunc(svc.Nonce, "key,account")
+	routes["nonce"] = rpcserver.NewRPCFunc(svc.Nonce, "key,account,lane")
 	routes["subevents"] = rpcserver.NewWSRPCFunc(svc.Subscribe, "topics")
 	routes["unsubevents"] = rpcserver.NewWSRPCFunc(svc.UnSubscribe, "topic")
 	routes["resolve"] = rpcserver.NewRPCFunc(svc.Resolve, "name")
@@ -131,9 +148,15 @@ func MakeQueryServiceHandler(svc QueryService, logger log.TMLogger, bus *QueryEv
 	routes["getevmtransactionbyhash"] = rpcserver.NewRPCFunc(svc.GetEvmTransactionByHash, "txHash")
 	routes["evmsubscribe"] = rpcserver.NewWSRPCFunc(svc.EvmSubscribe, "method,filter")
 	routes["contractevents"] = rpcserver.NewRPCFunc(svc.ContractEvents, "fromBlock,toBlock,contract")
+	routes["contractevents_paged"] = rpcserver.NewRPCFunc(
+		svc.ContractEventsPaged, "fromBlock,toBlock,contract,limit,cursor",
+	)
 	routes["contractrecord"] = rpcserver.NewRPCFunc(svc.GetContractRecord, "contract")
+	routes["contractversions"] = rpcserver.NewRPCFunc(svc.GetContractVersions, "name")
 	routes["dpos_total_staked"] = rpcserver.NewRPCFunc(svc.DPOSTotalStaked, "")
 	routes["canonical_tx_hash"] = rpcserver.NewRPCFunc(svc.GetCanonicalTxHash, "block,txIndex,evmTxHash")
+	routes["fnconsensus_status"] = rpcserver.NewRPCFunc(svc.FnConsensusStatus, "")
+	routes["simulate"] = rpcserver.NewRPCFunc(svc.SimulateTx, "tx,caller")
 	rpcserver.RegisterRPCFuncs(wsmux, routes, codec, logger)
 	wm := rpcserver.NewWebsocketManager(routes, codec, rpcserver.EventSubscriber(bus))
 	wsmux.HandleFunc("/queryws", wm.WebsocketHandler)
@@ -156,7 +179,9 @@ func MakeQueryServiceHandler(svc QueryService, logger log.TMLogger, bus *QueryEv
 	return mux
 }
 
-func createDefaultEthRoutes(svc QueryService, chainID string) map[string]eth.RPCFunc {
+// createDefaultEthRoutes returns the methods served on the /eth endpoint, the debug_trace* methods
+// are only included if enableDebugRPC is true.
+func createDefaultEthRoutes(svc QueryService, chainID string, enableDebugRPC bool) map[string]eth.RPCFunc {
 	routes := map[string]eth.RPCFunc{}
 	routes["eth_blockNumber"] = eth.NewRPCFunc(svc.EthBlockNumber, "")
 	routes["eth_getBlockByNumber"] = eth.NewRPCFunc(svc.EthGetBlockByNumber, "block,full")
@@ -165,8 +190,10 @@ func createDefaultEthRoutes(svc QueryService, chainID string) map[string]eth.RPC
 	routes["eth_getTransactionByHash"] = eth.NewRPCFunc(svc.EthGetTransactionByHash, "hash")
 	routes["eth_getCode"] = eth.NewRPCFunc(svc.EthGetCode, "address,block")
 	routes["eth_getStorageAt"] = eth.NewRPCFunc(svc.EthGetStorageAt, "address,position,block")
+	routes["eth_getProof"] = eth.NewRPCFunc(svc.EthGetProof, "address,storageKeys,block")
 	routes["eth_call"] = eth.NewRPCFunc(svc.EthCall, "query,block")
 	routes["eth_getLogs"] = eth.NewRPCFunc(svc.EthGetLogs, "filter")
+	routes["loom_getLogsPaged"] = eth.NewRPCFunc(svc.EthGetLogsPaged, "filter,limit,cursor")
 	routes["eth_getBlockTransactionCountByNumber"] = eth.NewRPCFunc(svc.EthGetBlockTransactionCountByNumber, "block")
 	routes["eth_getBlockTransactionCountByHash"] = eth.NewRPCFunc(svc.EthGetBlockTransactionCountByHash, "hash")
 	routes["eth_getTransactionByBlockHashAndIndex"] = eth.NewRPCFunc(
@@ -191,13 +218,30 @@ func createDefaultEthRoutes(svc QueryService, chainID string) map[string]eth.RPC
 	routes["net_version"] = eth.NewRPCFunc(svc.EthNetVersion, "")
 	routes["eth_getTransactionCount"] = eth.NewRPCFunc(svc.EthGetTransactionCount, "local,block")
 	routes["eth_sendRawTransaction"] = NewSendRawTransactionRPCFunc(chainID, rpccore.BroadcastTxSync)
+
+	if enableDebugRPC {
+		routes["debug_traceTransaction"] = eth.NewRPCFunc(svc.DebugTraceTransaction, "hash,config")
+		routes["debug_traceCall"] = eth.NewRPCFunc(svc.DebugTraceCall, "query,block,config")
+		routes["debug_traceBlockByNumber"] = eth.NewRPCFunc(svc.DebugTraceBlockByNumber, "block,config")
+	}
 	return routes
 }
 
-// MakeEthQueryServiceHandler returns an http handler mapping to query service
-func MakeEthQueryServiceHandler(logger log.TMLogger, hub *Hub, routes map[string]eth.RPCFunc) http.Handler {
+// MakeEthQueryServiceHandler returns an http handler mapping to query service, the batch size &
+// rate limits specified in the given config are applied to all requests (cfg may be nil).
+func MakeEthQueryServiceHandler(
+	logger log.TMLogger, hub *Hub, routes map[string]eth.RPCFunc, cfg *eth.Web3Config,
+) http.Handler {
+	var trustedProxies []string
+	if cfg != nil {
+		trustedProxies = cfg.TrustedProxies
+	}
+	ipResolver, err := eth.NewClientIPResolver(trustedProxies)
+	if err != nil {
+		logger.Error("Invalid Web3.TrustedProxies, proxy headers will be ignored", "err", err)
+	}
 	wsmux := http.NewServeMux()
-	RegisterRPCFuncs(wsmux, routes, logger, hub)
+	RegisterRPCFuncs(wsmux, routes, logger, hub, eth.NewRequestLimiter(cfg), ipResolver)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
@@ -212,8 +256,9 @@ func MakeEthQueryServiceHandler(logger log.TMLogger, hub *Hub, routes map[string
 	return mux
 }
 
-// MakeUnsafeQueryServiceHandler returns a http handler for unsafe RPC routes
-func MakeUnsafeQueryServiceHandler(logger log.TMLogger) http.Handler {
+// MakeUnsafeQueryServiceHandler returns a http handler for unsafe RPC routes, the plugin management
+// routes are only added if pluginLoader is not nil.
+func MakeUnsafeQueryServiceHandler(logger log.TMLogger, pluginLoader plugin.ReloadableLoader) http.Handler {
 	codec := amino.NewCodec()
 	mux := http.NewServeMux()
 	routes := map[string]*rpcserver.RPCFunc{}
@@ -226,6 +271,10 @@ func MakeUnsafeQueryServiceHandler(logger log.TMLogger) http.Handler {
 	routes["unsafe_stop_cpu_profiler"] = rpcserver.NewRPCFunc(rpccore.UnsafeStopCPUProfiler, "")
 	routes["unsafe_write_heap_profile"] = rpcserver.NewRPCFunc(rpccore.UnsafeWriteHeapProfile, "filename")
 
+	if pluginLoader != nil {
+		addPluginRoutes(routes, pluginLoader)
+	}
+
 	rpcserver.RegisterRPCFuncs(mux, routes, codec, logger)
 	return mux
 }
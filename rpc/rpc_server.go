@@ -6,6 +6,9 @@ import (
 	"strings"
 
 	"github.com/loomnetwork/loomchain/log"
+	"github.com/loomnetwork/loomchain/plugin"
+	"github.com/loomnetwork/loomchain/rpc/eth"
+	"github.com/loomnetwork/loomchain/store"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	amino "github.com/tendermint/go-amino"
@@ -38,16 +41,19 @@ func init() {
 // RPCServer starts up HTTP servers that handle client requests.
 func RPCServer(
 	qsvc QueryService, chainID string, logger log.TMLogger, bus *QueryEventBus, bindAddr string,
-	enableUnsafeRPC bool, unsafeRPCBindAddress string,
+	enableUnsafeRPC bool, unsafeRPCBindAddress string, web3Cfg *eth.Web3Config, eventStore store.EventStore,
+	pluginLoader plugin.ReloadableLoader,
 ) error {
 	queryHandler := MakeQueryServiceHandler(qsvc, logger, bus)
 	hub := newHub()
 	go hub.run()
-	ethHandler := MakeEthQueryServiceHandler(logger, hub, createDefaultEthRoutes(qsvc, chainID))
+	enableDebugRPC := web3Cfg != nil && web3Cfg.DebugRPCEnabled
+	ethRoutes := createDefaultEthRoutes(qsvc, chainID, enableDebugRPC)
+	ethHandler := MakeEthQueryServiceHandler(logger, hub, ethRoutes, web3Cfg)
 
 	// Add the nonce route to the TM routes so clients can query the nonce from the /websocket
 	// and /rpc endpoints.
-	rpccore.Routes["nonce"] = rpcserver.NewRPCFunc(qsvc.Nonce, "key,account")
+	rpccore.Routes["nonce"] = rpcserver.NewRPCFunc(qsvc.Nonce, "key,account,lane")
 
 	wm := rpcserver.NewWebsocketManager(rpccore.Routes, cdc, rpcserver.EventSubscriber(bus))
 	wm.SetLogger(logger)
@@ -57,6 +63,12 @@ func RPCServer(
 	mux.Handle("/query", stripPrefix("/query", queryHandler)) //backwards compatibility
 	mux.Handle("/queryws", queryHandler)
 	mux.Handle("/eth", ethHandler)
+	// The event stream is only available when events are being persisted to the event store.
+	if eventStore != nil {
+		eventStreamHandler := MakeEventStreamHandler(eventStore, logger)
+		mux.Handle("/events", eventStreamHandler)
+		mux.Handle("/eventsws", eventStreamHandler)
+	}
 	rpcmux := http.NewServeMux()
 	rpcserver.RegisterRPCFuncs(rpcmux, rpccore.Routes, cdc, logger)
 	mux.Handle("/rpc/", stripPrefix("/rpc", CORSMethodMiddleware(rpcmux)))
@@ -94,7 +106,7 @@ func RPCServer(
 
 	if enableUnsafeRPC {
 		unsafeLogger := logger.With("interface", "unsafe")
-		unsafeHandler := MakeUnsafeQueryServiceHandler(unsafeLogger)
+		unsafeHandler := MakeUnsafeQueryServiceHandler(unsafeLogger, pluginLoader)
 		unsafeListener, err := rpcserver.Listen(
 			unsafeRPCBindAddress,
 			rpcserver.Config{MaxOpenConnections: 0},
@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+)
+
+// TxSimulator is used by QueryServer to execute txs without committing them.
+type TxSimulator interface {
+	SimulateTx(txBytes []byte, origin loom.Address) (*loomchain.SimulateTxResult, error)
+}
+
+// SimulateTxResponse is returned by the simulate query method.
+type SimulateTxResponse struct {
+	Data []byte
+	Info string
+	Tags []common.KVPair
+	// Empty if the tx would've succeeded.
+	Error string
+	// Events the tx would've emitted.
+	Events []*types.EventData
+	// Keys the tx would've written to the app store, ordered by key.
+	WriteSet []store.WriteSetEntry
+}
+
+// SimulateTx executes a tx against the latest committed app state without committing it, and returns
+// the result of the tx, along with the events it emitted & the keys it wrote to the app store.
+//
+// If caller is empty the tx must be a signed tx, just like one that would be sent to the node via
+// broadcast_tx_*. Otherwise the tx must be an unsigned tx (serialized NonceTx), which will be processed
+// as if it was signed by the caller, if the sequence number of the unsigned tx is zero it'll be set
+// to the next nonce of the caller.
+func (s *QueryServer) SimulateTx(tx []byte, caller string) (*SimulateTxResponse, error) {
+	if s.TxSimulator == nil {
+		return nil, errors.New("tx simulation is not supported by this node")
+	}
+
+	var origin loom.Address
+	if caller != "" {
+		var err error
+		origin, err = loom.ParseAddress(caller)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid caller")
+		}
+
+		var nonceTx auth.NonceTx
+		if err := proto.Unmarshal(tx, &nonceTx); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal unsigned tx")
+		}
+		if nonceTx.Sequence == 0 {
+			snapshot := s.StateProvider.ReadOnlyState()
+			nonceTx.Sequence = auth.Nonce(snapshot, origin) + 1
+			snapshot.Release()
+
+			tx, err = proto.Marshal(&nonceTx)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to marshal unsigned tx")
+			}
+		}
+	}
+
+	result, err := s.TxSimulator.SimulateTx(tx, origin)
+	if err != nil {
+		return nil, err
+	}
+	resp := &SimulateTxResponse{
+		Data:     result.Data,
+		Info:     result.Info,
+		Tags:     result.Tags,
+		Events:   result.Events,
+		WriteSet: result.WriteSet,
+	}
+	if result.Err != nil {
+		resp.Error = result.Err.Error()
+	}
+	return resp, nil
+}
@@ -0,0 +1,121 @@
+package loomchain
+
+import (
+	"context"
+	"time"
+
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain/eth/subs"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/pkg/errors"
+)
+
+// SimulateTxHandlerFactoryFunc creates a TxHandler that can be used to simulate txs without committing
+// them. Like the replay tx handler it must not share any mutable state with the handler used by the
+// app, and must discard any receipts generated by the txs it processes, but any events emitted by the
+// txs must be posted to eventHandler. If origin isn't empty the handler must skip tx signature
+// verification and process the txs as if they were signed by origin, in which case the txs passed to
+// the handler will be unsigned (i.e. serialized NonceTx).
+type SimulateTxHandlerFactoryFunc func(
+	kvStore store.KVStore, eventHandler EventHandler, origin loom.Address,
+) (TxHandler, error)
+
+// SimulateTxResult is the result of simulating a tx.
+type SimulateTxResult struct {
+	TxHandlerResult
+	Err error
+	// Events emitted by the tx, events are discarded when a tx fails so this will be empty if Err
+	// is set.
+	Events []*types.EventData
+	// All the keys that would've been written to the app store if the tx was committed, ordered
+	// by key.
+	WriteSet []store.WriteSetEntry
+}
+
+// SimulateTx executes a tx on top of the latest committed app state, and returns the result of the
+// tx, the events it emitted, and the changes it made to the app state. None of the state changes are
+// persisted. If origin is empty txBytes must be a signed tx, otherwise txBytes must be an unsigned tx
+// which will be processed as if it was signed by origin.
+func (a *Application) SimulateTx(txBytes []byte, origin loom.Address) (*SimulateTxResult, error) {
+	if a.CreateSimulateTxHandler == nil {
+		return nil, errors.New("tx simulation is not supported")
+	}
+
+	header := a.lastBlockHeader
+	snapshot := a.Store.GetSnapshot()
+	defer snapshot.Release()
+
+	// All the state changes made by the tx end up in this throwaway store.
+	overlay := store.NewOverlayStore(snapshot)
+	eventHandler := newEventCollector()
+	txHandler, err := a.CreateSimulateTxHandler(overlay, eventHandler, origin)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tx handler")
+	}
+
+	storeTx := store.WrapAtomic(overlay).BeginTx()
+	state := NewStoreState(context.Background(), storeTx, header, nil, a.GetValidatorSet)
+	r, txErr := txHandler.ProcessTx(state, txBytes, false)
+	result := &SimulateTxResult{
+		TxHandlerResult: r,
+		Err:             txErr,
+	}
+	if txErr == nil {
+		storeTx.Commit()
+		result.Events = eventHandler.events
+	} else {
+		storeTx.Rollback()
+	}
+	result.WriteSet = overlay.WriteSet()
+	return result, nil
+}
+
+// eventCollector is an EventHandler that keeps track of all the events posted to it, it's used to
+// capture the events emitted by simulated txs.
+type eventCollector struct {
+	events                 []*types.EventData
+	subscriptions          *SubscriptionSet
+	ethSubscriptions       *subs.EthSubscriptionSet
+	legacyEthSubscriptions *subs.LegacyEthSubscriptionSet
+}
+
+var _ EventHandler = &eventCollector{}
+
+func newEventCollector() *eventCollector {
+	return &eventCollector{
+		subscriptions:          NewSubscriptionSet(),
+		ethSubscriptions:       subs.NewEthSubscriptionSet(),
+		legacyEthSubscriptions: subs.NewLegacyEthSubscriptionSet(),
+	}
+}
+
+func (c *eventCollector) Post(height uint64, e *types.EventData) error {
+	if e.BlockHeight == 0 {
+		e.BlockHeight = height
+	}
+	c.events = append(c.events, e)
+	return nil
+}
+
+func (c *eventCollector) Commit(height uint64) {}
+
+func (c *eventCollector) Rollback() {
+	c.events = nil
+}
+
+func (c *eventCollector) EmitBlockTx(height uint64, blockTime time.Time) error {
+	return nil
+}
+
+func (c *eventCollector) SubscriptionSet() *SubscriptionSet {
+	return c.subscriptions
+}
+
+func (c *eventCollector) EthSubscriptionSet() *subs.EthSubscriptionSet {
+	return c.ethSubscriptions
+}
+
+func (c *eventCollector) LegacyEthSubscriptionSet() *subs.LegacyEthSubscriptionSet {
+	return c.legacyEthSubscriptions
+}
@@ -0,0 +1,65 @@
+package loomchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/loomnetwork/loomchain/db"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateTx(t *testing.T) {
+	memDb, _ := db.LoadMemDB()
+	appStore, err := store.NewIAVLStore(memDb, 0, 0, 0)
+	require.NoError(t, err)
+	appStore.Set([]byte("a"), []byte("1"))
+	appStore.Set([]byte("b"), []byte("2"))
+	_, _, err = appStore.SaveVersion()
+	require.NoError(t, err)
+
+	var simulatedOrigin loom.Address
+	app := &Application{
+		Store: appStore,
+		CreateSimulateTxHandler: func(
+			kvStore store.KVStore, eventHandler EventHandler, origin loom.Address,
+		) (TxHandler, error) {
+			simulatedOrigin = origin
+			return TxHandlerFunc(func(state State, txBytes []byte, isCheckTx bool) (TxHandlerResult, error) {
+				state.Set([]byte("c"), txBytes)
+				state.Delete([]byte("a"))
+				require.NoError(t, eventHandler.Post(1, &types.EventData{PluginName: "test"}))
+				if string(txBytes) == "fail" {
+					return TxHandlerResult{}, errors.New("tx failed")
+				}
+				return TxHandlerResult{Info: "ok"}, nil
+			}), nil
+		},
+	}
+
+	origin := loom.MustParseAddress("default:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+	result, err := app.SimulateTx([]byte("pass"), origin)
+	require.NoError(t, err)
+	require.NoError(t, result.Err)
+	require.Equal(t, origin, simulatedOrigin)
+	require.Equal(t, "ok", result.Info)
+	require.Len(t, result.Events, 1)
+	require.Equal(t, "test", result.Events[0].PluginName)
+	require.Equal(t, []store.WriteSetEntry{
+		{Key: []byte("a"), Deleted: true},
+		{Key: []byte("c"), Value: []byte("pass")},
+	}, result.WriteSet)
+
+	// failed txs don't emit events or change the state
+	result, err = app.SimulateTx([]byte("fail"), loom.Address{})
+	require.NoError(t, err)
+	require.EqualError(t, result.Err, "tx failed")
+	require.Empty(t, result.Events)
+	require.Empty(t, result.WriteSet)
+
+	// nothing should've been committed
+	require.Equal(t, []byte("1"), appStore.Get([]byte("a")))
+	require.Nil(t, appStore.Get([]byte("c")))
+}
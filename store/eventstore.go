@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/binary"
+	"math"
 	"sync"
 
 	"github.com/gogo/protobuf/proto"
@@ -22,6 +23,44 @@ type EventFilter struct {
 	FromBlock uint64
 	ToBlock   uint64
 	Contract  string
+	// Only match events that have at least one of these topics, matches all events if empty.
+	Topics []string
+}
+
+// matchTopics checks if the given event has at least one of the topics specified in the filter.
+func (f *EventFilter) matchTopics(event *types.EventData) bool {
+	if len(f.Topics) == 0 {
+		return true
+	}
+	for _, topic := range f.Topics {
+		for _, eventTopic := range event.Topics {
+			if topic == eventTopic {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EventCursor identifies the position of an event in the event store, events are ordered by block
+// height, and then by the order in which they were emitted within the block.
+type EventCursor struct {
+	BlockHeight uint64 `json:"blockHeight"`
+	EventIndex  uint16 `json:"eventIndex"`
+}
+
+// Next returns the cursor immediately following this one.
+func (c EventCursor) Next() EventCursor {
+	if c.EventIndex == math.MaxUint16 {
+		return EventCursor{BlockHeight: c.BlockHeight + 1}
+	}
+	return EventCursor{BlockHeight: c.BlockHeight, EventIndex: c.EventIndex + 1}
+}
+
+// CursorEvent is an event along with its position in the event store.
+type CursorEvent struct {
+	Cursor EventCursor      `json:"cursor"`
+	Event  *types.EventData `json:"event"`
 }
 
 type EventStore interface {
@@ -32,19 +71,30 @@ type EventStore interface {
 	BatchSaveEvents(events []*types.EventData) error
 	// FilterEvents filters events that match the given filter
 	FilterEvents(filter EventFilter) ([]*types.EventData, error)
+	// FilterEventsPaged returns up to limit events at or after the given cursor that match the given
+	// filter, the cursor of the last event returned can be used to fetch the next page.
+	FilterEventsPaged(filter EventFilter, from EventCursor, limit int) ([]*CursorEvent, error)
 	// ContractID mapping
 	GetContractID(pluginName string) uint64
+	// ReadEvents returns up to limit events at or after the given cursor that match the contract &
+	// topics in the filter, the block range in the filter is ignored.
+	ReadEvents(from EventCursor, filter EventFilter, limit int) ([]*CursorEvent, error)
+	// EventsSaved returns a channel that will be closed the next time events are saved to the store.
+	EventsSaved() <-chan struct{}
 }
 
 type KVEventStore struct {
 	dbm.DB
 	sync.Mutex
+
+	savedMutex sync.Mutex
+	savedCh    chan struct{}
 }
 
 var _ EventStore = &KVEventStore{}
 
 func NewKVEventStore(db dbm.DB) *KVEventStore {
-	return &KVEventStore{DB: db}
+	return &KVEventStore{DB: db, savedCh: make(chan struct{})}
 }
 
 func (s *KVEventStore) SaveEvent(contractID uint64, blockHeight uint64, eventIndex uint16, eventData *types.EventData) error {
@@ -54,6 +104,7 @@ func (s *KVEventStore) SaveEvent(contractID uint64, blockHeight uint64, eventInd
 	}
 	s.Set(prefixBlockHeightEventIndex(blockHeight, eventIndex), data)
 	s.Set(prefixContractIDBlockHightEventIndex(contractID, blockHeight, eventIndex), data)
+	s.notifyEventsSaved()
 	return nil
 }
 
@@ -79,43 +130,111 @@ func (s *KVEventStore) BatchSaveEvents(events []*types.EventData) error {
 		batch.Set(prefixContractIDBlockHightEventIndex(contractID, event.BlockHeight, eventIndex), data)
 	}
 	batch.Write()
+	if len(events) > 0 {
+		s.notifyEventsSaved()
+	}
 	return nil
 }
 
 func (s *KVEventStore) FilterEvents(filter EventFilter) ([]*types.EventData, error) {
+	cursorEvents, err := s.readEvents(EventCursor{BlockHeight: filter.FromBlock}, filter.ToBlock, filter, 0)
+	if err != nil {
+		return nil, err
+	}
 	var events []*types.EventData
+	for _, ce := range cursorEvents {
+		events = append(events, ce.Event)
+	}
+	return events, nil
+}
+
+func (s *KVEventStore) FilterEventsPaged(filter EventFilter, from EventCursor, limit int) ([]*CursorEvent, error) {
+	if from.BlockHeight < filter.FromBlock {
+		from = EventCursor{BlockHeight: filter.FromBlock}
+	}
+	if from.BlockHeight > filter.ToBlock {
+		return nil, nil
+	}
+	return s.readEvents(from, filter.ToBlock, filter, limit)
+}
+
+func (s *KVEventStore) ReadEvents(from EventCursor, filter EventFilter, limit int) ([]*CursorEvent, error) {
+	return s.readEvents(from, 0, filter, limit)
+}
+
+// readEvents returns up to limit events at or after the given cursor that match the contract & topics
+// in the filter, if toBlock is zero the events aren't limited to any particular block range.
+func (s *KVEventStore) readEvents(
+	from EventCursor, toBlock uint64, filter EventFilter, limit int,
+) ([]*CursorEvent, error) {
+	// Interator uses [start, end) so make sure we increase end inclusively
+	var start, end []byte
 	if filter.Contract != "" {
-		contractID := s.GetContractID(filter.Contract)
-		// Interator uses [start, end) so make sure we increase end inclusively
-		start := prefixContractIDBlockHight(contractID, filter.FromBlock)
-		end := prefixContractIDBlockHight(contractID, filter.ToBlock+1)
-		itr := s.Iterator(start, end)
-		defer itr.Close()
-		for ; itr.Valid(); itr.Next() {
-			var ed types.EventData
-			if err := proto.Unmarshal(itr.Value(), &ed); err != nil {
-				return nil, err
-			}
-			events = append(events, &ed)
+		contractID, ok := s.lookupContractID(filter.Contract)
+		if !ok {
+			// the contract hasn't emitted any events yet
+			return nil, nil
+		}
+		start = prefixContractIDBlockHightEventIndex(contractID, from.BlockHeight, from.EventIndex)
+		if toBlock > 0 {
+			end = prefixContractIDBlockHight(contractID, toBlock+1)
+		} else {
+			end = prefixContractIDBlockHight(contractID+1, 0)
 		}
 	} else {
-		// Interator uses [start, end) so make sure we increase end inclusively
-		start := prefixBlockHeightEventIndex(filter.FromBlock, 0)
-		end := prefixBlockHeightEventIndex(filter.ToBlock+1, 0)
-		itr := s.Iterator(start, end)
-		defer itr.Close()
-		for ; itr.Valid(); itr.Next() {
-			var ed types.EventData
-			if err := proto.Unmarshal(itr.Value(), &ed); err != nil {
-				return nil, err
-			}
-			events = append(events, &ed)
+		start = prefixBlockHeightEventIndex(from.BlockHeight, from.EventIndex)
+		if toBlock > 0 {
+			end = prefixBlockHeightEventIndex(toBlock+1, 0)
+		} else {
+			end = []byte{blockHeightKeyPrefix + 1}
 		}
 	}
 
+	var events []*CursorEvent
+	itr := s.Iterator(start, end)
+	defer itr.Close()
+	for ; itr.Valid() && (limit <= 0 || len(events) < limit); itr.Next() {
+		var ed types.EventData
+		if err := proto.Unmarshal(itr.Value(), &ed); err != nil {
+			return nil, err
+		}
+		if !filter.matchTopics(&ed) {
+			continue
+		}
+		// the event index is always stored at the end of the key
+		key := itr.Key()
+		events = append(events, &CursorEvent{
+			Cursor: EventCursor{
+				BlockHeight: ed.BlockHeight,
+				EventIndex:  binary.BigEndian.Uint16(key[len(key)-2:]),
+			},
+			Event: &ed,
+		})
+	}
 	return events, nil
 }
 
+func (s *KVEventStore) EventsSaved() <-chan struct{} {
+	s.savedMutex.Lock()
+	defer s.savedMutex.Unlock()
+	return s.savedCh
+}
+
+// notifyEventsSaved wakes up everyone waiting on the channel returned by EventsSaved.
+func (s *KVEventStore) notifyEventsSaved() {
+	s.savedMutex.Lock()
+	defer s.savedMutex.Unlock()
+	close(s.savedCh)
+	s.savedCh = make(chan struct{})
+}
+
+// lookupContractID returns the ID of the given contract, unlike GetContractID it doesn't assign
+// a new ID to contracts that don't have one yet.
+func (s *KVEventStore) lookupContractID(pluginName string) (uint64, bool) {
+	id := bytesToUint64(s.Get(prefixPluginName(pluginName)))
+	return id, id != 0
+}
+
 func (s *KVEventStore) GetContractID(pluginName string) uint64 {
 	data := s.Get(prefixPluginName(pluginName))
 	id := bytesToUint64(data)
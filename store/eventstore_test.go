@@ -228,3 +228,107 @@ func TestEventStoreFilterMultiplePluginsMemDB(t *testing.T) {
 	_, err = eventStore.FilterEvents(filter4)
 	require.Nil(t, err)
 }
+
+func TestEventStoreReadEvents(t *testing.T) {
+	eventStore := NewKVEventStore(dbm.NewMemDB())
+
+	var events []*types.EventData
+	for height := uint64(1); height <= 3; height++ {
+		saved := eventStore.EventsSaved()
+		events = nil
+		for i := 0; i < 3; i++ {
+			pluginName := "plugin1"
+			if i == 2 {
+				pluginName = "plugin2"
+			}
+			events = append(events, &types.EventData{
+				BlockHeight: height,
+				PluginName:  pluginName,
+				Topics:      []string{fmt.Sprintf("topic%d", i)},
+				EncodedBody: []byte(fmt.Sprintf("event-%d-%d", height, i)),
+			})
+		}
+		require.NoError(t, eventStore.BatchSaveEvents(events))
+		select {
+		case <-saved:
+		default:
+			t.Fatal("EventsSaved channel should be closed after events are saved")
+		}
+	}
+
+	result, err := eventStore.ReadEvents(EventCursor{}, EventFilter{}, 0)
+	require.NoError(t, err)
+	require.Len(t, result, 9)
+	require.Equal(t, EventCursor{BlockHeight: 1, EventIndex: 0}, result[0].Cursor)
+	require.Equal(t, EventCursor{BlockHeight: 3, EventIndex: 2}, result[8].Cursor)
+
+	// resume from the middle of a block, with a limit
+	result, err = eventStore.ReadEvents(EventCursor{BlockHeight: 2, EventIndex: 1}, EventFilter{}, 3)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	require.Equal(t, []byte("event-2-1"), result[0].Event.EncodedBody)
+	require.Equal(t, EventCursor{BlockHeight: 3, EventIndex: 1}, result[2].Cursor)
+
+	result, err = eventStore.ReadEvents(
+		EventCursor{BlockHeight: 2}, EventFilter{Contract: "plugin2"}, 0,
+	)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, EventCursor{BlockHeight: 2, EventIndex: 2}, result[0].Cursor)
+	require.Equal(t, EventCursor{BlockHeight: 3, EventIndex: 2}, result[1].Cursor)
+
+	result, err = eventStore.ReadEvents(
+		EventCursor{}, EventFilter{Contract: "plugin1", Topics: []string{"topic1"}}, 0,
+	)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	for _, r := range result {
+		require.Equal(t, uint16(1), r.Cursor.EventIndex)
+	}
+
+	result, err = eventStore.ReadEvents(EventCursor{}, EventFilter{Contract: "plugin3"}, 0)
+	require.NoError(t, err)
+	require.Len(t, result, 0)
+	_, ok := eventStore.lookupContractID("plugin3")
+	require.False(t, ok)
+
+	require.Equal(t, EventCursor{BlockHeight: 3}, EventCursor{BlockHeight: 2, EventIndex: 65535}.Next())
+}
+
+func TestEventStoreFilterEventsPaged(t *testing.T) {
+	eventStore := NewKVEventStore(dbm.NewMemDB())
+	for height := uint64(1); height <= 4; height++ {
+		var events []*types.EventData
+		for i := 0; i < 2; i++ {
+			events = append(events, &types.EventData{
+				BlockHeight: height,
+				PluginName:  "plugin1",
+				EncodedBody: []byte(fmt.Sprintf("event-%d-%d", height, i)),
+			})
+		}
+		require.NoError(t, eventStore.BatchSaveEvents(events))
+	}
+
+	filter := EventFilter{FromBlock: 2, ToBlock: 3, Contract: "plugin1"}
+	var pages [][]*CursorEvent
+	cursor := EventCursor{}
+	for {
+		page, err := eventStore.FilterEventsPaged(filter, cursor, 3)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		pages = append(pages, page)
+		cursor = page[len(page)-1].Cursor.Next()
+	}
+	require.Len(t, pages, 2)
+	require.Len(t, pages[0], 3)
+	require.Len(t, pages[1], 1)
+	require.Equal(t, []byte("event-2-0"), pages[0][0].Event.EncodedBody)
+	require.Equal(t, EventCursor{BlockHeight: 3, EventIndex: 1}, pages[1][0].Cursor)
+
+	// the block range applies even when no contract is specified
+	result, err := eventStore.FilterEventsPaged(EventFilter{FromBlock: 4, ToBlock: 4}, EventCursor{}, 0)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+}
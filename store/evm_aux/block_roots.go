@@ -0,0 +1,55 @@
+package evmaux
+
+import (
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	receiptsRootPrefix = []byte("brroot")
+	stateRootPrefix    = []byte("bsroot")
+)
+
+func receiptsRootKey(height uint64) []byte {
+	return util.PrefixKey(receiptsRootPrefix, blockHeightToBytes(height))
+}
+
+func stateRootKey(height uint64) []byte {
+	return util.PrefixKey(stateRootPrefix, blockHeightToBytes(height))
+}
+
+// SetReceiptsRoot stores the root of the Merkle-Patricia trie of the EVM tx receipts in the block at
+// the given height.
+func (s *EvmAuxStore) SetReceiptsRoot(root []byte, height uint64) error {
+	return s.db.Put(receiptsRootKey(height), root, nil)
+}
+
+// GetReceiptsRoot returns the receipts root of the block at the given height, or nil if it wasn't
+// stored. Receipts roots are not available for blocks committed by older builds.
+func (s *EvmAuxStore) GetReceiptsRoot(height uint64) ([]byte, error) {
+	root, err := s.db.Get(receiptsRootKey(height), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	return root, err
+}
+
+// SetStateRoot stores the root of the EVM state at the end of the block at the given height, if the
+// root is empty (because there's no EVM state yet) the root of an empty trie is stored instead.
+func (s *EvmAuxStore) SetStateRoot(root []byte, height uint64) error {
+	if len(root) == 0 {
+		root = etypes.EmptyRootHash.Bytes()
+	}
+	return s.db.Put(stateRootKey(height), root, nil)
+}
+
+// GetStateRoot returns the EVM state root at the end of the block at the given height, or nil if it
+// wasn't stored. State roots are not available for blocks committed by older builds.
+func (s *EvmAuxStore) GetStateRoot(height uint64) ([]byte, error) {
+	root, err := s.db.Get(stateRootKey(height), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	return root, err
+}
@@ -0,0 +1,207 @@
+package evmaux
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/core/bloombits"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// The bloom-bits index is similar to the one used by go-ethereum. The Ethereum-style (2048 bit) bloom
+// of each block is stored in the DB as the block is committed. Once all the blocks in a section have
+// been committed the blooms of the blocks in the section are rotated into 2048 bit vectors, one for
+// each bloom bit, with each bit vector indicating which blocks in the section have that bloom bit set.
+// To find the blocks in a section that may contain a particular address or topic only the bit
+// vectors of the three bloom bits the address or topic maps to have to be loaded & ANDed together.
+
+// BloomBitsSectionSize is the number of blocks in each section of the bloom-bits index.
+const BloomBitsSectionSize = uint64(4096)
+
+var (
+	blockBloomPrefix     = []byte("bbl")
+	bloomBitsPrefix      = []byte("bbits")
+	bloomBitsSectionsKey = []byte("bbsections")
+	// The lowest height from which the blooms of all subsequent blocks have been stored.
+	blockBloomsFromKey = []byte("bblfrom")
+)
+
+func blockBloomKey(height uint64) []byte {
+	return util.PrefixKey(blockBloomPrefix, blockHeightToBytes(height))
+}
+
+func bloomBitsKey(bit uint, section uint64) []byte {
+	key := make([]byte, 10)
+	binary.BigEndian.PutUint16(key, uint16(bit))
+	binary.BigEndian.PutUint64(key[2:], section)
+	return util.PrefixKey(bloomBitsPrefix, key)
+}
+
+func bloomBitsSectionKey(section uint64) []byte {
+	return util.PrefixKey(bloomBitsSectionsKey, blockHeightToBytes(section))
+}
+
+// SetBlockBloom stores the Ethereum-style bloom of the block at the given height.
+func (s *EvmAuxStore) SetBlockBloom(tran *leveldb.Transaction, bloom []byte, height uint64) error {
+	if len(bloom) != etypes.BloomByteLength {
+		return errors.Errorf("invalid block bloom length %d", len(bloom))
+	}
+	return tran.Put(blockBloomKey(height), bloom, nil)
+}
+
+// GetBlockBloom returns the Ethereum-style bloom of the block at the given height, or nil if the bloom
+// wasn't stored. Blocks without any EVM txs don't have a bloom.
+func (s *EvmAuxStore) GetBlockBloom(height uint64) ([]byte, error) {
+	bloom, err := s.db.Get(blockBloomKey(height), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	return bloom, err
+}
+
+// BlockBloomsFrom returns the lowest height from which the blooms of all the subsequent blocks are
+// available, the second return value will be false if no block blooms have been stored yet.
+func (s *EvmAuxStore) BlockBloomsFrom() (uint64, bool, error) {
+	b, err := s.db.Get(blockBloomsFromKey, nil)
+	if err == leveldb.ErrNotFound {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(b), true, nil
+}
+
+// SetBlockBloomsFrom records that the blooms of all the blocks from the given height onwards are
+// available.
+func (s *EvmAuxStore) SetBlockBloomsFrom(height uint64) error {
+	return s.db.Put(blockBloomsFromKey, blockHeightToBytes(height), nil)
+}
+
+// IsBloomBitsSectionIndexed checks if the given section of the bloom-bits index has been generated.
+func (s *EvmAuxStore) IsBloomBitsSectionIndexed(section uint64) (bool, error) {
+	return s.db.Has(bloomBitsSectionKey(section), nil)
+}
+
+// GetBloomBits returns the bit vector of the given bloom bit in the given section, the n-th bit of the
+// vector (counting from the most significant bit of the first byte) is set if the bloom bit is set in
+// the bloom of the n-th block in the section.
+func (s *EvmAuxStore) GetBloomBits(bit uint, section uint64) ([]byte, error) {
+	compressed, err := s.db.Get(bloomBitsKey(bit, section), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load bloom bit %d of section %d", bit, section)
+	}
+	return bitutil.DecompressBytes(compressed, int(BloomBitsSectionSize/8))
+}
+
+// IndexBloomBitsSection generates the given section of the bloom-bits index from the stored block
+// blooms, blocks without a stored bloom are assumed to have an empty bloom.
+func (s *EvmAuxStore) IndexBloomBitsSection(section uint64) error {
+	gen, err := bloombits.NewGenerator(uint(BloomBitsSectionSize))
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < BloomBitsSectionSize; i++ {
+		var bloom etypes.Bloom
+		b, err := s.GetBlockBloom(section*BloomBitsSectionSize + i)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load block bloom")
+		}
+		copy(bloom[:], b)
+		if err := gen.AddBloom(uint(i), bloom); err != nil {
+			return err
+		}
+	}
+
+	tran, err := s.db.OpenTransaction()
+	if err != nil {
+		return errors.Wrap(err, "failed to open tx in EvmAuxStore")
+	}
+	defer tran.Discard()
+
+	for bit := uint(0); bit < etypes.BloomBitLength; bit++ {
+		bits, err := gen.Bitset(bit)
+		if err != nil {
+			return err
+		}
+		if err := tran.Put(bloomBitsKey(bit, section), bitutil.CompressBytes(bits), nil); err != nil {
+			return err
+		}
+	}
+	if err := tran.Put(bloomBitsSectionKey(section), []byte{1}, nil); err != nil {
+		return err
+	}
+	if err := tran.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit tx in EvmAuxStore")
+	}
+	return nil
+}
+
+// UpdateBloomBits should be called after the block at the given height is committed, if the block is
+// the last one in a section of the bloom-bits index any sections that haven't been indexed yet will be
+// generated.
+func (s *EvmAuxStore) UpdateBloomBits(height uint64) error {
+	_, ok, err := s.BlockBloomsFrom()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// The blooms of the blocks before this one have never been stored, they'll have to be
+		// backfilled before the sections they belong to can be indexed.
+		if err := s.SetBlockBloomsFrom(height); err != nil {
+			return err
+		}
+	}
+	if (height+1)%BloomBitsSectionSize != 0 {
+		return nil
+	}
+	return s.IndexBloomBits(height)
+}
+
+// IndexBloomBits generates all the sections of the bloom-bits index that haven't been indexed yet, and
+// only contain blocks at or below the given height. Sections are only indexed if the blooms of all the
+// blocks in the section are available.
+func (s *EvmAuxStore) IndexBloomBits(height uint64) error {
+	from, ok, err := s.BlockBloomsFrom()
+	if err != nil || !ok {
+		return err
+	}
+	for section := FirstIndexableSection(from); LastBlockInSection(section) <= height; section++ {
+		indexed, err := s.IsBloomBitsSectionIndexed(section)
+		if err != nil {
+			return err
+		}
+		if indexed {
+			continue
+		}
+		if err := s.IndexBloomBitsSection(section); err != nil {
+			return errors.Wrapf(err, "failed to index section %d", section)
+		}
+	}
+	return nil
+}
+
+// FirstBlockInSection returns the height of the first block in the given section of the bloom-bits
+// index, there's no block at height zero so the first section starts at height one.
+func FirstBlockInSection(section uint64) uint64 {
+	if section == 0 {
+		return 1
+	}
+	return section * BloomBitsSectionSize
+}
+
+// LastBlockInSection returns the height of the last block in the given section of the bloom-bits index.
+func LastBlockInSection(section uint64) uint64 {
+	return (section+1)*BloomBitsSectionSize - 1
+}
+
+// FirstIndexableSection returns the first section of the bloom-bits index that only contains blocks at
+// or above the given height.
+func FirstIndexableSection(height uint64) uint64 {
+	if height <= 1 {
+		return 0
+	}
+	return (height + BloomBitsSectionSize - 1) / BloomBitsSectionSize
+}
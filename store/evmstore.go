@@ -35,6 +35,11 @@ func init() {
 	)
 }
 
+// GetEvmStateRoot returns the Patricia root of the EVM state in the given store.
+func GetEvmStateRoot(s KVReader) []byte {
+	return s.Get(rootHashKey)
+}
+
 func evmRootKey(blockHeight int64) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b, uint64(blockHeight))
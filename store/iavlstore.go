@@ -243,6 +243,28 @@ func (s *IAVLStore) Prune() error {
 	return nil
 }
 
+// GetWithProofAt returns the value of the given key at a previously saved version of the store, along
+// with a proof of existence (or absence) of the key.
+func (s *IAVLStore) GetWithProofAt(key []byte, version int64) ([]byte, *iavl.RangeProof, error) {
+	tree, err := s.tree.GetImmutable(version)
+	if err != nil {
+		return nil, nil, errors.Wrapf(ErrVersionNotFound, "app store version %d: %v", version, err)
+	}
+	return tree.GetWithProof(key)
+}
+
+// GetRangeWithProofAt returns all the keys & values with the given prefix at a previously saved
+// version of the store, along with a proof of their existence.
+func (s *IAVLStore) GetRangeWithProofAt(
+	prefix []byte, version int64,
+) ([][]byte, [][]byte, *iavl.RangeProof, error) {
+	tree, err := s.tree.GetImmutable(version)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(ErrVersionNotFound, "app store version %d: %v", version, err)
+	}
+	return getRangeWithProof(tree, prefix)
+}
+
 func (s *IAVLStore) GetSnapshot() Snapshot {
 	// This isn't an actual snapshot obviously, and never will be, but lets pretend...
 	return &iavlStoreSnapshot{
@@ -253,6 +275,9 @@ func (s *IAVLStore) GetSnapshot() Snapshot {
 var (
 	standardStorePrefixes = [][]byte{
 		[]byte("nonce"),
+		[]byte("lane_nonce"),
+		[]byte("sponsor"),
+		[]byte("txlimit"),
 		[]byte("feature"),
 		[]byte("registry"),
 		[]byte("reg_caddr"),
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/loomnetwork/go-loom/plugin"
+	"github.com/pkg/errors"
+	"github.com/tendermint/iavl"
 )
 
 type LogParams struct {
@@ -26,6 +28,9 @@ type LogStore struct {
 	store  VersionedKVStore
 	logger log.Logger
 	params LogParams
+
+	// Keys written since RecordWriteSet was called, nil if the write set isn't being recorded.
+	writeSet []WriteSetEntry
 }
 
 func NewLogStore(store VersionedKVStore) (ls *LogStore, err error) {
@@ -59,6 +64,9 @@ func (s *LogStore) Delete(key []byte) {
 	if s.params.LogDelete {
 		s.logger.Println("Delete key: ", string(key))
 	}
+	if s.writeSet != nil {
+		s.writeSet = append(s.writeSet, WriteSetEntry{Key: copyBytes(key), Deleted: true})
+	}
 	s.store.Delete(key)
 }
 
@@ -72,6 +80,9 @@ func (s *LogStore) Set(key, val []byte) {
 	if s.params.LogSetSize {
 		s.logger.Println("Set Size: ", len(val))
 	}
+	if s.writeSet != nil {
+		s.writeSet = append(s.writeSet, WriteSetEntry{Key: copyBytes(key), Value: copyBytes(val)})
+	}
 	s.store.Set(key, val)
 }
 
@@ -129,3 +140,51 @@ func (s *LogStore) Prune() error {
 func (s *LogStore) GetSnapshot() Snapshot {
 	return s.store.GetSnapshot()
 }
+
+func (s *LogStore) GetSnapshotAt(version int64) (Snapshot, error) {
+	if hs, ok := s.store.(HistoricalSnapshotter); ok {
+		return hs.GetSnapshotAt(version)
+	}
+	return nil, errors.New("[LogStore] GetSnapshotAt() not supported by underlying store")
+}
+
+func (s *LogStore) GetWithProofAt(key []byte, version int64) ([]byte, *iavl.RangeProof, error) {
+	if sp, ok := s.store.(StateProver); ok {
+		return sp.GetWithProofAt(key, version)
+	}
+	return nil, nil, errors.New("[LogStore] GetWithProofAt() not supported by underlying store")
+}
+
+func (s *LogStore) GetRangeWithProofAt(
+	prefix []byte, version int64,
+) ([][]byte, [][]byte, *iavl.RangeProof, error) {
+	if sp, ok := s.store.(StateProver); ok {
+		return sp.GetRangeWithProofAt(prefix, version)
+	}
+	return nil, nil, nil, errors.New("[LogStore] GetRangeWithProofAt() not supported by underlying store")
+}
+
+// RecordWriteSet starts recording all the keys written to the store, the recorded keys can be
+// retrieved by calling TakeWriteSet.
+func (s *LogStore) RecordWriteSet() {
+	s.writeSet = []WriteSetEntry{}
+}
+
+// TakeWriteSet returns all the keys written to the store since the last call to RecordWriteSet or
+// TakeWriteSet (in the order they were written), and resets the recorded write set.
+func (s *LogStore) TakeWriteSet() []WriteSetEntry {
+	writeSet := s.writeSet
+	if writeSet != nil {
+		s.writeSet = []WriteSetEntry{}
+	}
+	return writeSet
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogStoreWriteSet(t *testing.T) {
+	s, err := NewLogStore(NewMemStore())
+	require.NoError(t, err)
+	defer os.Remove(s.params.LogFilename)
+
+	// writes aren't recorded until RecordWriteSet is called
+	s.Set(key1, val1)
+	require.Nil(t, s.TakeWriteSet())
+
+	s.RecordWriteSet()
+	s.Set(key2, val2)
+	s.Delete(key1)
+	require.Equal(t, []WriteSetEntry{
+		{Key: key2, Value: val2},
+		{Key: key1, Deleted: true},
+	}, s.TakeWriteSet())
+
+	require.Empty(t, s.TakeWriteSet())
+	s.Set(key3, val3)
+	require.Equal(t, []WriteSetEntry{{Key: key3, Value: val3}}, s.TakeWriteSet())
+	require.Equal(t, val3, s.Get(key3))
+	require.False(t, s.Has(key1))
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"bytes"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
@@ -88,6 +89,9 @@ type MultiWriterAppStore struct {
 	evmStore                   *EvmStore
 	lastSavedTree              unsafe.Pointer // *iavl.ImmutableTree
 	onlySaveEvmStateToEvmStore bool
+	// Guards the versions of the IAVL tree while they're being saved or pruned, so that snapshots of
+	// older versions can be created by other threads.
+	treeVersionsMutex sync.Mutex
 }
 
 // NewMultiWriterAppStore creates a new MultiWriterAppStore.
@@ -208,7 +212,9 @@ func (s *MultiWriterAppStore) SaveVersion() ([]byte, int64, error) {
 		}
 	}
 
+	s.treeVersionsMutex.Lock()
 	hash, version, err := s.appStore.SaveVersion()
+	s.treeVersionsMutex.Unlock()
 	if err != nil {
 		return nil, 0, err
 	}
@@ -269,6 +275,8 @@ func (s *MultiWriterAppStore) setLastSavedTreeToVersion(version int64) error {
 }
 
 func (s *MultiWriterAppStore) Prune() error {
+	s.treeVersionsMutex.Lock()
+	defer s.treeVersionsMutex.Unlock()
 	return s.appStore.Prune()
 }
 
@@ -281,6 +289,71 @@ func (s *MultiWriterAppStore) GetSnapshot() Snapshot {
 	return newMultiWriterStoreSnapshot(evmDbSnapshot, appStoreTree)
 }
 
+// GetSnapshotAt creates a snapshot of the store at a previously saved version, the version must
+// still be retained in both app.db & evm.db (see AppStoreConfig.MaxVersions).
+func (s *MultiWriterAppStore) GetSnapshotAt(version int64) (Snapshot, error) {
+	if version == 0 || version > s.Version() {
+		return nil, errors.Wrapf(ErrVersionNotFound, "app store version %d", version)
+	}
+
+	s.treeVersionsMutex.Lock()
+	appStoreTree, err := s.appStore.tree.GetImmutable(version)
+	s.treeVersionsMutex.Unlock()
+	if err != nil {
+		return nil, errors.Wrapf(ErrVersionNotFound, "app store version %d: %v", version, err)
+	}
+
+	evmDbSnapshot := s.evmStore.GetSnapshot(version)
+	// the snapshot always returns the EVM root for the version it was created for
+	if evmDbSnapshot.Get(rootHashKey) == nil {
+		evmDbSnapshot.Release()
+		return nil, errors.Wrapf(ErrVersionNotFound, "EVM root for version %d", version)
+	}
+	return newMultiWriterStoreSnapshot(evmDbSnapshot, appStoreTree), nil
+}
+
+// GetWithProofAt returns the value of the given key at a previously saved version of the store, along
+// with a proof of existence (or absence) of the key against the app hash. EVM state is not stored in
+// the IAVL tree so keys with the vm prefix can't be proven.
+func (s *MultiWriterAppStore) GetWithProofAt(key []byte, version int64) ([]byte, *iavl.RangeProof, error) {
+	if util.HasPrefix(key, vmPrefix) {
+		return nil, nil, errors.New("EVM state can't be proven against the app hash")
+	}
+	tree, err := s.getImmutableTree(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tree.GetWithProof(key)
+}
+
+// GetRangeWithProofAt returns all the keys & values with the given prefix at a previously saved
+// version of the store, along with a proof of their existence against the app hash.
+func (s *MultiWriterAppStore) GetRangeWithProofAt(
+	prefix []byte, version int64,
+) ([][]byte, [][]byte, *iavl.RangeProof, error) {
+	if bytes.Equal(prefix, vmPrefix) || util.HasPrefix(prefix, vmPrefix) {
+		return nil, nil, nil, errors.New("EVM state can't be proven against the app hash")
+	}
+	tree, err := s.getImmutableTree(version)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return getRangeWithProof(tree, prefix)
+}
+
+func (s *MultiWriterAppStore) getImmutableTree(version int64) (*iavl.ImmutableTree, error) {
+	if version == 0 || version > s.Version() {
+		return nil, errors.Wrapf(ErrVersionNotFound, "app store version %d", version)
+	}
+	s.treeVersionsMutex.Lock()
+	defer s.treeVersionsMutex.Unlock()
+	tree, err := s.appStore.tree.GetImmutable(version)
+	if err != nil {
+		return nil, errors.Wrapf(ErrVersionNotFound, "app store version %d: %v", version, err)
+	}
+	return tree, nil
+}
+
 type multiWriterStoreSnapshot struct {
 	evmDbSnapshot db.Snapshot
 	appStoreTree  *iavl.ImmutableTree
@@ -9,6 +9,7 @@ import (
 	"github.com/loomnetwork/go-loom/util"
 	"github.com/loomnetwork/loomchain/db"
 	"github.com/loomnetwork/loomchain/log"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -126,6 +127,43 @@ func (m *MultiWriterAppStoreTestSuite) TestMultiWriterAppStoreSnapShot() {
 	require.Equal([]byte("vvvvvvvvv"), snapshot.Get(vmPrefixKey("abcde")))
 }
 
+func (m *MultiWriterAppStoreTestSuite) TestMultiWriterAppStoreSnapShotAtVersion() {
+	require := m.Require()
+	store, err := mockMultiWriterStore(-1)
+	require.NoError(err)
+
+	store.Set(evmDBFeatureKey, []byte{1})
+	store.Set(vmPrefixKey("abcd"), []byte("hello"))
+	store.Set([]byte("abcd"), []byte("NewData"))
+	_, version1, err := store.SaveVersion()
+	require.NoError(err)
+
+	store.Set([]byte("abcd"), []byte("asdfasdf"))
+	store.Set([]byte("efgh"), []byte("added"))
+	_, version2, err := store.SaveVersion()
+	require.NoError(err)
+
+	snapshot, err := store.GetSnapshotAt(version1)
+	require.NoError(err)
+	require.Equal([]byte("NewData"), snapshot.Get([]byte("abcd")))
+	require.False(snapshot.Has([]byte("efgh")))
+	require.Equal([]byte("hello"), snapshot.Get(vmPrefixKey("abcd")))
+	snapshot.Release()
+
+	snapshot, err = store.GetSnapshotAt(version2)
+	require.NoError(err)
+	require.Equal([]byte("asdfasdf"), snapshot.Get([]byte("abcd")))
+	require.Equal([]byte("added"), snapshot.Get([]byte("efgh")))
+	snapshot.Release()
+
+	_, err = store.GetSnapshotAt(version2 + 1)
+	require.Equal(ErrVersionNotFound, errors.Cause(err))
+
+	require.NoError(store.appStore.tree.DeleteVersion(version1))
+	_, err = store.GetSnapshotAt(version1)
+	require.Equal(ErrVersionNotFound, errors.Cause(err))
+}
+
 func (m *MultiWriterAppStoreTestSuite) TestMultiWriterAppStoreSnapShotFlushInterval() {
 	require := m.Require()
 	// flush data to disk every 2 blocks
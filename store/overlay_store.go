@@ -0,0 +1,101 @@
+package store
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/loomnetwork/go-loom/plugin"
+	"github.com/loomnetwork/go-loom/util"
+)
+
+// OverlayStore is an in-memory KVStore layered on top of a read-only KVReader, writes are kept in
+// memory and never reach the underlying reader. It can be used to execute txs against a snapshot of
+// the app state without persisting the resulting changes. Unlike cacheTx, ranges include any keys
+// that have been written to the overlay.
+type OverlayStore struct {
+	base  KVReader
+	cache map[string]cacheItem
+}
+
+var _ KVStore = &OverlayStore{}
+
+// NewOverlayStore creates a new OverlayStore on top of the given reader.
+func NewOverlayStore(base KVReader) *OverlayStore {
+	return &OverlayStore{
+		base:  base,
+		cache: make(map[string]cacheItem),
+	}
+}
+
+func (s *OverlayStore) Get(key []byte) []byte {
+	if item, ok := s.cache[string(key)]; ok {
+		return item.Value
+	}
+	return s.base.Get(key)
+}
+
+func (s *OverlayStore) Has(key []byte) bool {
+	if item, ok := s.cache[string(key)]; ok {
+		return !item.Deleted
+	}
+	return s.base.Has(key)
+}
+
+func (s *OverlayStore) Set(key, val []byte) {
+	s.cache[string(key)] = cacheItem{Value: val}
+}
+
+func (s *OverlayStore) Delete(key []byte) {
+	s.cache[string(key)] = cacheItem{Deleted: true}
+}
+
+// Range returns the keys & values prefixed by the given prefix, ordered by key.
+func (s *OverlayStore) Range(prefix []byte) plugin.RangeData {
+	merged := make(map[string][]byte)
+	for _, entry := range s.base.Range(prefix) {
+		merged[string(entry.Key)] = entry.Value
+	}
+
+	for key, item := range s.cache {
+		if !util.HasPrefix([]byte(key), prefix) {
+			continue
+		}
+		k, err := util.UnprefixKey([]byte(key), prefix)
+		if err != nil {
+			panic(err)
+		}
+		if item.Deleted {
+			delete(merged, string(k))
+		} else {
+			merged[string(k)] = item.Value
+		}
+	}
+
+	ret := make(plugin.RangeData, 0, len(merged))
+	for k, v := range merged {
+		ret = append(ret, &plugin.RangeEntry{
+			Key:   []byte(k),
+			Value: v,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i].Key, ret[j].Key) < 0
+	})
+	return ret
+}
+
+// WriteSet returns all the keys that have been written to the overlay, ordered by key.
+func (s *OverlayStore) WriteSet() []WriteSetEntry {
+	writeSet := make([]WriteSetEntry, 0, len(s.cache))
+	for key, item := range s.cache {
+		writeSet = append(writeSet, WriteSetEntry{
+			Key:     []byte(key),
+			Value:   item.Value,
+			Deleted: item.Deleted,
+		})
+	}
+	sort.Slice(writeSet, func(i, j int) bool {
+		return bytes.Compare(writeSet[i].Key, writeSet[j].Key) < 0
+	})
+	return writeSet
+}
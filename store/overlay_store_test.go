@@ -0,0 +1,83 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/loomnetwork/go-loom/plugin"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlayStoreReadWrite(t *testing.T) {
+	base := NewMemStore()
+	base.Set([]byte("a"), []byte("1"))
+	base.Set([]byte("b"), []byte("2"))
+
+	s := NewOverlayStore(base)
+	require.Equal(t, []byte("1"), s.Get([]byte("a")))
+	require.True(t, s.Has([]byte("b")))
+	require.False(t, s.Has([]byte("c")))
+
+	s.Set([]byte("a"), []byte("changed"))
+	s.Set([]byte("c"), []byte("3"))
+	s.Delete([]byte("b"))
+	require.Equal(t, []byte("changed"), s.Get([]byte("a")))
+	require.Equal(t, []byte("3"), s.Get([]byte("c")))
+	require.Nil(t, s.Get([]byte("b")))
+	require.False(t, s.Has([]byte("b")))
+	require.True(t, s.Has([]byte("c")))
+
+	// deleted keys can be written again
+	s.Set([]byte("b"), []byte("again"))
+	require.Equal(t, []byte("again"), s.Get([]byte("b")))
+
+	// none of the writes should reach the underlying store
+	require.Equal(t, []byte("1"), base.Get([]byte("a")))
+	require.Equal(t, []byte("2"), base.Get([]byte("b")))
+	require.False(t, base.Has([]byte("c")))
+}
+
+func TestOverlayStoreRange(t *testing.T) {
+	key := func(prefix, k string) []byte {
+		return util.PrefixKey([]byte(prefix), []byte(k))
+	}
+	base := NewMemStore()
+	base.Set(key("p", "a"), []byte("1"))
+	base.Set(key("p", "b"), []byte("2"))
+	base.Set(key("p", "c"), []byte("3"))
+	base.Set(key("q", "a"), []byte("4"))
+
+	s := NewOverlayStore(base)
+	s.Delete(key("p", "b"))
+	s.Set(key("p", "c"), []byte("changed"))
+	s.Set(key("p", "0"), []byte("new"))
+	s.Set(key("q", "b"), []byte("5"))
+
+	require.Equal(t, plugin.RangeData{
+		{Key: []byte("0"), Value: []byte("new")},
+		{Key: []byte("a"), Value: []byte("1")},
+		{Key: []byte("c"), Value: []byte("changed")},
+	}, s.Range([]byte("p")))
+	require.Equal(t, plugin.RangeData{
+		{Key: []byte("a"), Value: []byte("4")},
+		{Key: []byte("b"), Value: []byte("5")},
+	}, s.Range([]byte("q")))
+	require.Empty(t, s.Range([]byte("r")))
+}
+
+func TestOverlayStoreWriteSet(t *testing.T) {
+	base := NewMemStore()
+	base.Set([]byte("a"), []byte("1"))
+
+	s := NewOverlayStore(base)
+	require.Empty(t, s.WriteSet())
+
+	s.Set([]byte("c"), []byte("3"))
+	s.Delete([]byte("a"))
+	s.Set([]byte("b"), []byte("2"))
+	require.Equal(t, []WriteSetEntry{
+		{Key: []byte("a"), Deleted: true},
+		{Key: []byte("b"), Value: []byte("2")},
+		{Key: []byte("c"), Value: []byte("3")},
+	}, s.WriteSet())
+}
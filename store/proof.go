@@ -0,0 +1,237 @@
+package store
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/loomnetwork/go-loom/plugin"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/pkg/errors"
+	"github.com/tendermint/iavl"
+)
+
+// StateProver is implemented by versioned stores that can generate Merkle proofs for the keys stored
+// in previously saved versions of the store. The proofs are generated against the root hash of the
+// IAVL tree at the requested version, which is the app hash stored in the header of the next block.
+type StateProver interface {
+	// GetWithProofAt returns the value of the given key at the given version, along with a proof of
+	// existence (or absence if the value is nil).
+	GetWithProofAt(key []byte, version int64) ([]byte, *iavl.RangeProof, error)
+	// GetRangeWithProofAt returns all the keys & values with the given prefix at the given version,
+	// along with a proof of their existence. The returned keys still contain the prefix.
+	GetRangeWithProofAt(prefix []byte, version int64) ([][]byte, [][]byte, *iavl.RangeProof, error)
+}
+
+// KeyProof is a Merkle proof for a single key, or for all the keys with a common prefix.
+type KeyProof struct {
+	// Key that was read, or the prefix that was iterated over if IsRange is set.
+	Key     []byte
+	IsRange bool
+	// Keys & values covered by the proof, empty if the key doesn't exist.
+	Keys   [][]byte
+	Values [][]byte
+	Proof  *iavl.RangeProof
+}
+
+// Verify checks that the proof is valid for the given root hash (app hash).
+func (p *KeyProof) Verify(rootHash []byte) error {
+	if p.Proof == nil {
+		return errors.New("missing proof")
+	}
+	if len(p.Keys) != len(p.Values) {
+		return errors.New("mismatched keys & values")
+	}
+	if err := p.Proof.Verify(rootHash); err != nil {
+		return err
+	}
+	if !p.IsRange && len(p.Keys) == 0 {
+		return p.Proof.VerifyAbsence(p.Key)
+	}
+	if p.IsRange {
+		if err := p.verifyRangeIsComplete(); err != nil {
+			return err
+		}
+	}
+	for i, key := range p.Keys {
+		if p.IsRange && !bytes.HasPrefix(key, p.Key) {
+			return errors.Errorf("key %X doesn't have prefix %X", key, p.Key)
+		}
+		if !p.IsRange && !bytes.Equal(key, p.Key) {
+			return errors.Errorf("proof is for key %X instead of %X", key, p.Key)
+		}
+		if err := p.Proof.VerifyItem(key, p.Values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyRangeIsComplete checks that the (already verified) range proof covers the whole prefix range,
+// and that all the keys in the range that are covered by the proof have been returned.
+func (p *KeyProof) verifyRangeIsComplete() error {
+	leafKeys := p.Proof.Keys()
+	if len(leafKeys) == 0 {
+		return errors.New("range proof has no leaves")
+	}
+	end := prefixRangeEnd(p.Key)
+	// Every proof leaf within the range must've been returned, the leaves outside the range are
+	// the neighbours that bound it.
+	numKeysInRange := 0
+	for _, key := range leafKeys {
+		if bytes.Compare(key, p.Key) >= 0 && (end == nil || bytes.Compare(key, end) < 0) {
+			numKeysInRange++
+		}
+	}
+	if numKeysInRange != len(p.Keys) {
+		return errors.Errorf("range proof covers %d keys, but %d keys were returned", numKeysInRange, len(p.Keys))
+	}
+	// The proof must either include the first key before the range, or start at the first key in
+	// the tree, so the absence of a smaller key in the range is proven.
+	if first := leafKeys[0]; bytes.Compare(first, p.Key) > 0 {
+		if err := p.Proof.VerifyAbsence(p.Key); err != nil {
+			return errors.Wrap(err, "range proof doesn't cover the start of the range")
+		}
+	}
+	// Similarly the proof must either include the first key past the range, or end at the last key
+	// in the tree, otherwise the range could've been cut short.
+	if last := leafKeys[len(leafKeys)-1]; end == nil || bytes.Compare(last, end) < 0 {
+		// the successor of the last leaf can only be absent from the proof if the tree ends there
+		successor := append(append([]byte{}, last...), 0)
+		if err := p.Proof.VerifyAbsence(successor); err != nil {
+			return errors.Wrap(err, "range proof doesn't cover the end of the range")
+		}
+	}
+	return nil
+}
+
+// ProveKey generates a proof for a single key at the given version of the store.
+func ProveKey(prover StateProver, key []byte, version int64) (*KeyProof, error) {
+	value, proof, err := prover.GetWithProofAt(key, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to prove key %X", key)
+	}
+	p := &KeyProof{Key: key, Proof: proof}
+	if value != nil {
+		p.Keys = [][]byte{key}
+		p.Values = [][]byte{value}
+	}
+	return p, nil
+}
+
+// ProveRange generates a proof for all the keys with the given prefix at the given version of the
+// store.
+func ProveRange(prover StateProver, prefix []byte, version int64) (*KeyProof, error) {
+	keys, values, proof, err := prover.GetRangeWithProofAt(prefix, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to prove range %X", prefix)
+	}
+	return &KeyProof{
+		Key:     prefix,
+		IsRange: true,
+		Keys:    keys,
+		Values:  values,
+		Proof:   proof,
+	}, nil
+}
+
+func getRangeWithProof(tree *iavl.ImmutableTree, prefix []byte) ([][]byte, [][]byte, *iavl.RangeProof, error) {
+	if len(prefix) == 0 {
+		return nil, nil, nil, errors.New("range over nil prefix not supported")
+	}
+	return tree.GetRangeWithProof(prefix, prefixRangeEnd(prefix), 0)
+}
+
+// ReadRecorder is a Snapshot wrapper that keeps track of all the keys read from, and all the prefixes
+// iterated over in, the underlying snapshot. The recorded reads can then be proven to a client.
+type ReadRecorder struct {
+	Snapshot
+	mutex    sync.Mutex
+	keys     map[string]struct{}
+	prefixes map[string]struct{}
+}
+
+var _ Snapshot = &ReadRecorder{}
+
+// NewReadRecorder creates a new ReadRecorder on top of the given snapshot.
+func NewReadRecorder(snapshot Snapshot) *ReadRecorder {
+	return &ReadRecorder{
+		Snapshot: snapshot,
+		keys:     make(map[string]struct{}),
+		prefixes: make(map[string]struct{}),
+	}
+}
+
+func (r *ReadRecorder) Get(key []byte) []byte {
+	r.recordKey(key)
+	return r.Snapshot.Get(key)
+}
+
+func (r *ReadRecorder) Has(key []byte) bool {
+	r.recordKey(key)
+	return r.Snapshot.Has(key)
+}
+
+func (r *ReadRecorder) Range(prefix []byte) plugin.RangeData {
+	r.mutex.Lock()
+	r.prefixes[string(prefix)] = struct{}{}
+	r.mutex.Unlock()
+	return r.Snapshot.Range(prefix)
+}
+
+func (r *ReadRecorder) recordKey(key []byte) {
+	r.mutex.Lock()
+	r.keys[string(key)] = struct{}{}
+	r.mutex.Unlock()
+}
+
+// Prove generates proofs for all the keys & prefixes that have been read so far, ordered by key.
+// Keys that are covered by the proof of one of the recorded prefixes aren't proven individually.
+func (r *ReadRecorder) Prove(prover StateProver, version int64) ([]*KeyProof, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	prefixes := sortedKeys(r.prefixes)
+	proofs := make([]*KeyProof, 0, len(r.keys)+len(prefixes))
+	for _, prefix := range prefixes {
+		proof, err := ProveRange(prover, prefix, version)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+	for _, key := range sortedKeys(r.keys) {
+		if hasAnyPrefix(key, prefixes) {
+			continue
+		}
+		proof, err := ProveKey(prover, key, version)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+	sort.SliceStable(proofs, func(i, j int) bool {
+		return bytes.Compare(proofs[i].Key, proofs[j].Key) < 0
+	})
+	return proofs, nil
+}
+
+func sortedKeys(m map[string]struct{}) [][]byte {
+	keys := make([][]byte, 0, len(m))
+	for k := range m {
+		keys = append(keys, []byte(k))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	return keys
+}
+
+func hasAnyPrefix(key []byte, prefixes [][]byte) bool {
+	for _, prefix := range prefixes {
+		if util.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/db"
+)
+
+func TestReadRecorderProofs(t *testing.T) {
+	s, err := NewIAVLStore(db.NewMemDB(), 0, 0, 0)
+	require.NoError(t, err)
+	s.Set([]byte("a"), []byte("1"))
+	s.Set(util.PrefixKey([]byte("p"), []byte("x")), []byte("2"))
+	s.Set(util.PrefixKey([]byte("p"), []byte("y")), []byte("3"))
+	appHash, version, err := s.SaveVersion()
+	require.NoError(t, err)
+
+	recorder := NewReadRecorder(s.GetSnapshot())
+	require.Equal(t, []byte("1"), recorder.Get([]byte("a")))
+	require.False(t, recorder.Has([]byte("b")))
+	require.Len(t, recorder.Range([]byte("p")), 2)
+	// already covered by the range proof
+	recorder.Get(util.PrefixKey([]byte("p"), []byte("x")))
+
+	proofs, err := recorder.Prove(s, version)
+	require.NoError(t, err)
+	require.Len(t, proofs, 3)
+	require.Equal(t, []byte("a"), proofs[0].Key)
+	require.Equal(t, [][]byte{[]byte("1")}, proofs[0].Values)
+	require.Equal(t, []byte("b"), proofs[1].Key)
+	require.Empty(t, proofs[1].Values)
+	require.Equal(t, []byte("p"), proofs[2].Key)
+	require.True(t, proofs[2].IsRange)
+	require.Len(t, proofs[2].Keys, 2)
+	for _, proof := range proofs {
+		require.NoError(t, proof.Verify(appHash))
+	}
+
+	// tampered values shouldn't verify
+	proofs[0].Values[0] = []byte("4")
+	require.Error(t, proofs[0].Verify(appHash))
+	require.Error(t, proofs[2].Verify([]byte("invalid root")))
+}
+
+func TestRangeProofCompleteness(t *testing.T) {
+	s, err := NewIAVLStore(db.NewMemDB(), 0, 0, 0)
+	require.NoError(t, err)
+	s.Set([]byte("a"), []byte("0"))
+	for i := 0; i < 10; i++ {
+		s.Set(util.PrefixKey([]byte("p"), []byte{byte('a' + i)}), []byte{byte(i)})
+	}
+	s.Set([]byte("z"), []byte("0"))
+	appHash, version, err := s.SaveVersion()
+	require.NoError(t, err)
+
+	prefix := []byte("p")
+	proof, err := ProveRange(s, prefix, version)
+	require.NoError(t, err)
+	require.Len(t, proof.Keys, 10)
+	require.NoError(t, proof.Verify(appHash))
+
+	// ranges at the edges of the tree are bounded by the first & last keys in the tree
+	proof, err = ProveRange(s, []byte("z"), version)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(appHash))
+	proof, err = ProveRange(s, []byte("a"), version)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(appHash))
+
+	tree, err := s.tree.GetImmutable(version)
+	require.NoError(t, err)
+	truncatedProof := func(end []byte, limit int) *KeyProof {
+		keys, values, rangeProof, err := tree.GetRangeWithProof(prefix, end, limit)
+		require.NoError(t, err)
+		return &KeyProof{Key: prefix, IsRange: true, Keys: keys, Values: values, Proof: rangeProof}
+	}
+
+	// a range that's been cut short shouldn't verify
+	require.Error(t, truncatedProof(util.PrefixKey(prefix, []byte("e")), 0).Verify(appHash))
+	require.Error(t, truncatedProof(prefixRangeEnd(prefix), 3).Verify(appHash))
+
+	// neither should a range with some of the keys covered by the proof withheld
+	proof, err = ProveRange(s, prefix, version)
+	require.NoError(t, err)
+	proof.Keys = proof.Keys[1:]
+	proof.Values = proof.Values[1:]
+	require.Error(t, proof.Verify(appHash))
+}
@@ -0,0 +1,770 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	gcommon "github.com/ethereum/go-ethereum/common"
+	gstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/loomnetwork/loomchain/db"
+	"github.com/loomnetwork/loomchain/log"
+	"github.com/pkg/errors"
+	amino "github.com/tendermint/go-amino"
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const (
+	// StateSnapshotManifestFile is the name of the file the snapshot manifest is written to.
+	StateSnapshotManifestFile = "manifest.json"
+	// DefaultStateSnapshotChunkSize is the default max number of bytes written to each snapshot chunk.
+	DefaultStateSnapshotChunkSize = 16 * 1024 * 1024
+
+	stateSnapshotFormat = 1
+)
+
+// Each entry in a snapshot chunk is tagged with the store it belongs to.
+const (
+	stateSnapshotAppEntry byte = 1
+	stateSnapshotEvmEntry byte = 2
+)
+
+// StateSnapshotChunk describes a single chunk of a state snapshot.
+type StateSnapshotChunk struct {
+	Index      int    `json:"index"`
+	Hash       string `json:"hash"` // hex encoded SHA256 hash of the chunk file
+	NumEntries int    `json:"numEntries"`
+}
+
+// StateSnapshotManifest describes a state snapshot exported from a MultiWriterAppStore.
+type StateSnapshotManifest struct {
+	Format     int                  `json:"format"`
+	Version    int64                `json:"version"`
+	AppHash    string               `json:"appHash"` // hex encoded IAVL root hash at Version
+	EvmRoot    string               `json:"evmRoot"` // hex encoded EVM Patricia root at Version
+	NumAppKeys uint64               `json:"numAppKeys"`
+	NumEvmKeys uint64               `json:"numEvmKeys"`
+	Chunks     []StateSnapshotChunk `json:"chunks"`
+}
+
+func stateSnapshotChunkFile(index int) string {
+	return fmt.Sprintf("chunk-%06d.bin", index)
+}
+
+// ReadStateSnapshotManifest loads the manifest of the snapshot stored in the given directory.
+func ReadStateSnapshotManifest(dir string) (*StateSnapshotManifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, StateSnapshotManifestFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snapshot manifest")
+	}
+	var manifest StateSnapshotManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot manifest")
+	}
+	if manifest.Format != stateSnapshotFormat {
+		return nil, fmt.Errorf("unsupported snapshot format %d", manifest.Format)
+	}
+	return &manifest, nil
+}
+
+// stateSnapshotWriter buffers snapshot entries and writes them out to chunk files in a directory.
+type stateSnapshotWriter struct {
+	dir        string
+	chunkSize  int
+	buf        bytes.Buffer
+	numEntries int
+	chunks     []StateSnapshotChunk
+}
+
+func (w *stateSnapshotWriter) add(kind byte, key, value []byte) error {
+	var lenBuf [binary.MaxVarintLen64]byte
+	w.buf.WriteByte(kind)
+	n := binary.PutUvarint(lenBuf[:], uint64(len(key)))
+	w.buf.Write(lenBuf[:n])
+	w.buf.Write(key)
+	n = binary.PutUvarint(lenBuf[:], uint64(len(value)))
+	w.buf.Write(lenBuf[:n])
+	w.buf.Write(value)
+	w.numEntries++
+
+	if w.buf.Len() >= w.chunkSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *stateSnapshotWriter) flush() error {
+	if w.numEntries == 0 {
+		return nil
+	}
+	index := len(w.chunks)
+	data := w.buf.Bytes()
+	if err := ioutil.WriteFile(filepath.Join(w.dir, stateSnapshotChunkFile(index)), data, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write snapshot chunk %d", index)
+	}
+	hash := sha256.Sum256(data)
+	w.chunks = append(w.chunks, StateSnapshotChunk{
+		Index:      index,
+		Hash:       hex.EncodeToString(hash[:]),
+		NumEntries: w.numEntries,
+	})
+	w.buf.Reset()
+	w.numEntries = 0
+	return nil
+}
+
+// ExportStateSnapshot writes out the IAVL tree & the EVM state at the given version to a set of
+// hashed chunk files in the given directory, along with a manifest describing the snapshot.
+// If version is zero the latest saved version of the store will be exported.
+// chunkSize specifies the (approximate) max number of bytes written to each chunk.
+//
+// The IAVL tree is exported as the raw nodes stored in the app DB, rather than as key/value pairs,
+// because the hash of each node depends on the version the node was last modified at, and the
+// tree wouldn't hash to the same root if it were rebuilt from its key/value pairs.
+//
+// NOTE: The DBs must not be modified while the snapshot is being exported.
+func ExportStateSnapshot(
+	appDB dbm.DB, evmDB db.DBWrapper, version int64, dir string, chunkSize int,
+) (*StateSnapshotManifest, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultStateSnapshotChunkSize
+	}
+
+	iavlStore, err := NewIAVLStore(appDB, 0, version, 0)
+	if err != nil {
+		return nil, err
+	}
+	version = iavlStore.Version()
+	evmStore := NewEvmStore(evmDB, 100)
+	if err := evmStore.LoadVersion(version); err != nil {
+		return nil, err
+	}
+	// make sure the EVM root tied up with the IAVL tree matches the one in the EVM DB
+	if _, err := NewMultiWriterAppStore(iavlStore, evmStore, false); err != nil {
+		return nil, err
+	}
+
+	appRoot := appDB.Get(iavlRootKeyFormat.Key(version))
+	if appRoot == nil {
+		return nil, errors.Errorf("failed to load IAVL root for version %d", version)
+	}
+	evmRoot, evmRootVersion := evmStore.getLastSavedRoot(version)
+	if evmRoot == nil {
+		return nil, errors.Errorf("failed to load EVM root for version %d", version)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create snapshot dir %s", dir)
+	}
+
+	manifest := &StateSnapshotManifest{
+		Format:  stateSnapshotFormat,
+		Version: version,
+		AppHash: hex.EncodeToString(appRoot),
+		EvmRoot: hex.EncodeToString(evmRoot),
+	}
+	w := &stateSnapshotWriter{
+		dir:       dir,
+		chunkSize: chunkSize,
+	}
+
+	// The IAVL root is written out first, followed by the tree nodes in pre-order, which allows the
+	// import to verify the tree one chunk at a time.
+	if err := w.add(stateSnapshotAppEntry, iavlRootKeyFormat.Key(version), appRoot); err != nil {
+		return nil, err
+	}
+	manifest.NumAppKeys++
+	err = walkIAVLTree(appDB.Get, appRoot, func(key, value []byte) error {
+		if err := w.add(stateSnapshotAppEntry, key, value); err != nil {
+			return err
+		}
+		manifest.NumAppKeys++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the Patricia nodes & contract code reachable from the EVM root at the exported version
+	// are written out, anything else in the EVM DB belongs to other versions of the EVM state.
+	if err := w.add(stateSnapshotEvmEntry, evmRootKey(evmRootVersion), evmRoot); err != nil {
+		return nil, err
+	}
+	manifest.NumEvmKeys++
+	snap := evmDB.GetSnapshot()
+	defer snap.Release()
+	err = walkEvmState(snap.Get, evmRoot, func(key, value []byte) error {
+		if err := w.add(stateSnapshotEvmEntry, key, value); err != nil {
+			return err
+		}
+		manifest.NumEvmKeys++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.flush(); err != nil {
+		return nil, err
+	}
+	manifest.Chunks = w.chunks
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal snapshot manifest")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, StateSnapshotManifestFile), data, 0644); err != nil {
+		return nil, errors.Wrap(err, "failed to write snapshot manifest")
+	}
+	return manifest, nil
+}
+
+// ImportStateSnapshot loads the snapshot stored in the given directory into empty app & EVM DBs.
+// Nothing is written to the DBs until every chunk has been checked against the hash recorded in
+// the manifest, and every entry in the chunks has been checked against the IAVL root & EVM root in
+// the manifest. The chunks are then written out one at a time, and once they've all been written
+// the EVM state trie is walked to verify every node reachable from the EVM root is present.
+// If the import fails after anything has been written the DBs are cleared out again.
+func ImportStateSnapshot(dir string, appDB dbm.DB, evmDB db.DBWrapper) (*StateSnapshotManifest, error) {
+	manifest, err := ReadStateSnapshotManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	expectedAppHash, err := hex.DecodeString(manifest.AppHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid app hash in snapshot manifest")
+	}
+	expectedEvmRoot, err := hex.DecodeString(manifest.EvmRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid EVM root in snapshot manifest")
+	}
+
+	if !isDBEmpty(appDB) {
+		return nil, errors.New("app DB must be empty to import a snapshot")
+	}
+	if !isDBEmpty(evmDB) {
+		return nil, errors.New("EVM DB must be empty to import a snapshot")
+	}
+
+	if err := verifyStateSnapshot(dir, manifest, expectedAppHash, expectedEvmRoot); err != nil {
+		return nil, err
+	}
+
+	if err := writeStateSnapshot(dir, manifest, appDB, evmDB, expectedAppHash, expectedEvmRoot); err != nil {
+		// both DBs were empty before the import started, so clear out whatever was written to them
+		clearDB(appDB)
+		clearDB(evmDB)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// verifyStateSnapshot checks every chunk of a snapshot against the manifest without writing
+// anything out. The IAVL tree nodes are verified against the IAVL root as they're read, the EVM
+// state nodes & contract code are only checked against their own hashes since the EVM state
+// trie can't be walked until all of it has been written to the EVM DB.
+func verifyStateSnapshot(dir string, manifest *StateSnapshotManifest, appHash, evmRoot []byte) error {
+	appVerifier := &iavlSnapshotVerifier{version: manifest.Version, root: appHash}
+	var numAppKeys, numEvmKeys uint64
+	numEvmRoots := 0
+	for i, chunk := range manifest.Chunks {
+		data, err := readStateSnapshotChunkFile(dir, i, chunk)
+		if err != nil {
+			return err
+		}
+		numEntries, err := readStateSnapshotChunk(data, func(kind byte, key, value []byte) error {
+			switch kind {
+			case stateSnapshotAppEntry:
+				numAppKeys++
+				return appVerifier.add(key, value)
+			case stateSnapshotEvmEntry:
+				numEvmKeys++
+				isRoot, err := checkStateSnapshotEvmEntry(key, value, manifest.Version)
+				if err != nil {
+					return err
+				}
+				if isRoot {
+					if !bytes.Equal(value, evmRoot) {
+						return errors.Errorf("snapshot EVM root %X, expected %X", value, evmRoot)
+					}
+					numEvmRoots++
+				}
+				return nil
+			default:
+				return errors.Errorf("invalid snapshot entry type %d", kind)
+			}
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to verify snapshot chunk %d", i)
+		}
+		if numEntries != chunk.NumEntries {
+			return errors.Errorf(
+				"snapshot chunk %d has %d entries, expected %d", i, numEntries, chunk.NumEntries,
+			)
+		}
+		log.Debug("[StateSnapshot] Verified chunk", "index", i, "entries", numEntries)
+	}
+	if err := appVerifier.finish(); err != nil {
+		return err
+	}
+	if numEvmRoots != 1 {
+		return errors.Errorf("snapshot contains %d EVM roots, expected 1", numEvmRoots)
+	}
+	if numAppKeys != manifest.NumAppKeys || numEvmKeys != manifest.NumEvmKeys {
+		return errors.Errorf(
+			"snapshot contains %d app keys & %d EVM keys, expected %d & %d",
+			numAppKeys, numEvmKeys, manifest.NumAppKeys, manifest.NumEvmKeys,
+		)
+	}
+	return nil
+}
+
+// writeStateSnapshot writes out a snapshot that has already been verified by verifyStateSnapshot,
+// each chunk is written to the DBs in its own batch.
+func writeStateSnapshot(
+	dir string, manifest *StateSnapshotManifest, appDB dbm.DB, evmDB db.DBWrapper, appHash, evmRoot []byte,
+) error {
+	for i, chunk := range manifest.Chunks {
+		// the chunk is hashed again in case it was modified after it was verified
+		data, err := readStateSnapshotChunkFile(dir, i, chunk)
+		if err != nil {
+			return err
+		}
+		appBatch := appDB.NewBatch()
+		evmBatch := evmDB.NewBatch()
+		_, err = readStateSnapshotChunk(data, func(kind byte, key, value []byte) error {
+			if kind == stateSnapshotAppEntry {
+				appBatch.Set(key, value)
+			} else {
+				evmBatch.Set(key, value)
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to read snapshot chunk %d", i)
+		}
+		appBatch.Write()
+		evmBatch.Write()
+		log.Debug("[StateSnapshot] Imported chunk", "index", i)
+	}
+
+	err := walkEvmState(evmDB.Get, evmRoot, func(key, value []byte) error { return nil })
+	if err != nil {
+		return errors.Wrap(err, "failed to verify imported EVM state")
+	}
+
+	iavlStore, err := NewIAVLStore(appDB, 0, manifest.Version, 0)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(iavlStore.Hash(), appHash) {
+		return errors.Errorf("imported tree hash %X, expected %X", iavlStore.Hash(), appHash)
+	}
+	evmStore := NewEvmStore(evmDB, 1)
+	if err := evmStore.LoadVersion(manifest.Version); err != nil {
+		return err
+	}
+	// Finally make sure the EVM root tied up with the IAVL tree matches the one in the EVM DB.
+	if _, err := NewMultiWriterAppStore(iavlStore, evmStore, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readStateSnapshotChunkFile loads a chunk file & checks it against the hash in the manifest.
+func readStateSnapshotChunkFile(dir string, index int, chunk StateSnapshotChunk) ([]byte, error) {
+	if chunk.Index != index {
+		return nil, errors.Errorf("snapshot chunk %d is out of order", chunk.Index)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, stateSnapshotChunkFile(index)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read snapshot chunk %d", index)
+	}
+	hash := sha256.Sum256(data)
+	if hex.EncodeToString(hash[:]) != chunk.Hash {
+		return nil, errors.Errorf("snapshot chunk %d hash mismatch", index)
+	}
+	return data, nil
+}
+
+// checkStateSnapshotEvmEntry verifies an EVM DB entry from a snapshot is either an EVM root saved at
+// or before the snapshot version, or a Patricia node / contract code blob keyed by its own hash.
+// Returns true if the entry is an EVM root.
+func checkStateSnapshotEvmEntry(key, value []byte, version int64) (bool, error) {
+	if util.HasPrefix(key, util.PrefixKey(vmPrefix, evmRootPrefix)) {
+		rootVersion, err := getVersionFromEvmRootKey(key)
+		if err != nil {
+			return false, err
+		}
+		if rootVersion > version {
+			return false, errors.Errorf("snapshot contains EVM root for version %d", rootVersion)
+		}
+		return true, nil
+	}
+	hash, err := util.UnprefixKey(key, vmPrefix)
+	if err != nil || len(hash) != gcommon.HashLength {
+		return false, errors.Errorf("invalid EVM key %X in snapshot", key)
+	}
+	if !bytes.Equal(crypto.Keccak256(value), hash) {
+		return false, errors.Errorf("EVM state node %X hash mismatch", hash)
+	}
+	return false, nil
+}
+
+func readStateSnapshotChunk(data []byte, fn func(kind byte, key, value []byte) error) (int, error) {
+	numEntries := 0
+	r := bytes.NewReader(data)
+	for r.Len() > 0 {
+		kind, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		key, err := readStateSnapshotBytes(r)
+		if err != nil {
+			return 0, err
+		}
+		value, err := readStateSnapshotBytes(r)
+		if err != nil {
+			return 0, err
+		}
+		if err := fn(kind, key, value); err != nil {
+			return 0, err
+		}
+		numEntries++
+	}
+	return numEntries, nil
+}
+
+func readStateSnapshotBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()) {
+		return nil, errors.New("truncated snapshot entry")
+	}
+	b := make([]byte, n)
+	if _, err := r.Read(b); err != nil && n > 0 {
+		return nil, err
+	}
+	return b, nil
+}
+
+func isDBEmpty(d dbm.DB) bool {
+	it := d.Iterator(nil, nil)
+	defer it.Close()
+	return !it.Valid()
+}
+
+// clearDB deletes every key in the given DB, the keys are deleted in batches to keep memory usage
+// down, and so the DB isn't modified while it's being iterated.
+func clearDB(d dbm.DB) {
+	const batchSize = 10000
+	for {
+		var keys [][]byte
+		it := d.Iterator(nil, nil)
+		for ; it.Valid() && len(keys) < batchSize; it.Next() {
+			keys = append(keys, it.Key())
+		}
+		it.Close()
+		if len(keys) == 0 {
+			return
+		}
+		batch := d.NewBatch()
+		for _, key := range keys {
+			batch.Delete(key)
+		}
+		batch.Write()
+	}
+}
+
+var (
+	// Keys of the IAVL tree nodes & roots in the app DB, these must match the ones in the iavl package.
+	iavlNodeKeyFormat = iavl.NewKeyFormat('n', tmhash.Size) // n<hash>
+	iavlRootKeyFormat = iavl.NewKeyFormat('r', 8)           // r<version>
+)
+
+// iavlNode contains the fields of an IAVL tree node that are needed to walk the tree & verify the
+// hash of each node, it's decoded from a node stored in the app DB.
+type iavlNode struct {
+	height    int8
+	size      int64
+	version   int64
+	key       []byte
+	value     []byte
+	leftHash  []byte
+	rightHash []byte
+}
+
+// decodeIAVLNode decodes a node in the format the iavl package stores nodes in.
+func decodeIAVLNode(buf []byte) (*iavlNode, error) {
+	var node iavlNode
+	var n int
+	var err error
+	if node.height, n, err = amino.DecodeInt8(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode IAVL node height")
+	}
+	buf = buf[n:]
+	if node.size, n, err = amino.DecodeVarint(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode IAVL node size")
+	}
+	buf = buf[n:]
+	if node.version, n, err = amino.DecodeVarint(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode IAVL node version")
+	}
+	buf = buf[n:]
+	if node.key, n, err = amino.DecodeByteSlice(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode IAVL node key")
+	}
+	buf = buf[n:]
+	if node.isLeaf() {
+		if node.value, _, err = amino.DecodeByteSlice(buf); err != nil {
+			return nil, errors.Wrap(err, "failed to decode IAVL node value")
+		}
+		return &node, nil
+	}
+	if node.leftHash, n, err = amino.DecodeByteSlice(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode IAVL node left hash")
+	}
+	buf = buf[n:]
+	if node.rightHash, _, err = amino.DecodeByteSlice(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode IAVL node right hash")
+	}
+	if len(node.leftHash) == 0 || len(node.rightHash) == 0 {
+		return nil, errors.New("IAVL inner node is missing a child hash")
+	}
+	return &node, nil
+}
+
+func (n *iavlNode) isLeaf() bool {
+	return n.height == 0
+}
+
+// hash computes the hash of the node the same way the iavl package does.
+func (n *iavlNode) hash() []byte {
+	var buf bytes.Buffer
+	// writes to a bytes.Buffer can't fail
+	_ = amino.EncodeInt8(&buf, n.height)
+	_ = amino.EncodeVarint(&buf, n.size)
+	_ = amino.EncodeVarint(&buf, n.version)
+	if n.isLeaf() {
+		_ = amino.EncodeByteSlice(&buf, n.key)
+		_ = amino.EncodeByteSlice(&buf, tmhash.Sum(n.value))
+	} else {
+		_ = amino.EncodeByteSlice(&buf, n.leftHash)
+		_ = amino.EncodeByteSlice(&buf, n.rightHash)
+	}
+	return tmhash.Sum(buf.Bytes())
+}
+
+// loadIAVLNode decodes an IAVL tree node, and checks the node matches the hash it's referenced by.
+func loadIAVLNode(hash, value []byte) (*iavlNode, error) {
+	node, err := decodeIAVLNode(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid IAVL node %X", hash)
+	}
+	if !bytes.Equal(node.hash(), hash) {
+		return nil, errors.Errorf("IAVL node %X hash mismatch", hash)
+	}
+	return node, nil
+}
+
+// walkIAVLTree visits every node of the IAVL tree with the given root hash in pre-order (each node
+// is visited before its left & right subtrees), fn is called with the key each one is stored under
+// in the app DB. Every node is checked against the hash it's referenced by before it's passed to fn.
+func walkIAVLTree(get func(key []byte) []byte, hash []byte, fn func(key, value []byte) error) error {
+	if len(hash) == 0 {
+		return nil
+	}
+	key := iavlNodeKeyFormat.KeyBytes(hash)
+	value := get(key)
+	if value == nil {
+		return errors.Errorf("missing IAVL node %X", hash)
+	}
+	node, err := loadIAVLNode(hash, value)
+	if err != nil {
+		return err
+	}
+	if err := fn(key, value); err != nil {
+		return err
+	}
+	if node.isLeaf() {
+		return nil
+	}
+	if err := walkIAVLTree(get, node.leftHash, fn); err != nil {
+		return err
+	}
+	return walkIAVLTree(get, node.rightHash, fn)
+}
+
+// iavlSnapshotVerifier checks the IAVL entries in a snapshot make up the tree with the expected
+// root, one entry at a time. The root entry must come first, followed by the tree nodes in the same
+// order walkIAVLTree visits them, so only the hashes of the nodes that haven't been reached yet
+// along the current path through the tree have to be kept around.
+type iavlSnapshotVerifier struct {
+	version  int64
+	root     []byte
+	hasRoot  bool
+	expected [][]byte // stack of the hashes of the nodes that are expected next
+}
+
+func (v *iavlSnapshotVerifier) add(key, value []byte) error {
+	if !v.hasRoot {
+		if !bytes.Equal(key, iavlRootKeyFormat.Key(v.version)) {
+			return errors.Errorf("expected IAVL root for version %d, got key %X", v.version, key)
+		}
+		if !bytes.Equal(value, v.root) {
+			return errors.Errorf("snapshot IAVL root %X, expected %X", value, v.root)
+		}
+		v.hasRoot = true
+		if len(value) > 0 {
+			v.expected = append(v.expected, value)
+		}
+		return nil
+	}
+
+	if len(v.expected) == 0 {
+		return errors.Errorf("unexpected IAVL key %X in snapshot", key)
+	}
+	hash := v.expected[len(v.expected)-1]
+	v.expected = v.expected[:len(v.expected)-1]
+	if !bytes.Equal(key, iavlNodeKeyFormat.KeyBytes(hash)) {
+		return errors.Errorf("unexpected IAVL key %X in snapshot, expected node %X", key, hash)
+	}
+	node, err := loadIAVLNode(hash, value)
+	if err != nil {
+		return err
+	}
+	if !node.isLeaf() {
+		v.expected = append(v.expected, node.rightHash, node.leftHash)
+	}
+	return nil
+}
+
+// finish checks that every node in the tree has been seen.
+func (v *iavlSnapshotVerifier) finish() error {
+	if !v.hasRoot {
+		return errors.New("snapshot doesn't contain the IAVL root")
+	}
+	if len(v.expected) > 0 {
+		return errors.Errorf("missing IAVL node %X", v.expected[len(v.expected)-1])
+	}
+	return nil
+}
+
+var (
+	// Root hash of an empty Patricia trie.
+	emptyEvmTrieRoot = gcommon.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	// Hash of empty contract code.
+	emptyEvmCodeHash = crypto.Keccak256Hash(nil)
+)
+
+// walkEvmState visits every Patricia node & contract code blob reachable from the given EVM state
+// root, fn is called with the key each one is stored under in the EVM DB. Every node is checked
+// against the hash it's referenced by before it's passed to fn.
+func walkEvmState(get func(key []byte) []byte, root []byte, fn func(key, value []byte) error) error {
+	if len(root) == 0 || bytes.Equal(root, defaultRoot) {
+		return nil
+	}
+	trieDB := trie.NewDatabase(&evmTrieReader{get: get})
+
+	visit := func(hash gcommon.Hash) error {
+		key := util.PrefixKey(vmPrefix, hash[:])
+		value := get(key)
+		if value == nil {
+			return errors.Errorf("missing EVM state node %X", hash)
+		}
+		if crypto.Keccak256Hash(value) != hash {
+			return errors.Errorf("EVM state node %X hash mismatch", hash)
+		}
+		return fn(key, value)
+	}
+
+	walkTrie := func(root gcommon.Hash, onLeaf func(blob []byte) error) error {
+		t, err := trie.New(root, trieDB)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open EVM trie %X", root)
+		}
+		it := t.NodeIterator(nil)
+		for it.Next(true) {
+			// embedded nodes don't have a hash, they're stored as part of their parent
+			if hash := it.Hash(); hash != (gcommon.Hash{}) {
+				if err := visit(hash); err != nil {
+					return err
+				}
+			}
+			if it.Leaf() && onLeaf != nil {
+				if err := onLeaf(it.LeafBlob()); err != nil {
+					return err
+				}
+			}
+		}
+		return errors.Wrapf(it.Error(), "failed to iterate EVM trie %X", root)
+	}
+
+	// contracts can share storage tries & code, no point walking them more than once
+	visited := map[gcommon.Hash]bool{}
+	return walkTrie(gcommon.BytesToHash(root), func(blob []byte) error {
+		var account gstate.Account
+		if err := rlp.DecodeBytes(blob, &account); err != nil {
+			return errors.Wrap(err, "failed to decode EVM account")
+		}
+		if account.Root != emptyEvmTrieRoot && !visited[account.Root] {
+			visited[account.Root] = true
+			if err := walkTrie(account.Root, nil); err != nil {
+				return err
+			}
+		}
+		codeHash := gcommon.BytesToHash(account.CodeHash)
+		if codeHash != emptyEvmCodeHash && !visited[codeHash] {
+			visited[codeHash] = true
+			return visit(codeHash)
+		}
+		return nil
+	})
+}
+
+// evmTrieReader provides the go-ethereum trie package read-only access to the Patricia nodes
+// stored in the EVM DB.
+type evmTrieReader struct {
+	get func(key []byte) []byte
+}
+
+func (r *evmTrieReader) Get(key []byte) ([]byte, error) {
+	if value := r.get(util.PrefixKey(vmPrefix, key)); value != nil {
+		return value, nil
+	}
+	return nil, errors.Errorf("EVM state node %X not found", key)
+}
+
+func (r *evmTrieReader) Has(key []byte) (bool, error) {
+	return r.get(util.PrefixKey(vmPrefix, key)) != nil, nil
+}
+
+func (r *evmTrieReader) Put(key []byte, value []byte) error {
+	return errors.New("EVM trie reader is read-only")
+}
+
+func (r *evmTrieReader) Delete(key []byte) error {
+	return errors.New("EVM trie reader is read-only")
+}
+
+func (r *evmTrieReader) Close() {
+}
+
+func (r *evmTrieReader) NewBatch() ethdb.Batch {
+	panic("EVM trie reader is read-only")
+}
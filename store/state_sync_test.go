@@ -0,0 +1,265 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gcommon "github.com/ethereum/go-ethereum/common"
+	gstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/loomnetwork/loomchain/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSnapshotExportImport(t *testing.T) {
+	store, srcAppDB, srcEvmDB := newStateSnapshotTestStore(t)
+
+	store.Set(evmDBFeatureKey, []byte{1})
+	store.Set([]byte("abcd"), []byte("world"))
+	_, _, err := store.SaveVersion()
+	require.NoError(t, err)
+
+	evmRoot, _, evmNodes := buildTestEvmState(t)
+	for key, value := range evmNodes {
+		store.Set(util.PrefixKey(vmPrefix, []byte(key)), value)
+	}
+	store.Set(rootHashKey, evmRoot)
+	// not reachable from the EVM root so shouldn't end up in the snapshot
+	store.Set(vmPrefixKey("efgh"), []byte("evm"))
+	store.Set([]byte("efgh"), []byte("app"))
+	for i := 0; i < 100; i++ {
+		store.Set([]byte{'k', byte(i)}, []byte{'v', byte(i)})
+	}
+	hash, version, err := store.SaveVersion()
+	require.NoError(t, err)
+
+	// this version shouldn't end up in the snapshot
+	store.Set([]byte("abcd"), []byte("changed"))
+	store.Set(rootHashKey, []byte("newroot"))
+	_, _, err = store.SaveVersion()
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "state-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	manifest, err := ExportStateSnapshot(srcAppDB, srcEvmDB, version, dir, 64)
+	require.NoError(t, err)
+	require.Equal(t, version, manifest.Version)
+	require.True(t, len(manifest.Chunks) > 1)
+	// the EVM root key, plus every trie node & the contract code
+	require.Equal(t, uint64(len(evmNodes)+1), manifest.NumEvmKeys)
+
+	appDB, _ := db.LoadMemDB()
+	evmDB, _ := db.LoadMemDB()
+	imported, err := ImportStateSnapshot(dir, appDB, evmDB)
+	require.NoError(t, err)
+	require.Equal(t, manifest.AppHash, imported.AppHash)
+
+	iavlStore, err := NewIAVLStore(appDB, 0, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, version, iavlStore.Version())
+	require.Equal(t, hash, iavlStore.Hash())
+	evmStore := NewEvmStore(evmDB, 10)
+	require.NoError(t, evmStore.LoadVersion(version))
+	importedStore, err := NewMultiWriterAppStore(iavlStore, evmStore, false)
+	require.NoError(t, err)
+	require.Equal(t, []byte("world"), importedStore.Get([]byte("abcd")))
+	require.Equal(t, []byte{'v', 99}, importedStore.Get([]byte{'k', 99}))
+	require.Equal(t, evmRoot, importedStore.Get(rootHashKey))
+	require.Nil(t, importedStore.Get(vmPrefixKey("efgh")))
+	for key, value := range evmNodes {
+		require.Equal(t, value, importedStore.Get(util.PrefixKey(vmPrefix, []byte(key))))
+	}
+
+	// importing into a DB that isn't empty should fail
+	_, err = ImportStateSnapshot(dir, appDB, evmDB)
+	require.Error(t, err)
+
+	// tampering with a chunk should fail the import
+	chunkPath := filepath.Join(dir, stateSnapshotChunkFile(0))
+	data, err := ioutil.ReadFile(chunkPath)
+	require.NoError(t, err)
+	data[len(data)-1]++
+	require.NoError(t, ioutil.WriteFile(chunkPath, data, 0644))
+	appDB, _ = db.LoadMemDB()
+	evmDB, _ = db.LoadMemDB()
+	_, err = ImportStateSnapshot(dir, appDB, evmDB)
+	require.Error(t, err)
+	require.True(t, isDBEmpty(appDB))
+	require.True(t, isDBEmpty(evmDB))
+}
+
+func TestStateSnapshotImportVerifiesEvmState(t *testing.T) {
+	store, srcAppDB, srcEvmDB := newStateSnapshotTestStore(t)
+
+	store.Set(evmDBFeatureKey, []byte{1})
+	evmRoot, storageRoot, evmNodes := buildTestEvmState(t)
+	for key, value := range evmNodes {
+		store.Set(util.PrefixKey(vmPrefix, []byte(key)), value)
+	}
+	store.Set(rootHashKey, evmRoot)
+	_, version, err := store.SaveVersion()
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "state-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = ExportStateSnapshot(srcAppDB, srcEvmDB, version, dir, 64)
+	require.NoError(t, err)
+
+	// rewrite the snapshot with consistent chunk hashes, but without one of the EVM trie nodes
+	storageRootKey := util.PrefixKey(vmPrefix, storageRoot)
+	rewriteStateSnapshot(t, dir, func(kind byte, key, value []byte) bool {
+		return kind != stateSnapshotEvmEntry || !bytes.Equal(key, storageRootKey)
+	})
+	appDB, _ := db.LoadMemDB()
+	evmDB, _ := db.LoadMemDB()
+	_, err = ImportStateSnapshot(dir, appDB, evmDB)
+	require.Error(t, err)
+	// the missing node is only detected after the chunks have been written, which should be undone
+	require.True(t, isDBEmpty(appDB))
+	require.True(t, isDBEmpty(evmDB))
+}
+
+func TestStateSnapshotImportVerifiesAppState(t *testing.T) {
+	store, srcAppDB, srcEvmDB := newStateSnapshotTestStore(t)
+
+	store.Set(evmDBFeatureKey, []byte{1})
+	for i := 0; i < 20; i++ {
+		store.Set([]byte{'k', byte(i)}, []byte{'v', byte(i)})
+	}
+	_, version, err := store.SaveVersion()
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "state-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = ExportStateSnapshot(srcAppDB, srcEvmDB, version, dir, 64)
+	require.NoError(t, err)
+
+	// rewrite the snapshot with consistent chunk hashes, but without one of the IAVL leaf nodes
+	dropped := false
+	rewriteStateSnapshot(t, dir, func(kind byte, key, value []byte) bool {
+		isNode := util.HasPrefix(key, []byte(iavlNodeKeyFormat.Prefix()))
+		if dropped || kind != stateSnapshotAppEntry || !isNode {
+			return true
+		}
+		node, err := decodeIAVLNode(value)
+		require.NoError(t, err)
+		dropped = node.isLeaf()
+		return !dropped
+	})
+	require.True(t, dropped)
+
+	appDB, _ := db.LoadMemDB()
+	evmDB, _ := db.LoadMemDB()
+	_, err = ImportStateSnapshot(dir, appDB, evmDB)
+	require.Error(t, err)
+	// the IAVL tree is verified before anything is written
+	require.True(t, isDBEmpty(appDB))
+	require.True(t, isDBEmpty(evmDB))
+}
+
+func newStateSnapshotTestStore(t *testing.T) (*MultiWriterAppStore, db.DBWrapper, db.DBWrapper) {
+	appDB, _ := db.LoadMemDB()
+	iavlStore, err := NewIAVLStore(appDB, 0, 0, -1)
+	require.NoError(t, err)
+	evmDB, _ := db.LoadMemDB()
+	store, err := NewMultiWriterAppStore(iavlStore, NewEvmStore(evmDB, 100), false)
+	require.NoError(t, err)
+	return store, appDB, evmDB
+}
+
+// rewriteStateSnapshot rewrites the snapshot in the given dir with consistent chunk hashes & key
+// counts, but only with the entries fn returns true for.
+func rewriteStateSnapshot(t *testing.T, dir string, fn func(kind byte, key, value []byte) bool) {
+	manifest, err := ReadStateSnapshotManifest(dir)
+	require.NoError(t, err)
+	manifest.NumAppKeys = 0
+	manifest.NumEvmKeys = 0
+	w := &stateSnapshotWriter{dir: dir, chunkSize: 64}
+	for i := range manifest.Chunks {
+		data, err := ioutil.ReadFile(filepath.Join(dir, stateSnapshotChunkFile(i)))
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(filepath.Join(dir, stateSnapshotChunkFile(i))))
+		_, err = readStateSnapshotChunk(data, func(kind byte, key, value []byte) error {
+			if !fn(kind, key, value) {
+				return nil
+			}
+			if kind == stateSnapshotAppEntry {
+				manifest.NumAppKeys++
+			} else {
+				manifest.NumEvmKeys++
+			}
+			return w.add(kind, key, value)
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.flush())
+	manifest.Chunks = w.chunks
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, StateSnapshotManifestFile), data, 0644))
+}
+
+var (
+	testEvmAddr = gcommon.HexToAddress("0x2b6b9a3b29a0bd4e7f0a8dd9ac6dba6e4bdcfc34")
+	testEvmCode = []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+)
+
+// buildTestEvmState creates an EVM state trie with a single contract account that has some code
+// and storage, and returns the state & storage roots along with all the nodes & code that make up
+// the state.
+func buildTestEvmState(t *testing.T) ([]byte, []byte, map[string][]byte) {
+	diskDB := ethdb.NewMemDatabase()
+	trieDB := trie.NewDatabase(diskDB)
+
+	storageTrie, err := trie.New(gcommon.Hash{}, trieDB)
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		slot := gcommon.BigToHash(big.NewInt(int64(i)))
+		value, err := rlp.EncodeToBytes(big.NewInt(int64(1000 + i)))
+		require.NoError(t, err)
+		require.NoError(t, storageTrie.TryUpdate(crypto.Keccak256(slot[:]), value))
+	}
+	storageRoot, err := storageTrie.Commit(nil)
+	require.NoError(t, err)
+	require.NoError(t, trieDB.Commit(storageRoot, false))
+
+	codeHash := crypto.Keccak256(testEvmCode)
+	require.NoError(t, diskDB.Put(codeHash, testEvmCode))
+
+	accountTrie, err := trie.New(gcommon.Hash{}, trieDB)
+	require.NoError(t, err)
+	account, err := rlp.EncodeToBytes(&gstate.Account{
+		Nonce:    1,
+		Balance:  big.NewInt(5),
+		Root:     storageRoot,
+		CodeHash: codeHash,
+	})
+	require.NoError(t, err)
+	require.NoError(t, accountTrie.TryUpdate(crypto.Keccak256(testEvmAddr[:]), account))
+	root, err := accountTrie.Commit(nil)
+	require.NoError(t, err)
+	require.NoError(t, trieDB.Commit(root, false))
+
+	nodes := map[string][]byte{}
+	for _, key := range diskDB.Keys() {
+		value, err := diskDB.Get(key)
+		require.NoError(t, err)
+		nodes[string(key)] = value
+	}
+	return root[:], storageRoot[:], nodes
+}
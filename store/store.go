@@ -3,6 +3,7 @@ package store
 import (
 	"github.com/loomnetwork/go-loom/plugin"
 	"github.com/loomnetwork/go-loom/util"
+	"github.com/pkg/errors"
 )
 
 // KVReader interface for reading data out of a store
@@ -56,6 +57,24 @@ type VersionedKVStore interface {
 	GetSnapshot() Snapshot
 }
 
+// ErrVersionNotFound is returned when a store is asked for a version that was never saved, or that
+// has already been pruned.
+var ErrVersionNotFound = errors.New("version not found")
+
+// HistoricalSnapshotter is implemented by versioned stores that can provide snapshots of previously
+// saved versions of the store.
+type HistoricalSnapshotter interface {
+	// GetSnapshotAt creates a new read-only snapshot of the store at the given version.
+	GetSnapshotAt(version int64) (Snapshot, error)
+}
+
+// WriteSetEntry is a single key that was either set or deleted.
+type WriteSetEntry struct {
+	Key     []byte
+	Value   []byte
+	Deleted bool
+}
+
 type cacheItem struct {
 	Value   []byte
 	Deleted bool
@@ -14,6 +14,7 @@ import (
 	loom "github.com/loomnetwork/go-loom"
 	"github.com/pkg/errors"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/tendermint/iavl"
 )
 
 const separator = "|"
@@ -378,6 +379,35 @@ func (c *versionedCachingStore) GetSnapshot() Snapshot {
 	)
 }
 
+// GetSnapshotAt creates a snapshot of a previously saved version of the underlying store, the cache
+// only tracks the latest version so it's bypassed entirely.
+func (c *versionedCachingStore) GetSnapshotAt(version int64) (Snapshot, error) {
+	if hs, ok := c.VersionedKVStore.(HistoricalSnapshotter); ok {
+		return hs.GetSnapshotAt(version)
+	}
+	return nil, errors.New("[VersionedCachingStore] GetSnapshotAt() not supported by underlying store")
+}
+
+// GetWithProofAt bypasses the cache and generates a proof from the underlying store.
+func (c *versionedCachingStore) GetWithProofAt(key []byte, version int64) ([]byte, *iavl.RangeProof, error) {
+	if sp, ok := c.VersionedKVStore.(StateProver); ok {
+		return sp.GetWithProofAt(key, version)
+	}
+	return nil, nil, errors.New("[VersionedCachingStore] GetWithProofAt() not supported by underlying store")
+}
+
+// GetRangeWithProofAt bypasses the cache and generates a proof from the underlying store.
+func (c *versionedCachingStore) GetRangeWithProofAt(
+	prefix []byte, version int64,
+) ([][]byte, [][]byte, *iavl.RangeProof, error) {
+	if sp, ok := c.VersionedKVStore.(StateProver); ok {
+		return sp.GetRangeWithProofAt(prefix, version)
+	}
+	return nil, nil, nil, errors.New(
+		"[VersionedCachingStore] GetRangeWithProofAt() not supported by underlying store",
+	)
+}
+
 // CachingStoreSnapshot is a read-only CachingStore with specified version
 type versionedCachingStoreSnapshot struct {
 	Snapshot
@@ -78,7 +78,9 @@ type contractTxLimiter struct {
 	contractToTierMap         map[string]udw.TierID
 	inactiveDeployerContracts map[string]bool
 	contractDataLastUpdated   int64
-	// track of no. of txns in previous blocks per contract
+	// track of no. of txns in previous blocks per contract, only contracts in contractToTierMap are
+	// tracked so the size of the map is bounded by the number of contracts registered with the
+	// user deployer whitelist
 	contractStatsMap    map[string]*contractStats
 	tierMap             map[udw.TierID]udw.Tier
 	tierDataLastUpdated int64
@@ -120,6 +122,15 @@ func (txl *contractTxLimiter) updateState(contractAddr loom.Address, curBlockHei
 	blockTx.txn++
 }
 
+// pruneContractStats discards the stats of contracts that are no longer in contractToTierMap.
+func (txl *contractTxLimiter) pruneContractStats() {
+	for contractAddr := range txl.contractStatsMap {
+		if _, ok := txl.contractToTierMap[contractAddr]; !ok {
+			delete(txl.contractStatsMap, contractAddr)
+		}
+	}
+}
+
 func loadContractTierMap(ctx contractpb.StaticContext) (*udw.ContractInfo, error) {
 	var err error
 	defer func(begin time.Time) {
@@ -211,6 +222,7 @@ func NewContractTxLimiterMiddleware(cfg *ContractTxLimiterConfig,
 			txl.contractDataLastUpdated = time.Now().Unix()
 			txl.contractToTierMap = contractInfo.ContractToTierMap
 			txl.inactiveDeployerContracts = contractInfo.InactiveDeployerContracts
+			txl.pruneContractStats()
 			// TxLimiter.contractDataLastUpdated will be updated after updating contractToTierMap
 		}
 		contractAddr := loom.UnmarshalAddressPB(msg.To)
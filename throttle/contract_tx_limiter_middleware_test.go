@@ -164,3 +164,19 @@ func TestContractTxLimiterMiddleware(t *testing.T) {
 	processMiddleware(state, txSignedEVM1.Inner)
 	require.Equal(t, allowed, false)
 }
+
+func TestContractTxLimiterPruneStats(t *testing.T) {
+	txl := &contractTxLimiter{
+		contractToTierMap: map[string]udwtypes.TierID{contractAddr.String(): udwtypes.TierID_DEFAULT},
+		contractStatsMap:  make(map[string]*contractStats),
+		tierMap:           map[udwtypes.TierID]udwtypes.Tier{udwtypes.TierID_DEFAULT: {MaxTxs: 10, BlockRange: 10}},
+	}
+	txl.updateState(contractAddr, 1)
+	txl.updateState(addr5, 1)
+	require.Len(t, txl.contractStatsMap, 2)
+
+	// stats of contracts that are no longer registered with the user deployer whitelist are discarded
+	txl.pruneContractStats()
+	require.Len(t, txl.contractStatsMap, 1)
+	require.NotNil(t, txl.contractStatsMap[contractAddr.String()])
+}
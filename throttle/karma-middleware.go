@@ -14,6 +14,7 @@ import (
 	"github.com/loomnetwork/loomchain/auth"
 	"github.com/loomnetwork/loomchain/builtin/plugins/karma"
 	"github.com/loomnetwork/loomchain/eth/utils"
+	"github.com/loomnetwork/loomchain/features"
 	"github.com/loomnetwork/loomchain/vm"
 	"github.com/pkg/errors"
 )
@@ -41,6 +42,8 @@ func GetKarmaMiddleWare(
 		if origin.IsEmpty() {
 			return res, errors.New("throttle: transaction has no origin [get-karma]")
 		}
+		// Karma is charged to the sponsor of the tx (if any), but the origin still owns any deployed contracts
+		payer := auth.Payer(state.Context())
 
 		var nonceTx lauth.NonceTx
 		if err := proto.Unmarshal(txBytes, &nonceTx); err != nil {
@@ -107,7 +110,7 @@ func GetKarmaMiddleWare(
 		if err != nil {
 			return res, errors.Wrap(err, "failed to obtain Karma Oracle address")
 		}
-		if oracleAddr != nil && origin.Compare(*oracleAddr) == 0 {
+		if oracleAddr != nil && payer.Compare(*oracleAddr) == 0 {
 			r, err := next(state, txBytes, isCheckTx)
 			if err != nil {
 				return r, err
@@ -124,13 +127,13 @@ func GetKarmaMiddleWare(
 			return r, nil
 		}
 
-		originKarma, err := th.getKarmaForTransaction(ctx, origin, isDeployTx)
+		originKarma, err := th.getKarmaForTransaction(ctx, payer, isDeployTx)
 		if err != nil {
 			return res, errors.Wrap(err, "getting total karma")
 		}
 
 		if originKarma == nil || originKarma.Cmp(common.BigZero()) == 0 {
-			return res, errors.New("origin has no karma of the appropriate type")
+			return res, errors.New("payer has no karma of the appropriate type")
 		}
 
 		var originKarmaTotal int64
@@ -159,9 +162,13 @@ func GetKarmaMiddleWare(
 			if originKarmaTotal > math.MaxInt64-th.maxCallCount {
 				callCount = math.MaxInt64
 			}
-			err := th.runThrottle(state, nonceTx.Sequence, origin, callCount, tx.Id, karmaMiddlewareThrottleKey)
-			if err != nil {
-				return res, errors.Wrap(err, "call karma throttle")
+			// The in-memory throttle isn't deterministic, once the state tx limiter is enabled it's only
+			// used to filter txs in CheckTx.
+			if isCheckTx || !state.FeatureEnabled(features.StateTxLimiterFeature, false) {
+				err := th.runThrottle(state, nonceTx.Sequence, payer, callCount, tx.Id, karmaMiddlewareThrottleKey)
+				if err != nil {
+					return res, errors.Wrap(err, "call karma throttle")
+				}
 			}
 		}
 
@@ -0,0 +1,120 @@
+package throttle
+
+import (
+	"encoding/binary"
+
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/util"
+	"github.com/pkg/errors"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	"github.com/loomnetwork/loomchain/features"
+)
+
+const (
+	// Window size & tx limit used by the state tx limiter if they're not set in the on-chain config.
+	DefaultStateTxLimiterBlockRange      = int64(60)
+	DefaultStateTxLimiterMaxTxsPerWindow = int64(60)
+)
+
+// stateTxLimits returns the number of blocks in each window, and the maximum number of txs each
+// account can send within a window, these are set via the StateTxLimiter.BlockRange and
+// StateTxLimiter.MaxTxsPerWindow on-chain config settings.
+func stateTxLimits(state loomchain.State) (int64, int64) {
+	cfg := state.Config().GetStateTxLimiter()
+	blockRange := int64(cfg.GetBlockRange())
+	if blockRange <= 0 {
+		blockRange = DefaultStateTxLimiterBlockRange
+	}
+	maxTxs := int64(cfg.GetMaxTxsPerWindow())
+	if maxTxs <= 0 {
+		maxTxs = DefaultStateTxLimiterMaxTxsPerWindow
+	}
+	return blockRange, maxTxs
+}
+
+// StateLimiter counts txs within fixed windows of block heights, unlike the in-memory limiters the
+// counts are stored in the app state, so all nodes agree on whether a limit has been reached.
+// Only the count for the current window is stored for each key, so the state used by the limiter
+// only grows with the number of distinct keys.
+type StateLimiter struct {
+	prefix []byte
+}
+
+// NewStateLimiter creates a limiter that stores its counts under the given name.
+func NewStateLimiter(name string) *StateLimiter {
+	return &StateLimiter{
+		prefix: util.PrefixKey([]byte("txlimit"), []byte(name)),
+	}
+}
+
+func (l *StateLimiter) countKey(addr loom.Address) []byte {
+	return util.PrefixKey(l.prefix, addr.Bytes())
+}
+
+// Count returns the number of txs counted for the given address within the window containing the
+// given block height.
+func (l *StateLimiter) Count(state loomchain.ReadOnlyState, addr loom.Address, height, blockRange int64) int64 {
+	data := state.Get(l.countKey(addr))
+	if len(data) != 16 {
+		return 0
+	}
+	if int64(binary.BigEndian.Uint64(data[:8])) != height/blockRange {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(data[8:]))
+}
+
+// Inc increments the number of txs counted for the given address within the window containing the
+// given block height, the count from any previous window is discarded.
+func (l *StateLimiter) Inc(state loomchain.State, addr loom.Address, height, blockRange int64) {
+	count := l.Count(state, addr, height, blockRange)
+	data := make([]byte, 16)
+	binary.BigEndian.PutUint64(data[:8], uint64(height/blockRange))
+	binary.BigEndian.PutUint64(data[8:], uint64(count+1))
+	state.Set(l.countKey(addr), data)
+}
+
+// NewStateTxLimiterMiddleware creates middleware that limits how many txs (of all types) each account
+// can send within a window of blocks. The tx counts are stored in the app state, and the middleware is
+// enabled & configured on-chain, so unlike the in-memory TxLimiter this middleware produces the same
+// result on every node in DeliverTx. In CheckTx the counts are only checked, they're not incremented,
+// so the in-memory TxLimiter should be used to filter out bursts of txs from a single account in
+// CheckTx. Only txs that are processed successfully are counted, since the state changes made by
+// failed txs are discarded.
+func NewStateTxLimiterMiddleware() loomchain.TxMiddlewareFunc {
+	limiter := NewStateLimiter("account")
+	return loomchain.TxMiddlewareFunc(func(
+		state loomchain.State,
+		txBytes []byte,
+		next loomchain.TxHandlerFunc,
+		isCheckTx bool,
+	) (loomchain.TxHandlerResult, error) {
+		if !state.FeatureEnabled(features.StateTxLimiterFeature, false) {
+			return next(state, txBytes, isCheckTx)
+		}
+
+		origin := auth.Origin(state.Context())
+		if origin.IsEmpty() {
+			return loomchain.TxHandlerResult{}, errors.New("throttle: transaction has no origin [state-tx-limiter]")
+		}
+
+		// In CheckTx the tx will be included in the next block at the earliest
+		height := state.Block().Height
+		if isCheckTx {
+			height++
+		}
+
+		// Sponsored txs count towards the limit of the sponsor rather than the origin
+		payer := auth.Payer(state.Context())
+		blockRange, maxTxs := stateTxLimits(state)
+		if limiter.Count(state, payer, height, blockRange) >= maxTxs {
+			return loomchain.TxHandlerResult{}, ErrTxLimitReached
+		}
+		if !isCheckTx {
+			limiter.Inc(state, payer, height, blockRange)
+		}
+		return next(state, txBytes, isCheckTx)
+	})
+}
@@ -0,0 +1,61 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loomnetwork/go-loom"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	"github.com/loomnetwork/loomchain/features"
+	"github.com/loomnetwork/loomchain/store"
+)
+
+func TestStateTxLimiterMiddleware(t *testing.T) {
+	origin1 := loom.MustParseAddress("chain:0x5cecd1f7261e1f4c684e297be3edf03b825e01c4")
+	origin2 := loom.MustParseAddress("chain:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+	kvStore := store.NewMemStore()
+	cfgState := loomchain.NewStoreState(context.Background(), kvStore, abci.Header{}, nil, nil)
+	require.NoError(t, cfgState.ChangeConfigSetting("StateTxLimiter.BlockRange", "10"))
+	require.NoError(t, cfgState.ChangeConfigSetting("StateTxLimiter.MaxTxsPerWindow", "2"))
+	txl := NewStateTxLimiterMiddleware()
+	noopHandler := func(state loomchain.State, txBytes []byte, isCheckTx bool) (loomchain.TxHandlerResult, error) {
+		return loomchain.TxHandlerResult{}, nil
+	}
+	processTx := func(height int64, ctx context.Context, isCheckTx bool) error {
+		state := loomchain.NewStoreState(ctx, kvStore, abci.Header{Height: height}, nil, nil)
+		state.SetFeature(features.StateTxLimiterFeature, true)
+		_, err := txl.ProcessTx(state, nil, noopHandler, isCheckTx)
+		return err
+	}
+	ctx1 := context.WithValue(context.Background(), auth.ContextKeyOrigin, origin1)
+	ctx2 := context.WithValue(context.Background(), auth.ContextKeyOrigin, origin2)
+
+	require.NoError(t, processTx(5, ctx1, false))
+	require.NoError(t, processTx(6, ctx1, false))
+	require.Equal(t, ErrTxLimitReached, processTx(7, ctx1, false))
+	require.Equal(t, ErrTxLimitReached, processTx(7, ctx1, true))
+	// Each account has its own limit
+	require.NoError(t, processTx(7, ctx2, false))
+
+	// CheckTx should check the limit for the next block, which starts a new window
+	require.NoError(t, processTx(9, ctx1, true))
+	require.NoError(t, processTx(10, ctx1, false))
+	require.NoError(t, processTx(11, ctx1, false))
+	require.Equal(t, ErrTxLimitReached, processTx(12, ctx1, false))
+
+	// Sponsored txs count towards the limit of the sponsor
+	sponsoredCtx := context.WithValue(ctx1, auth.ContextKeySponsor, origin2)
+	require.NoError(t, processTx(12, sponsoredCtx, false))
+	require.NoError(t, processTx(12, sponsoredCtx, false))
+	require.Equal(t, ErrTxLimitReached, processTx(12, sponsoredCtx, false))
+	require.Equal(t, ErrTxLimitReached, processTx(12, ctx2, false))
+
+	// The limit isn't enforced until the feature is enabled
+	state := loomchain.NewStoreState(ctx2, kvStore, abci.Header{Height: 12}, nil, nil)
+	_, err := txl.ProcessTx(state, nil, noopHandler, false)
+	require.NoError(t, err)
+}
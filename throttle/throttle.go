@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 
 	"github.com/ulule/limiter"
@@ -19,11 +20,14 @@ import (
 	"github.com/loomnetwork/loomchain/builtin/plugins/karma"
 )
 
+// Maximum number of accounts the throttle keeps call limiters for, the least recently used limiters
+// are evicted once this limit is reached.
+const maxCallLimiterPoolSize = 10000
+
 type Throttle struct {
 	maxCallCount         int64
 	sessionDuration      int64
-	callLimiterPool      map[string]*limiter.Limiter
-	deployLimiterPool    map[string]*limiter.Limiter
+	callLimiterPool      *lru.Cache // account -> *limiter.Limiter
 	karmaContractAddress loom.Address
 
 	lastAddress        string
@@ -36,11 +40,14 @@ func NewThrottle(
 	sessionDuration int64,
 	maxCallCount int64,
 ) *Throttle {
+	callLimiterPool, err := lru.New(maxCallLimiterPoolSize)
+	if err != nil {
+		panic(err)
+	}
 	return &Throttle{
 		maxCallCount:         maxCallCount,
 		sessionDuration:      sessionDuration,
-		callLimiterPool:      make(map[string]*limiter.Limiter),
-		deployLimiterPool:    make(map[string]*limiter.Limiter),
+		callLimiterPool:      callLimiterPool,
 		karmaContractAddress: loom.Address{},
 	}
 }
@@ -55,23 +62,21 @@ func (t *Throttle) getNewLimiter(ctx context.Context, limit int64) *limiter.Limi
 }
 
 func (t *Throttle) getLimiterFromPool(ctx context.Context, limit int64) *limiter.Limiter {
-	address := auth.Origin(ctx).String()
-	_, ok := t.callLimiterPool[address]
-	if !ok {
-		t.callLimiterPool[address] = t.getNewLimiter(ctx, limit)
+	address := auth.Payer(ctx).String()
+	if v, ok := t.callLimiterPool.Get(address); ok {
+		if l := v.(*limiter.Limiter); l.Rate.Limit == limit {
+			return l
+		}
 	}
-	if t.callLimiterPool[address].Rate.Limit != limit {
-		delete(t.callLimiterPool, address)
-		t.callLimiterPool[address] = t.getNewLimiter(ctx, limit)
-	}
-
-	return t.callLimiterPool[address]
+	l := t.getNewLimiter(ctx, limit)
+	t.callLimiterPool.Add(address, l)
+	return l
 }
 
 func (t *Throttle) getLimiterContext(
 	ctx context.Context, nonce uint64, limit int64, txId uint32, key string,
 ) (limiter.Context, error) {
-	address := auth.Origin(ctx).String()
+	address := auth.Payer(ctx).String()
 	if address == t.lastAddress && nonce == t.lastNonce && t.lastId == txId {
 		return t.lastLimiterContext, nil
 	} else {
@@ -1,15 +1,13 @@
 package throttle
 
 import (
-	"context"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/loomnetwork/go-loom"
 	"github.com/loomnetwork/loomchain"
 	"github.com/loomnetwork/loomchain/auth"
 	"github.com/pkg/errors"
-	"github.com/ulule/limiter"
-	"github.com/ulule/limiter/drivers/store/memory"
 )
 
 type TxLimiterConfig struct {
@@ -19,12 +17,16 @@ type TxLimiterConfig struct {
 	SessionDuration int64
 	// Maximum number of txs that should be allowed per session
 	MaxTxsPerSession int64
+	// Maximum number of accounts to track, when this limit is reached the least recently seen
+	// accounts are evicted (which resets their sessions).
+	MaxAccounts int
 }
 
 func DefaultTxLimiterConfig() *TxLimiterConfig {
 	return &TxLimiterConfig{
 		SessionDuration:  60,
 		MaxTxsPerSession: 60,
+		MaxAccounts:      10000,
 	}
 }
 
@@ -38,35 +40,54 @@ func (c *TxLimiterConfig) Clone() *TxLimiterConfig {
 }
 
 type txLimiter struct {
-	*limiter.Limiter
+	period   time.Duration
+	limit    int64
+	sessions *lru.Cache // account -> *txSession
+}
+
+type txSession struct {
+	start time.Time
+	count int64
 }
 
 func newTxLimiter(cfg *TxLimiterConfig) *txLimiter {
+	maxAccounts := cfg.MaxAccounts
+	if maxAccounts <= 0 {
+		maxAccounts = DefaultTxLimiterConfig().MaxAccounts
+	}
+	sessions, err := lru.New(maxAccounts)
+	// Only returns an error if the size isn't positive
+	if err != nil {
+		panic(err)
+	}
 	return &txLimiter{
-		Limiter: limiter.New(
-			memory.NewStore(),
-			limiter.Rate{
-				Period: time.Duration(cfg.SessionDuration) * time.Second,
-				Limit:  cfg.MaxTxsPerSession,
-			},
-		),
+		period:   time.Duration(cfg.SessionDuration) * time.Second,
+		limit:    cfg.MaxTxsPerSession,
+		sessions: sessions,
 	}
 }
 
+// isAccountLimitReached counts a tx from the given account, and returns true if the account has
+// sent more txs than allowed in the current session.
 func (txl *txLimiter) isAccountLimitReached(account loom.Address) bool {
-	lmtCtx, err := txl.Limiter.Get(context.TODO(), account.String())
-	// Doesn't look like the current implementation of the limit with the in-memory store will ever
-	// return an error anyway.
-	if err != nil {
-		panic(err)
+	now := time.Now()
+	key := account.String()
+	if v, ok := txl.sessions.Get(key); ok {
+		session := v.(*txSession)
+		if now.Sub(session.start) < txl.period {
+			session.count++
+			return session.count > txl.limit
+		}
 	}
-	return lmtCtx.Reached
+	txl.sessions.Add(key, &txSession{start: now, count: 1})
+	return txl.limit < 1
 }
 
 // NewTxLimiterMiddleware creates middleware that throttles txs (all types) in CheckTx, the rate
 // can be configured in loom.yml. Since this middleware only runs in CheckTx the rate limit can
 // differ between nodes on the same cluster, and private nodes don't really need to run the rate
-// limiter at all.
+// limiter at all. The middleware is meant to be a cheap pre-filter, the number of accounts it keeps
+// track of is bounded, use the state tx limiter to enforce limits that all nodes agree on.
 func NewTxLimiterMiddleware(cfg *TxLimiterConfig) loomchain.TxMiddlewareFunc {
 	txl := newTxLimiter(cfg)
 	return loomchain.TxMiddlewareFunc(func(
@@ -84,7 +105,8 @@ func NewTxLimiterMiddleware(cfg *TxLimiterConfig) loomchain.TxMiddlewareFunc {
 			return loomchain.TxHandlerResult{}, errors.New("throttle: transaction has no origin [get-karma]")
 		}
 
-		if txl.isAccountLimitReached(origin) {
+		// Sponsored txs count towards the limit of the sponsor rather than the origin
+		if txl.isAccountLimitReached(auth.Payer(state.Context())) {
 			return loomchain.TxHandlerResult{}, errors.New("tx limit reached, try again later")
 		}
 
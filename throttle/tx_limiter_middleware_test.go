@@ -0,0 +1,25 @@
+package throttle
+
+import (
+	"testing"
+
+	"github.com/loomnetwork/go-loom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxLimiterEviction(t *testing.T) {
+	origin1 := loom.MustParseAddress("chain:0x5cecd1f7261e1f4c684e297be3edf03b825e01c4")
+	origin2 := loom.MustParseAddress("chain:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+	txl := newTxLimiter(&TxLimiterConfig{
+		SessionDuration:  60,
+		MaxTxsPerSession: 1,
+		MaxAccounts:      1,
+	})
+
+	require.False(t, txl.isAccountLimitReached(origin1))
+	require.True(t, txl.isAccountLimitReached(origin1))
+	// Tracking another account should evict the first one
+	require.False(t, txl.isAccountLimitReached(origin2))
+	require.Equal(t, 1, txl.sessions.Len())
+	require.False(t, txl.isAccountLimitReached(origin1))
+}
@@ -0,0 +1,70 @@
+package tx_handler
+
+import (
+	"fmt"
+
+	proto "github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	"github.com/loomnetwork/loomchain/features"
+	"github.com/loomnetwork/loomchain/registry"
+	"github.com/loomnetwork/loomchain/registry/factory"
+	"github.com/loomnetwork/loomchain/vm"
+)
+
+// RegistryTxHandler handles RegistryTx(s), which allow the owner of a contract name to upgrade the
+// contract the name points to, or transfer ownership of the name.
+type RegistryTxHandler struct {
+	CreateRegistry factory.RegistryFactoryFunc
+}
+
+func (h *RegistryTxHandler) ProcessTx(
+	state loomchain.State,
+	txBytes []byte,
+	isCheckTx bool,
+) (loomchain.TxHandlerResult, error) {
+	var r loomchain.TxHandlerResult
+
+	if !state.FeatureEnabled(features.RegistryTxFeature, false) {
+		return r, fmt.Errorf("RegistryTx feature hasn't been enabled")
+	}
+
+	var msg vm.MessageTx
+	if err := proto.Unmarshal(txBytes, &msg); err != nil {
+		return r, err
+	}
+
+	origin := auth.Origin(state.Context())
+	caller := loom.UnmarshalAddressPB(msg.From)
+
+	if caller.Compare(origin) != 0 {
+		return r, fmt.Errorf("Origin doesn't match caller: - %v != %v", origin, caller)
+	}
+
+	var tx registry.RegistryTx
+	if err := proto.Unmarshal(msg.Data, &tx); err != nil {
+		return r, errors.Wrap(err, "failed to unmarshal RegistryTx")
+	}
+
+	reg, ok := h.CreateRegistry(state).(registry.VersionedRegistry)
+	if !ok {
+		return r, errors.Wrap(registry.ErrNotImplemented, "RegistryTx requires registry v3")
+	}
+
+	switch {
+	case tx.Upgrade != nil && tx.NewOwner == nil:
+		if err := reg.ScheduleUpgrade(tx.Name, tx.Upgrade, caller); err != nil {
+			return r, errors.Wrapf(err, "failed to upgrade %s", tx.Name)
+		}
+	case tx.NewOwner != nil && tx.Upgrade == nil:
+		if err := reg.TransferOwnership(tx.Name, loom.UnmarshalAddressPB(tx.NewOwner), caller); err != nil {
+			return r, errors.Wrapf(err, "failed to transfer ownership of %s", tx.Name)
+		}
+	default:
+		return r, errors.New("RegistryTx must specify either an upgrade or a new owner")
+	}
+	return r, nil
+}
@@ -0,0 +1,53 @@
+package tx_handler
+
+import (
+	"fmt"
+
+	proto "github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	loom "github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	"github.com/loomnetwork/loomchain/features"
+	"github.com/loomnetwork/loomchain/vm"
+)
+
+// SponsorPolicyTxHandler handles txs that set the SponsorPolicy of the caller, which restricts the
+// contracts the caller pays for when it sponsors txs sent by other accounts.
+type SponsorPolicyTxHandler struct {
+}
+
+func (h *SponsorPolicyTxHandler) ProcessTx(
+	state loomchain.State,
+	txBytes []byte,
+	isCheckTx bool,
+) (loomchain.TxHandlerResult, error) {
+	var r loomchain.TxHandlerResult
+
+	if !state.FeatureEnabled(features.SponsoredTxFeature, false) {
+		return r, fmt.Errorf("SponsoredTx feature hasn't been enabled")
+	}
+
+	var msg vm.MessageTx
+	if err := proto.Unmarshal(txBytes, &msg); err != nil {
+		return r, err
+	}
+
+	origin := auth.Origin(state.Context())
+	caller := loom.UnmarshalAddressPB(msg.From)
+
+	if caller.Compare(origin) != 0 {
+		return r, fmt.Errorf("Origin doesn't match caller: - %v != %v", origin, caller)
+	}
+
+	var policy auth.SponsorPolicy
+	if err := proto.Unmarshal(msg.Data, &policy); err != nil {
+		return r, errors.Wrap(err, "failed to unmarshal SponsorPolicy")
+	}
+
+	if err := auth.SetSponsorPolicy(state, caller, &policy); err != nil {
+		return r, errors.Wrapf(err, "failed to set sponsor policy of %s", caller.String())
+	}
+	return r, nil
+}
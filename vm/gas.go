@@ -0,0 +1,90 @@
+package vm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/features"
+)
+
+// DefaultGoContractGasLimit is the max amount of gas a single Go contract tx can use if the on-chain
+// config doesn't specify a gas limit.
+const DefaultGoContractGasLimit = uint64(100000000)
+
+var ErrOutOfGas = errors.New("out of gas")
+
+// GasMeter keeps track of the gas used while executing a tx.
+type GasMeter struct {
+	limit    uint64
+	used     uint64
+	outOfGas bool
+}
+
+func NewGasMeter(limit uint64) *GasMeter {
+	return &GasMeter{limit: limit}
+}
+
+// ConsumeGas adds the given amount to the gas used, if this exceeds the gas limit all the remaining gas
+// is used up and ErrOutOfGas is returned. Once the meter runs out of gas any further attempts to consume
+// gas will fail.
+func (m *GasMeter) ConsumeGas(amount uint64) error {
+	if m.outOfGas || amount > m.limit-m.used {
+		m.used = m.limit
+		m.outOfGas = true
+		return ErrOutOfGas
+	}
+	m.used += amount
+	return nil
+}
+
+func (m *GasMeter) GasUsed() uint64 {
+	return m.used
+}
+
+func (m *GasMeter) GasLimit() uint64 {
+	return m.limit
+}
+
+func (m *GasMeter) IsOutOfGas() bool {
+	return m.outOfGas
+}
+
+type gasMeterCtxKey struct{}
+
+// WithGasMeter returns a copy of the given context that carries the given gas meter.
+func WithGasMeter(ctx context.Context, meter *GasMeter) context.Context {
+	return context.WithValue(ctx, gasMeterCtxKey{}, meter)
+}
+
+// GasMeterFromContext returns the gas meter carried by the given context, or nil if there isn't one.
+func GasMeterFromContext(ctx context.Context) *GasMeter {
+	if ctx == nil {
+		return nil
+	}
+	meter, _ := ctx.Value(gasMeterCtxKey{}).(*GasMeter)
+	return meter
+}
+
+// goContractGasLimit returns the max amount of gas a Go contract tx can use, Go contract txs share
+// the per-tx gas limit of EVM txs, which is set via the Evm.GasLimit on-chain config setting.
+func goContractGasLimit(state loomchain.State) uint64 {
+	if limit := state.Config().GetEvm().GetGasLimit(); limit > 0 {
+		return limit
+	}
+	return DefaultGoContractGasLimit
+}
+
+// withGasMeter returns a copy of the given state that carries a new gas meter if gas metering is
+// enabled for txs of the given VM type.
+func withGasMeter(state loomchain.State, vmType VMType) (loomchain.State, *GasMeter) {
+	if vmType != VMType_PLUGIN || !state.FeatureEnabled(features.GoContractGasMeteringFeature, false) {
+		return state, nil
+	}
+	meter := NewGasMeter(goContractGasLimit(state))
+	ctx := state.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return state.WithContext(WithGasMeter(ctx, meter)), meter
+}
@@ -52,7 +52,8 @@ func (h *DeployTxHandler) ProcessTx(
 		return r, errors.New("named evm contracts are not allowed")
 	}
 
-	vm, err := h.Manager.InitVM(tx.VmType, state)
+	vmState, gasMeter := withGasMeter(state, tx.VmType)
+	vm, err := h.Manager.InitVM(tx.VmType, vmState)
 	if err != nil {
 		return r, err
 	}
@@ -65,6 +66,13 @@ func (h *DeployTxHandler) ProcessTx(
 	}
 
 	retCreate, addr, errCreate := vm.Create(origin, tx.Code, value)
+	if gasMeter != nil {
+		r.GasUsed = gasMeter.GasUsed()
+		// A contract may have swallowed the error returned by a nested call that ran out of gas
+		if errCreate == nil && gasMeter.IsOutOfGas() {
+			errCreate = ErrOutOfGas
+		}
+	}
 
 	response, errMarshal := proto.Marshal(&DeployResponse{
 		Contract: &types.Address{
@@ -130,7 +138,8 @@ func (h *CallTxHandler) ProcessTx(
 		return r, err
 	}
 
-	vm, err := h.Manager.InitVM(tx.VmType, state)
+	vmState, gasMeter := withGasMeter(state, tx.VmType)
+	vm, err := h.Manager.InitVM(tx.VmType, vmState)
 	if err != nil {
 		return r, err
 	}
@@ -142,6 +151,13 @@ func (h *CallTxHandler) ProcessTx(
 		value = &tx.Value.Value
 	}
 	r.Data, err = vm.Call(origin, addr, tx.Input, value)
+	if gasMeter != nil {
+		r.GasUsed = gasMeter.GasUsed()
+		// A contract may have swallowed the error returned by a nested call that ran out of gas
+		if err == nil && gasMeter.IsOutOfGas() {
+			err = ErrOutOfGas
+		}
+	}
 	if err != nil {
 		return r, err
 	}
@@ -9,6 +9,7 @@ type VMType = lvm.VMType
 const (
 	VMType_PLUGIN VMType = lvm.VMType_PLUGIN
 	VMType_EVM    VMType = lvm.VMType_EVM
+	VMType_WASM   VMType = lvm.VMType_WASM
 )
 
 var VMType_value = lvm.VMType_value
@@ -0,0 +1,378 @@
+package wasm
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/loomnetwork/go-loom"
+	"github.com/loomnetwork/go-loom/plugin"
+	"github.com/loomnetwork/go-loom/plugin/types"
+	"github.com/perlin-network/life/exec"
+	"github.com/pkg/errors"
+
+	"github.com/loomnetwork/loomchain/vm"
+)
+
+// Host functions WASM contracts can import from the `env` module, these mirror the API the node
+// exposes to external Go contracts via GRPCAPIServer. All pointers & lengths are i32 values that
+// refer to the contract's linear memory. Functions that output variable length data write it to the
+// given output buffer & return the length of the data, if the data doesn't fit into the buffer
+// nothing is written so the contract can retry with a bigger buffer.
+//
+//   loom_input_len() -> i32                         length of the call input
+//   loom_input(ptr)                                 copies the call input to ptr
+//   loom_return(ptr, len)                           sets the call output
+//   loom_get(key, keyLen, out, outLen) -> i32       reads a value from storage, -1 if not found
+//   loom_has(key, keyLen) -> i32                    1 if the key exists in storage, 0 otherwise
+//   loom_set(key, keyLen, value, valueLen)          writes a value to storage
+//   loom_delete(key, keyLen)                        deletes a value from storage
+//   loom_range(prefix, prefixLen, out, outLen) -> i32
+//                                                   reads all storage entries under the prefix,
+//                                                   outputs a protobuf-encoded types.RangeResponse
+//   loom_emit(req, reqLen)                          emits an event, req is a types.EmitRequest
+//   loom_call(req, reqLen, out, outLen) -> i32      calls a contract, req is a types.CallRequest
+//   loom_static_call(req, reqLen, out, outLen) -> i32
+//                                                   calls a contract in read-only mode
+//   loom_resolve(name, nameLen, out, outLen) -> i32 looks up the address of a named contract,
+//                                                   outputs a protobuf-encoded types.Address
+//   loom_sender(out, outLen) -> i32                 outputs the caller's types.Address
+//   loom_address(out, outLen) -> i32                outputs the contract's types.Address
+//   loom_block_height() -> i64
+//   loom_block_time() -> i64                        block time in seconds since the Unix epoch
+//   loom_feature_enabled(name, nameLen) -> i32      1 if the named feature is enabled, 0 otherwise
+//
+// In addition to the gas charged for each WASM instruction, host functions charge a flat fee, plus
+// a per byte fee for any data they read or write.
+
+const (
+	hostCallGas     = uint64(40)
+	storageReadGas  = uint64(200)
+	storageWriteGas = uint64(5000)
+	emitGas         = uint64(375)
+	contractCallGas = uint64(700)
+	perByteGas      = uint64(3)
+)
+
+var (
+	errVolatileCall = errors.New("calling volatile method from static context")
+	errOutOfBounds  = errors.New("memory access out of bounds")
+)
+
+// kvStore is the contract storage accessible to WASM contracts.
+type kvStore interface {
+	Get(key []byte) []byte
+	Has(key []byte) bool
+	Set(key, value []byte)
+	Delete(key []byte)
+	Range(prefix []byte) plugin.RangeData
+}
+
+// hostEnv holds the context of a single WASM contract call.
+type hostEnv struct {
+	vm       *WasmVM
+	caller   loom.Address
+	address  loom.Address
+	input    []byte
+	output   []byte
+	readOnly bool
+	dryRun   bool
+	store    kvStore
+	depth    int
+	// The first error encountered by a host function, execution is aborted as soon as it's set.
+	err error
+}
+
+// abort stops execution of the contract, the error will be returned by WasmVM.run.
+func (h *hostEnv) abort(err error) {
+	if h.err == nil {
+		h.err = err
+	}
+	panic(err)
+}
+
+func (h *hostEnv) chargeGas(instance *exec.VirtualMachine, gas uint64, numBytes int) {
+	instance.Gas += gas + uint64(numBytes)*perByteGas
+}
+
+func (h *hostEnv) readMemory(instance *exec.VirtualMachine, ptr, length int64) []byte {
+	if ptr < 0 || length < 0 || ptr+length > int64(len(instance.Memory)) {
+		h.abort(errOutOfBounds)
+	}
+	data := make([]byte, length)
+	copy(data, instance.Memory[ptr:ptr+length])
+	return data
+}
+
+func (h *hostEnv) writeMemory(instance *exec.VirtualMachine, ptr int64, data []byte) {
+	if ptr < 0 || ptr+int64(len(data)) > int64(len(instance.Memory)) {
+		h.abort(errOutOfBounds)
+	}
+	copy(instance.Memory[ptr:], data)
+}
+
+// writeOutput writes the given data to the output buffer if it fits, and returns the data length.
+func (h *hostEnv) writeOutput(instance *exec.VirtualMachine, ptr, maxLen int64, data []byte) int64 {
+	if int64(len(data)) <= maxLen {
+		h.writeMemory(instance, ptr, data)
+	}
+	return int64(len(data))
+}
+
+func (h *hostEnv) checkWritable() {
+	if h.readOnly {
+		h.abort(errVolatileCall)
+	}
+}
+
+func args(instance *exec.VirtualMachine) []int64 {
+	return instance.GetCurrentFrame().Locals
+}
+
+func (h *hostEnv) inputLen(instance *exec.VirtualMachine) int64 {
+	h.chargeGas(instance, hostCallGas, 0)
+	return int64(len(h.input))
+}
+
+func (h *hostEnv) readInput(instance *exec.VirtualMachine) int64 {
+	h.chargeGas(instance, hostCallGas, len(h.input))
+	h.writeMemory(instance, args(instance)[0], h.input)
+	return 0
+}
+
+func (h *hostEnv) setOutput(instance *exec.VirtualMachine) int64 {
+	a := args(instance)
+	h.output = h.readMemory(instance, a[0], a[1])
+	h.chargeGas(instance, hostCallGas, len(h.output))
+	return 0
+}
+
+func (h *hostEnv) get(instance *exec.VirtualMachine) int64 {
+	a := args(instance)
+	key := h.readMemory(instance, a[0], a[1])
+	if !h.store.Has(key) {
+		h.chargeGas(instance, storageReadGas, len(key))
+		return -1
+	}
+	value := h.store.Get(key)
+	h.chargeGas(instance, storageReadGas, len(key)+len(value))
+	return h.writeOutput(instance, a[2], a[3], value)
+}
+
+func (h *hostEnv) has(instance *exec.VirtualMachine) int64 {
+	a := args(instance)
+	key := h.readMemory(instance, a[0], a[1])
+	h.chargeGas(instance, storageReadGas, len(key))
+	if h.store.Has(key) {
+		return 1
+	}
+	return 0
+}
+
+func (h *hostEnv) set(instance *exec.VirtualMachine) int64 {
+	h.checkWritable()
+	a := args(instance)
+	key := h.readMemory(instance, a[0], a[1])
+	value := h.readMemory(instance, a[2], a[3])
+	h.chargeGas(instance, storageWriteGas, len(key)+len(value))
+	h.store.Set(key, value)
+	return 0
+}
+
+func (h *hostEnv) delete(instance *exec.VirtualMachine) int64 {
+	h.checkWritable()
+	a := args(instance)
+	key := h.readMemory(instance, a[0], a[1])
+	h.chargeGas(instance, storageWriteGas, len(key))
+	h.store.Delete(key)
+	return 0
+}
+
+func (h *hostEnv) rangePrefix(instance *exec.VirtualMachine) int64 {
+	a := args(instance)
+	prefix := h.readMemory(instance, a[0], a[1])
+	resp := &types.RangeResponse{}
+	for _, entry := range h.store.Range(prefix) {
+		resp.RangeEntries = append(resp.RangeEntries, &types.RangeEntry{
+			Key:   entry.Key,
+			Value: entry.Value,
+		})
+	}
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		h.abort(err)
+	}
+	h.chargeGas(instance, storageReadGas, len(prefix)+len(data))
+	return h.writeOutput(instance, a[2], a[3], data)
+}
+
+func (h *hostEnv) emit(instance *exec.VirtualMachine) int64 {
+	a := args(instance)
+	reqBytes := h.readMemory(instance, a[0], a[1])
+	h.chargeGas(instance, emitGas, len(reqBytes))
+	// Events emitted in read-only mode are silently dropped, same as Go contracts.
+	if h.readOnly || h.dryRun || h.vm.eventHandler == nil {
+		return 0
+	}
+	var req types.EmitRequest
+	if err := proto.Unmarshal(reqBytes, &req); err != nil {
+		h.abort(errors.Wrap(err, "invalid emit request"))
+	}
+	event := &types.EventData{
+		Topics:          req.Topics,
+		Caller:          h.caller.MarshalPB(),
+		Address:         h.address.MarshalPB(),
+		EncodedBody:     req.Data,
+		OriginalRequest: h.input,
+	}
+	if err := h.vm.eventHandler.Post(uint64(h.vm.state.Block().Height), event); err != nil {
+		h.abort(err)
+	}
+	return 0
+}
+
+func (h *hostEnv) call(instance *exec.VirtualMachine) int64 {
+	h.checkWritable()
+	return h.callContract(instance, false)
+}
+
+func (h *hostEnv) staticCall(instance *exec.VirtualMachine) int64 {
+	return h.callContract(instance, true)
+}
+
+func (h *hostEnv) callContract(instance *exec.VirtualMachine, readOnly bool) int64 {
+	a := args(instance)
+	reqBytes := h.readMemory(instance, a[0], a[1])
+	h.chargeGas(instance, contractCallGas, len(reqBytes))
+	if h.depth+1 > maxCallDepth {
+		h.abort(ErrCallDepth)
+	}
+	var req types.CallRequest
+	if err := proto.Unmarshal(reqBytes, &req); err != nil {
+		h.abort(errors.Wrap(err, "invalid call request"))
+	}
+	if h.vm.createVM == nil {
+		h.abort(errors.New("contract calls are not supported"))
+	}
+
+	// The called contract is charged via a gas meter regardless of the VM it runs on, Go & EVM
+	// contracts charge the meter for storage access & EVM execution respectively.
+	var gasLimit uint64
+	if instance.Config.GasLimit > instance.Gas {
+		gasLimit = instance.Config.GasLimit - instance.Gas
+	}
+	gasMeter := vm.NewGasMeter(gasLimit)
+	nested := &nestedCall{depth: h.depth + 1}
+	target, err := h.vm.createVM(req.VmType, withNestedCall(h.vm.state, nested, gasMeter))
+	if err != nil {
+		h.abort(err)
+	}
+	addr := loom.UnmarshalAddressPB(req.Address)
+	var ret []byte
+	if readOnly || h.dryRun {
+		ret, err = target.StaticCall(h.address, addr, req.Input)
+	} else {
+		value := loom.NewBigUIntFromInt(0)
+		if req.Value != nil {
+			value = &req.Value.Value
+		}
+		ret, err = target.Call(h.address, addr, req.Input, value)
+	}
+	instance.Gas += gasMeter.GasUsed()
+	if err != nil {
+		h.abort(errors.Wrapf(err, "failed to call contract %v", addr))
+	}
+	h.chargeGas(instance, 0, len(ret))
+	return h.writeOutput(instance, a[2], a[3], ret)
+}
+
+func (h *hostEnv) resolve(instance *exec.VirtualMachine) int64 {
+	a := args(instance)
+	name := h.readMemory(instance, a[0], a[1])
+	h.chargeGas(instance, storageReadGas, len(name))
+	addr, err := h.vm.registry.Resolve(string(name))
+	if err != nil {
+		h.abort(errors.Wrapf(err, "failed to resolve contract %s", name))
+	}
+	return h.writeAddress(instance, a[2], a[3], addr)
+}
+
+func (h *hostEnv) sender(instance *exec.VirtualMachine) int64 {
+	h.chargeGas(instance, hostCallGas, 0)
+	a := args(instance)
+	return h.writeAddress(instance, a[0], a[1], h.caller)
+}
+
+func (h *hostEnv) contractAddress(instance *exec.VirtualMachine) int64 {
+	h.chargeGas(instance, hostCallGas, 0)
+	a := args(instance)
+	return h.writeAddress(instance, a[0], a[1], h.address)
+}
+
+func (h *hostEnv) writeAddress(instance *exec.VirtualMachine, ptr, maxLen int64, addr loom.Address) int64 {
+	data, err := proto.Marshal(addr.MarshalPB())
+	if err != nil {
+		h.abort(err)
+	}
+	return h.writeOutput(instance, ptr, maxLen, data)
+}
+
+func (h *hostEnv) blockHeight(instance *exec.VirtualMachine) int64 {
+	h.chargeGas(instance, hostCallGas, 0)
+	return h.vm.state.Block().Height
+}
+
+func (h *hostEnv) blockTime(instance *exec.VirtualMachine) int64 {
+	h.chargeGas(instance, hostCallGas, 0)
+	return h.vm.state.Block().Time
+}
+
+func (h *hostEnv) featureEnabled(instance *exec.VirtualMachine) int64 {
+	a := args(instance)
+	name := h.readMemory(instance, a[0], a[1])
+	h.chargeGas(instance, storageReadGas, len(name))
+	if h.vm.state.FeatureEnabled(string(name), false) {
+		return 1
+	}
+	return 0
+}
+
+// hostResolver resolves the functions imported by WASM contracts, the host environment may be nil
+// if the module is only being validated.
+type hostResolver struct {
+	env *hostEnv
+}
+
+func (r *hostResolver) ResolveFunc(module, field string) exec.FunctionImport {
+	if module != "env" {
+		panic(errors.Errorf("unknown import module %s", module))
+	}
+	h := r.env
+	funcs := map[string]func(*exec.VirtualMachine) int64{
+		"loom_input_len":       h.inputLen,
+		"loom_input":           h.readInput,
+		"loom_return":          h.setOutput,
+		"loom_get":             h.get,
+		"loom_has":             h.has,
+		"loom_set":             h.set,
+		"loom_delete":          h.delete,
+		"loom_range":           h.rangePrefix,
+		"loom_emit":            h.emit,
+		"loom_call":            h.call,
+		"loom_static_call":     h.staticCall,
+		"loom_resolve":         h.resolve,
+		"loom_sender":          h.sender,
+		"loom_address":         h.contractAddress,
+		"loom_block_height":    h.blockHeight,
+		"loom_block_time":      h.blockTime,
+		"loom_feature_enabled": h.featureEnabled,
+	}
+	fn, ok := funcs[field]
+	if !ok {
+		panic(errors.Errorf("unknown import %s.%s", module, field))
+	}
+	return fn
+}
+
+func (r *hostResolver) ResolveGlobal(module, field string) int64 {
+	panic(errors.Errorf("unknown global import %s.%s", module, field))
+}
+
+var _ exec.ImportResolver = &hostResolver{}
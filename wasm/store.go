@@ -0,0 +1,55 @@
+package wasm
+
+import (
+	"github.com/loomnetwork/go-loom/plugin"
+)
+
+// overlayStore buffers all writes in memory, leaving the underlying store untouched.
+type overlayStore struct {
+	store   kvStore
+	cache   map[string][]byte
+	deleted map[string]bool
+}
+
+func newOverlayStore(store kvStore) *overlayStore {
+	return &overlayStore{
+		store:   store,
+		cache:   map[string][]byte{},
+		deleted: map[string]bool{},
+	}
+}
+
+func (s *overlayStore) Get(key []byte) []byte {
+	if s.deleted[string(key)] {
+		return nil
+	}
+	if value, ok := s.cache[string(key)]; ok {
+		return value
+	}
+	return s.store.Get(key)
+}
+
+func (s *overlayStore) Has(key []byte) bool {
+	if s.deleted[string(key)] {
+		return false
+	}
+	if _, ok := s.cache[string(key)]; ok {
+		return true
+	}
+	return s.store.Has(key)
+}
+
+func (s *overlayStore) Set(key, value []byte) {
+	delete(s.deleted, string(key))
+	s.cache[string(key)] = value
+}
+
+func (s *overlayStore) Delete(key []byte) {
+	delete(s.cache, string(key))
+	s.deleted[string(key)] = true
+}
+
+// Range doesn't reflect any buffered writes.
+func (s *overlayStore) Range(prefix []byte) plugin.RangeData {
+	return s.store.Range(prefix)
+}
@@ -0,0 +1,253 @@
+package wasm
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/loomnetwork/go-loom"
+	"github.com/perlin-network/life/compiler"
+	"github.com/perlin-network/life/exec"
+	"github.com/pkg/errors"
+
+	"github.com/loomnetwork/loomchain"
+	"github.com/loomnetwork/loomchain/auth"
+	"github.com/loomnetwork/loomchain/plugin"
+	"github.com/loomnetwork/loomchain/registry"
+	"github.com/loomnetwork/loomchain/vm"
+)
+
+const (
+	// DefaultGasLimit is the max amount of gas a single call to a WASM contract can use, including
+	// the gas used by any contracts it calls.
+	DefaultGasLimit = uint64(100000000)
+	// Max depth of nested contract calls initiated by WASM contracts.
+	maxCallDepth = 16
+
+	// Names of the functions WASM contracts can export.
+	initExport = "init"
+	callExport = "call"
+)
+
+var (
+	ErrContractNotFound = errors.New("WASM contract not found")
+	ErrOutOfGas         = errors.New("WASM contract ran out of gas")
+	ErrCallDepth        = errors.New("max call depth exceeded")
+)
+
+// The interpreter config is part of consensus, changing any of these values will change the outcome
+// of previously executed txs. Floating point instructions are disabled because their results aren't
+// guaranteed to be deterministic across platforms.
+var vmConfig = exec.VMConfig{
+	DefaultMemoryPages:   16,
+	MaxMemoryPages:       256,
+	DefaultTableSize:     65536,
+	MaxTableSize:         65536,
+	MaxValueSlots:        65536,
+	MaxCallStackDepth:    1024,
+	DisableFloatingPoint: true,
+}
+
+var gasPolicy = &compiler.SimpleGasPolicy{GasPerInstruction: 1}
+
+// CreateVMFunc creates a VM of the given type, used by WASM contracts to call other contracts.
+type CreateVMFunc func(vmType vm.VMType, state loomchain.State) (vm.VM, error)
+
+// WasmVM implements the vm.VM interface for contracts compiled to WebAssembly.
+//
+// A WASM contract is a module that exports a `call` function that takes no params and returns an i32
+// status code (zero indicates success), and optionally an `init` function with the same signature
+// that's called when the contract is deployed. Contracts interact with the chain via the host
+// functions imported from the `env` module (see host.go). Contract storage goes through the same
+// app state prefix as Go contract storage.
+type WasmVM struct {
+	state        loomchain.State
+	registry     registry.Registry
+	eventHandler loomchain.EventHandler
+	createVM     CreateVMFunc
+	gasLimit     uint64
+}
+
+var _ vm.VM = &WasmVM{}
+
+func NewWasmVM(
+	state loomchain.State,
+	registry registry.Registry,
+	eventHandler loomchain.EventHandler,
+	createVM CreateVMFunc,
+) *WasmVM {
+	return &WasmVM{
+		state:        state,
+		registry:     registry,
+		eventHandler: eventHandler,
+		createVM:     createVM,
+		gasLimit:     DefaultGasLimit,
+	}
+}
+
+func (w *WasmVM) Create(caller loom.Address, code []byte, value *loom.BigUInt) ([]byte, loom.Address, error) {
+	if value != nil && value.Int != nil && value.Int.Sign() != 0 {
+		return nil, loom.Address{}, errors.New("WASM contracts can't receive value")
+	}
+	// Make sure the module is valid & only imports the available host functions.
+	if _, err := exec.NewVirtualMachine(code, vmConfig, &hostResolver{}, gasPolicy); err != nil {
+		return nil, loom.Address{}, errors.Wrap(err, "invalid WASM module")
+	}
+
+	nonce := auth.Nonce(w.state, caller)
+	contractAddr := plugin.CreateAddress(caller, nonce)
+	if len(w.state.Get(loom.TextKey(contractAddr))) > 0 {
+		return nil, contractAddr, errors.Errorf("contract already exists at %v", contractAddr)
+	}
+	w.state.Set(loom.TextKey(contractAddr), code)
+
+	_, _, err := w.run(caller, contractAddr, code, nil, initExport, false, false)
+	if err != nil {
+		return nil, contractAddr, err
+	}
+
+	ret, err := proto.Marshal(&vm.DeployResponseData{Bytecode: code})
+	if err != nil {
+		return nil, contractAddr, err
+	}
+	return ret, contractAddr, nil
+}
+
+func (w *WasmVM) Call(caller, addr loom.Address, input []byte, value *loom.BigUInt) ([]byte, error) {
+	if value != nil && value.Int != nil && value.Int.Sign() != 0 {
+		return nil, errors.New("WASM contracts can't receive value")
+	}
+	code := w.state.Get(loom.TextKey(addr))
+	if len(code) == 0 {
+		return nil, ErrContractNotFound
+	}
+	ret, _, err := w.run(caller, addr, code, input, callExport, false, false)
+	return ret, err
+}
+
+func (w *WasmVM) StaticCall(caller, addr loom.Address, input []byte) ([]byte, error) {
+	code := w.state.Get(loom.TextKey(addr))
+	if len(code) == 0 {
+		return nil, ErrContractNotFound
+	}
+	ret, _, err := w.run(caller, addr, code, input, callExport, true, false)
+	return ret, err
+}
+
+func (w *WasmVM) GetCode(addr loom.Address) ([]byte, error) {
+	return w.state.Get(loom.TextKey(addr)), nil
+}
+
+func (w *WasmVM) GetStorageAt(addr loom.Address, key []byte) ([]byte, error) {
+	return w.contractStore(addr).Get(key), nil
+}
+
+// EstimateGas returns the amount of gas used by the given call, any changes the call makes to the
+// contract storage are discarded, and any calls it makes to other contracts are made in read-only
+// mode.
+func (w *WasmVM) EstimateGas(
+	caller, addr loom.Address, input []byte, value *loom.BigUInt, gas uint64,
+) (uint64, error) {
+	code := w.state.Get(loom.TextKey(addr))
+	if len(code) == 0 {
+		return 0, ErrContractNotFound
+	}
+	_, gasUsed, err := w.run(caller, addr, code, input, callExport, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return gasUsed, nil
+}
+
+func (w *WasmVM) contractStore(addr loom.Address) kvStore {
+	return w.state.WithPrefix(loom.DataPrefix(addr))
+}
+
+type nestedCallCtxKey struct{}
+
+// nestedCall is passed to contracts called by WASM contracts via the state context, so that the
+// call depth can be tracked.
+type nestedCall struct {
+	depth int
+}
+
+// run executes the given function exported by the contract, returns the output & the amount of
+// gas used by the contract. If dryRun is true changes to the contract storage are discarded.
+func (w *WasmVM) run(
+	caller, addr loom.Address, code, input []byte, export string, readOnly, dryRun bool,
+) ([]byte, uint64, error) {
+	depth := 0
+	if ctx := w.state.Context(); ctx != nil {
+		if parent, ok := ctx.Value(nestedCallCtxKey{}).(*nestedCall); ok {
+			depth = parent.depth
+		}
+	}
+	// When a metered contract calls a WASM contract the gas used by the WASM contract is charged to
+	// the caller, so the WASM contract can't use more gas than the caller has left.
+	gasLimit := w.gasLimit
+	gasMeter := vm.GasMeterFromContext(w.state.Context())
+	if gasMeter != nil {
+		if remaining := gasMeter.GasLimit() - gasMeter.GasUsed(); remaining < gasLimit {
+			gasLimit = remaining
+		}
+	}
+
+	var store kvStore = w.contractStore(addr)
+	if dryRun {
+		store = newOverlayStore(store)
+	}
+	host := &hostEnv{
+		vm:       w,
+		caller:   caller,
+		address:  addr,
+		input:    input,
+		readOnly: readOnly,
+		dryRun:   dryRun,
+		store:    store,
+		depth:    depth,
+	}
+	cfg := vmConfig
+	cfg.GasLimit = gasLimit
+	instance, err := exec.NewVirtualMachine(code, cfg, &hostResolver{env: host}, gasPolicy)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to load WASM contract")
+	}
+
+	entryID, ok := instance.GetFunctionExport(export)
+	if !ok {
+		if export == initExport {
+			return nil, 0, nil
+		}
+		return nil, 0, errors.Errorf("WASM contract doesn't export %s function", export)
+	}
+
+	status, err := instance.Run(entryID)
+	gasUsed := instance.Gas
+	if gasMeter != nil {
+		// If the contract ran out of gas this uses up all the gas left in the meter.
+		gasMeter.ConsumeGas(gasUsed)
+	}
+	if gasUsed > gasLimit {
+		return nil, gasUsed, ErrOutOfGas
+	}
+	if host.err != nil {
+		return nil, gasUsed, host.err
+	}
+	if err != nil {
+		return nil, gasUsed, errors.Wrap(err, "WASM contract execution failed")
+	}
+	if status != 0 {
+		return nil, gasUsed, errors.Errorf("WASM contract returned error code %d: %s", status, host.output)
+	}
+	return host.output, gasUsed, nil
+}
+
+// withNestedCall returns a copy of the given state that carries the info needed to track nested
+// contract calls, and the gas meter that should be charged for the gas used by the called contract.
+func withNestedCall(state loomchain.State, call *nestedCall, gasMeter *vm.GasMeter) loomchain.State {
+	ctx := state.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, nestedCallCtxKey{}, call)
+	return state.WithContext(vm.WithGasMeter(ctx, gasMeter))
+}
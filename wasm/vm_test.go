@@ -0,0 +1,123 @@
+package wasm
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/loomnetwork/go-loom"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/loomnetwork/loomchain"
+	registry "github.com/loomnetwork/loomchain/registry/factory"
+	"github.com/loomnetwork/loomchain/store"
+	"github.com/loomnetwork/loomchain/vm"
+)
+
+const (
+	// Stores the call input under the key "k" and returns it, if the input is empty returns the value
+	// currently stored under "k" instead.
+	storeModule = "0061736d01000000011d056000017f60017f0060027f7f0060047f7f7f7f017f60047f7f7f7f0002570503656e760e" +
+		"6c6f6f6d5f696e7075745f6c656e000003656e760a6c6f6f6d5f696e707574000103656e760b6c6f6f6d5f7265747572" +
+		"6e000203656e76086c6f6f6d5f676574000303656e76086c6f6f6d5f7365740004030201000503010001071102066d65" +
+		"6d6f727902000463616c6c00050a3a013801027f100022004504404180084101410041e8071003210141002001100205" +
+		"4100100141800841014100200010044100200010020b41000b0b0801004180080b016b"
+	// Loops forever.
+	loopModule = "0061736d010000000105016000017f030201000503010001071102066d656d6f727902000463616c6c00000a0b0109" +
+		"0003400c000b41000b"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func newTestWasmVM(t *testing.T) (*WasmVM, loomchain.State) {
+	state := loomchain.NewStoreState(
+		context.Background(), store.NewMemStore(), abci.Header{ChainID: "default", Height: 1}, nil, nil,
+	)
+	createRegistry, err := registry.NewRegistryFactory(registry.LatestRegistryVersion)
+	require.NoError(t, err)
+	manager := vm.NewManager()
+	manager.Register(vm.VMType_WASM, func(state loomchain.State) (vm.VM, error) {
+		return NewWasmVM(state, createRegistry(state), nil, manager.InitVM), nil
+	})
+	return NewWasmVM(state, createRegistry(state), nil, manager.InitVM), state
+}
+
+func TestWasmVMCallAndStaticCall(t *testing.T) {
+	wvm, _ := newTestWasmVM(t)
+	caller := loom.MustParseAddress("default:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+
+	_, _, err := wvm.Create(caller, []byte("not a wasm module"), nil)
+	require.Error(t, err)
+
+	_, contractAddr, err := wvm.Create(caller, mustDecodeHex(t, storeModule), nil)
+	require.NoError(t, err)
+	code, err := wvm.GetCode(contractAddr)
+	require.NoError(t, err)
+	require.Equal(t, mustDecodeHex(t, storeModule), code)
+
+	ret, err := wvm.Call(caller, contractAddr, []byte("hello"), nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), ret)
+	value, err := wvm.GetStorageAt(contractAddr, []byte("k"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), value)
+
+	ret, err = wvm.StaticCall(caller, contractAddr, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), ret)
+
+	// storage writes aren't allowed in static calls
+	_, err = wvm.StaticCall(caller, contractAddr, []byte("bye"))
+	require.Equal(t, errVolatileCall, err)
+
+	// gas estimation shouldn't modify the contract storage
+	gas, err := wvm.EstimateGas(caller, contractAddr, []byte("bye"), nil, 0)
+	require.NoError(t, err)
+	require.True(t, gas > storageWriteGas)
+	ret, err = wvm.StaticCall(caller, contractAddr, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), ret)
+
+	_, err = wvm.Call(caller, loom.RootAddress("default"), []byte("hello"), nil)
+	require.Equal(t, ErrContractNotFound, err)
+}
+
+func TestWasmVMGasLimit(t *testing.T) {
+	wvm, _ := newTestWasmVM(t)
+	wvm.gasLimit = 10000
+	caller := loom.MustParseAddress("default:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+
+	_, contractAddr, err := wvm.Create(caller, mustDecodeHex(t, loopModule), nil)
+	require.NoError(t, err)
+	_, err = wvm.Call(caller, contractAddr, nil, nil)
+	require.Equal(t, ErrOutOfGas, err)
+}
+
+func TestWasmVMChargesCallerGasMeter(t *testing.T) {
+	wvm, state := newTestWasmVM(t)
+	caller := loom.MustParseAddress("default:0xb16a379ec18d4093666f8f38b11a3071c920207d")
+
+	_, contractAddr, err := wvm.Create(caller, mustDecodeHex(t, storeModule), nil)
+	require.NoError(t, err)
+
+	// contracts called by metered contracts charge the caller's gas meter
+	gasMeter := vm.NewGasMeter(1000000)
+	meteredState := state.WithContext(vm.WithGasMeter(context.Background(), gasMeter))
+	meteredVM := NewWasmVM(meteredState, wvm.registry, nil, wvm.createVM)
+	_, err = meteredVM.Call(caller, contractAddr, []byte("hello"), nil)
+	require.NoError(t, err)
+	require.True(t, gasMeter.GasUsed() > storageWriteGas)
+
+	// and can't use more gas than the caller has left
+	gasMeter = vm.NewGasMeter(gasMeter.GasUsed() - 1)
+	meteredState = state.WithContext(vm.WithGasMeter(context.Background(), gasMeter))
+	meteredVM = NewWasmVM(meteredState, wvm.registry, nil, wvm.createVM)
+	_, err = meteredVM.Call(caller, contractAddr, []byte("hello"), nil)
+	require.Equal(t, ErrOutOfGas, err)
+	require.True(t, gasMeter.IsOutOfGas())
+}